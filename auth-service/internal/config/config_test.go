@@ -0,0 +1,81 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// validConfig konfigurasi default yang lolos Validate
+func validConfig(t *testing.T) *Config {
+	t.Helper()
+	cfg := Default()
+	cfg.JWT.Secret = "test-secret-that-is-long-enough-for-hs256"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("default config invalid: %v", err)
+	}
+	return cfg
+}
+
+func TestWildcardOrigins(t *testing.T) {
+	tests := []struct {
+		origin string
+		valid  bool
+	}{
+		{"https://*.example.com", true},
+		{"http://*.example.com:8080", true},
+		{"https://app.example.com", true},
+		{"https://*example.com", false},
+		{"https://app.example.*", false},
+		{"https://app.*.example.com", false},
+		{"https://*.com", false},
+		{"https://*.*.example.com", false},
+	}
+	for _, tt := range tests {
+		cfg := validConfig(t)
+		cfg.CORS.AllowOrigins = []string{tt.origin}
+		err := cfg.Validate()
+		if got := err == nil || !strings.Contains(err.Error(), "allow_origins"); got != tt.valid {
+			t.Errorf("origin %q: Validate() = %v, want valid %v", tt.origin, err, tt.valid)
+		}
+	}
+}
+
+func TestRedactDSN(t *testing.T) {
+	tests := []struct {
+		dsn  string
+		want string
+	}{
+		{"postgres://app:s3cret@db:5432/auth", "postgres://app:xxxxx@db:5432/auth"},
+		{"postgres://db/auth?sslmode=disable&password=s3cret", "postgres://db/auth?sslmode=disable&password=****"},
+		{"host=db user=app password=s3cret dbname=auth", "host=db user=app password=**** dbname=auth"},
+		{"host=db password = 's3 \\'cret' dbname=auth", "host=db password=**** dbname=auth"},
+		{"password=s3cret host=db", "password=**** host=db"},
+		{"app:s3cret@tcp(db:3306)/auth?parseTime=true", "app:****@tcp(db:3306)/auth?parseTime=true"},
+		{"file:auth.db?cache=shared", "file:auth.db?cache=shared"},
+	}
+	for _, tt := range tests {
+		if got := RedactDSN(tt.dsn); got != tt.want {
+			t.Errorf("RedactDSN(%q) = %q, want %q", tt.dsn, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigBoolFlags(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-that-is-long-enough-for-hs256")
+	t.Setenv("METRICS_ENABLED", "")
+	t.Setenv("COOKIE_SECURE", "")
+
+	cfg, err := LoadConfig([]string{"-metrics", "-cookie-secure=false", "-cors-allow-credentials"})
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if !cfg.Metrics.Enabled {
+		t.Error("-metrics without a value did not enable metrics")
+	}
+	if cfg.Cookies.Secure {
+		t.Error("-cookie-secure=false did not disable the secure attribute")
+	}
+	if cfg.CORS.AllowCredentials == nil || !*cfg.CORS.AllowCredentials {
+		t.Errorf("-cors-allow-credentials = %v, want true", cfg.CORS.AllowCredentials)
+	}
+}
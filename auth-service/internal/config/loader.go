@@ -0,0 +1,391 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// setting menghubungkan satu nilai konfigurasi dengan environment variable
+// dan flag command-line yang dapat menimpanya
+type setting struct {
+    env    string
+    flag   string
+    usage  string
+    target setter
+}
+
+// setter menerapkan nilai string dari env atau flag ke field Config
+type setter interface {
+    set(c *Config, value string) error
+}
+
+// setFunc setter untuk nilai selain boolean
+type setFunc func(c *Config, value string) error
+
+func (f setFunc) set(c *Config, value string) error { return f(c, value) }
+
+// boolSetFunc setter untuk nilai boolean; flag-nya didaftarkan dengan fs.Bool
+// sehingga "-metrics" saja sudah berarti true
+type boolSetFunc func(c *Config, value string) error
+
+func (f boolSetFunc) set(c *Config, value string) error { return f(c, value) }
+
+// settings daftar semua nilai yang dapat ditimpa lewat env atau flag.
+// Secret sengaja tidak memiliki flag agar tidak muncul di daftar proses.
+var settings = []setting{
+    {"APP_ENV", "env", "application environment (development, production, test)", setString(func(c *Config) *string { return &c.Env })},
+    {"APP_PORT", "port", "HTTP port to listen on", setString(func(c *Config) *string { return &c.Server.Port })},
+    {"SERVER_READ_TIMEOUT", "read-timeout", "maximum duration for reading an entire request", setDuration(func(c *Config) *time.Duration { return &c.Server.ReadTimeout })},
+    {"SERVER_READ_HEADER_TIMEOUT", "read-header-timeout", "maximum duration for reading request headers", setDuration(func(c *Config) *time.Duration { return &c.Server.ReadHeaderTimeout })},
+    {"SERVER_WRITE_TIMEOUT", "write-timeout", "maximum duration before timing out writes of the response", setDuration(func(c *Config) *time.Duration { return &c.Server.WriteTimeout })},
+    {"SERVER_IDLE_TIMEOUT", "idle-timeout", "maximum time to wait for the next request on keep-alive connections", setDuration(func(c *Config) *time.Duration { return &c.Server.IdleTimeout })},
+    {"SERVER_MAX_HEADER_BYTES", "max-header-bytes", "maximum size of request headers in bytes", setInt(func(c *Config) *int { return &c.Server.MaxHeaderBytes })},
+    {"SERVER_MAX_BODY_BYTES", "max-body-bytes", "maximum size of request bodies in bytes", setInt64(func(c *Config) *int64 { return &c.Server.MaxBodyBytes })},
+    {"SERVER_DRAIN_PERIOD", "drain-period", "delay between receiving a shutdown signal and closing the listener", setDuration(func(c *Config) *time.Duration { return &c.Server.DrainPeriod })},
+    {"SERVER_SHUTDOWN_TIMEOUT", "shutdown-timeout", "maximum time to wait for in-flight requests during shutdown", setDuration(func(c *Config) *time.Duration { return &c.Server.ShutdownTimeout })},
+    {"SERVER_HEALTH_CHECK_TIMEOUT", "health-check-timeout", "timeout of each dependency check performed by /readyz", setDuration(func(c *Config) *time.Duration { return &c.Server.HealthCheckTimeout })},
+
+    {"GRPC_ENABLED", "grpc", "serve the gRPC API on grpc.port", setBool(func(c *Config) *bool { return &c.GRPC.Enabled })},
+    {"GRPC_PORT", "grpc-port", "gRPC port to listen on", setString(func(c *Config) *string { return &c.GRPC.Port })},
+    {"EXT_AUTHZ_ENABLED", "ext-authz", "serve the Envoy ext_authz v3 Check API on the gRPC server", setBool(func(c *Config) *bool { return &c.ExtAuthz.Enabled })},
+
+    {"TLS_ENABLED", "tls", "serve HTTPS using tls.cert_file and tls.key_file", setBool(func(c *Config) *bool { return &c.TLS.Enabled })},
+    {"TLS_CERT_FILE", "tls-cert-file", "path to the TLS certificate (PEM)", setString(func(c *Config) *string { return &c.TLS.CertFile })},
+    {"TLS_KEY_FILE", "tls-key-file", "path to the TLS private key (PEM)", setString(func(c *Config) *string { return &c.TLS.KeyFile })},
+    {"TLS_CLIENT_AUTH", "tls-client-auth", "client certificate mode (none, optional, require)", setString(func(c *Config) *string { return &c.TLS.ClientAuth })},
+    {"TLS_CLIENT_CA_FILE", "tls-client-ca-file", "path to the CA bundle used to verify client certificates", setString(func(c *Config) *string { return &c.TLS.ClientCAFile })},
+
+    {"LOG_LEVEL", "log-level", "minimum log level (debug, info, warn, error)", setString(func(c *Config) *string { return &c.Logging.Level })},
+    {"LOG_FORMAT", "log-format", "log output format (json, text)", setString(func(c *Config) *string { return &c.Logging.Format })},
+
+    {"METRICS_ENABLED", "metrics", "expose Prometheus metrics", setBool(func(c *Config) *bool { return &c.Metrics.Enabled })},
+    {"METRICS_PATH", "metrics-path", "HTTP path of the metrics endpoint", setString(func(c *Config) *string { return &c.Metrics.Path })},
+    {"METRICS_PORT", "metrics-port", "internal port for the metrics endpoint, separate from the API port", setString(func(c *Config) *string { return &c.Metrics.Port })},
+
+    {"TRACING_ENABLED", "tracing", "enable OpenTelemetry tracing", setBool(func(c *Config) *bool { return &c.Tracing.Enabled })},
+    {"TRACING_EXPORTER", "tracing-exporter", "trace exporter (otlp, stdout); OTLP endpoint is read from OTEL_EXPORTER_OTLP_ENDPOINT", setString(func(c *Config) *string { return &c.Tracing.Exporter })},
+    {"OTEL_SERVICE_NAME", "", "", setString(func(c *Config) *string { return &c.Tracing.ServiceName })},
+    {"TRACING_SAMPLE_RATIO", "tracing-sample-ratio", "fraction of traces to sample (0..1)", setFloat(func(c *Config) *float64 { return &c.Tracing.SampleRatio })},
+
+    {"DB_DRIVER", "db-driver", "database backend (mysql, postgres, sqlite)", setString(func(c *Config) *string { return &c.Database.Driver })},
+    {"DB_DSN", "", "", setSecret(func(c *Config) (*string, *string) { return &c.Database.DSN, &c.Database.DSNFile })},
+    {"DB_DSN_FILE", "", "", setSecretFile(func(c *Config) (*string, *string) { return &c.Database.DSN, &c.Database.DSNFile })},
+    {"DB_MAX_OPEN_CONNS", "db-max-open-conns", "maximum number of open database connections", setInt(func(c *Config) *int { return &c.Database.MaxOpenConns })},
+    {"DB_MAX_IDLE_CONNS", "db-max-idle-conns", "maximum number of idle database connections", setInt(func(c *Config) *int { return &c.Database.MaxIdleConns })},
+    {"DB_QUERY_TIMEOUT", "db-query-timeout", "timeout of each database query (e.g. 5s, 0 disables)", setDuration(func(c *Config) *time.Duration { return &c.Database.QueryTimeout })},
+    {"DB_AUTO_MIGRATE", "auto-migrate", "apply pending schema migrations on startup", setBool(func(c *Config) *bool { return &c.Database.AutoMigrate })},
+    {"DB_CONN_MAX_LIFETIME", "db-conn-max-lifetime", "maximum lifetime of a database connection (e.g. 5m)", setDuration(func(c *Config) *time.Duration { return &c.Database.ConnMaxLifetime })},
+
+    {"JWT_ALGORITHM", "jwt-algorithm", "JWT signing algorithm (HS256, RS256, EdDSA)", setString(func(c *Config) *string { return &c.JWT.Algorithm })},
+    {"JWT_PRIVATE_KEY_FILE", "jwt-private-key-file", "path to the PEM private key used by RS256/EdDSA", setString(func(c *Config) *string { return &c.JWT.PrivateKeyFile })},
+    {"JWT_SECRET", "", "", setSecret(func(c *Config) (*string, *string) { return &c.JWT.Secret, &c.JWT.SecretFile })},
+    {"JWT_SECRET_FILE", "", "", setSecretFile(func(c *Config) (*string, *string) { return &c.JWT.Secret, &c.JWT.SecretFile })},
+    {"JWT_ISSUER", "jwt-issuer", "JWT issuer claim", setString(func(c *Config) *string { return &c.JWT.Issuer })},
+    {"JWT_TTL_MINUTES", "", "", setMinutes(func(c *Config) *time.Duration { return &c.JWT.TTL })},
+    {"", "jwt-ttl", "JWT lifetime (e.g. 60m)", setDuration(func(c *Config) *time.Duration { return &c.JWT.TTL })},
+
+    {"CORS_ALLOW_ORIGINS", "cors-allow-origins", "comma separated list of allowed CORS origins (supports https://*.example.com)", setList(func(c *Config) *[]string { return &c.CORS.AllowOrigins })},
+    {"CORS_ALLOW_METHODS", "cors-allow-methods", "comma separated list of allowed CORS methods", setList(func(c *Config) *[]string { return &c.CORS.AllowMethods })},
+    {"CORS_ALLOW_HEADERS", "cors-allow-headers", "comma separated list of allowed CORS request headers", setList(func(c *Config) *[]string { return &c.CORS.AllowHeaders })},
+    {"CORS_ALLOW_CREDENTIALS", "cors-allow-credentials", "allow credentials (cookies) on CORS requests", setBoolPtr(func(c *Config) **bool { return &c.CORS.AllowCredentials })},
+    {"CORS_MAX_AGE", "cors-max-age", "how long browsers may cache preflight results (e.g. 12h)", setDuration(func(c *Config) *time.Duration { return &c.CORS.MaxAge })},
+
+    {"COOKIE_DOMAIN", "cookie-domain", "domain attribute of the JWT cookie", setString(func(c *Config) *string { return &c.Cookies.Domain })},
+    {"COOKIE_SECURE", "cookie-secure", "set the Secure attribute on the JWT cookie", setBool(func(c *Config) *bool { return &c.Cookies.Secure })},
+    {"COOKIE_SAME_SITE", "cookie-same-site", "SameSite attribute of the JWT cookie (lax, strict, none)", setString(func(c *Config) *string { return &c.Cookies.SameSite })},
+
+    {"SECURITY_ROLES", "roles", "comma separated list of allowed user roles", setList(func(c *Config) *[]string { return &c.Security.Roles })},
+    {"SECURITY_ADMIN_ROLES", "admin-roles", "comma separated list of roles allowed to use admin endpoints", setList(func(c *Config) *[]string { return &c.Security.AdminRoles })},
+    {"SECURITY_DEFAULT_ROLE", "default-role", "role assigned to newly registered users", setString(func(c *Config) *string { return &c.Security.DefaultRole })},
+
+    {"API_KEYS_ENABLED", "api-keys", "allow users to create API keys (Authorization: ApiKey ...)", setBool(func(c *Config) *bool { return &c.APIKeys.Enabled })},
+    {"API_KEYS_MAX_PER_USER", "api-keys-max-per-user", "maximum number of API keys per user (0 = unlimited)", setInt(func(c *Config) *int { return &c.APIKeys.MaxPerUser })},
+    {"API_KEYS_MAX_TTL", "api-keys-max-ttl", "maximum API key lifetime, also the default expiry (e.g. 8760h, 0 = unlimited)", setDuration(func(c *Config) *time.Duration { return &c.APIKeys.MaxTTL })},
+    {"SERVICE_ACCOUNTS_ENABLED", "service-accounts", "enable service accounts and the client_credentials grant", setBool(func(c *Config) *bool { return &c.ServiceAccounts.Enabled })},
+    {"SERVICE_ACCOUNTS_TOKEN_TTL", "service-accounts-token-ttl", "lifetime of client_credentials access tokens (e.g. 1h)", setDuration(func(c *Config) *time.Duration { return &c.ServiceAccounts.TokenTTL })},
+    {"IMPERSONATION_ENABLED", "impersonation", "allow admins to impersonate users", setBool(func(c *Config) *bool { return &c.Impersonation.Enabled })},
+    {"IMPERSONATION_TOKEN_TTL", "impersonation-token-ttl", "lifetime of impersonation tokens (e.g. 15m)", setDuration(func(c *Config) *time.Duration { return &c.Impersonation.TokenTTL })},
+    {"TOKEN_EXCHANGE_ENABLED", "token-exchange", "enable the token-exchange grant (RFC 8693) on /api/oauth/token", setBool(func(c *Config) *bool { return &c.TokenExchange.Enabled })},
+    {"TOKEN_EXCHANGE_TOKEN_TTL", "token-exchange-token-ttl", "maximum lifetime of exchanged tokens (e.g. 5m)", setDuration(func(c *Config) *time.Duration { return &c.TokenExchange.TokenTTL })},
+
+    {"FORWARD_AUTH_LOGIN_URL", "forward-auth-login-url", "login page that unauthenticated /auth/forward requests are redirected to", setString(func(c *Config) *string { return &c.ForwardAuth.LoginURL })},
+
+    {"I18N_DEFAULT_LOCALE", "default-locale", "language of API messages when neither Accept-Language nor the user preference matches (en, id)", setString(func(c *Config) *string { return &c.I18n.DefaultLocale })},
+}
+
+// LoadConfig memuat konfigurasi secara berlapis dengan urutan prioritas:
+// default < file YAML (-config atau CONFIG_FILE) < environment variable
+// (termasuk .env) < flag command-line. Secret dapat dibaca dari file
+// (*_FILE, misalnya Docker/Kubernetes secret mount). Semua kesalahan
+// konfigurasi dikembalikan sekaligus dalam satu error.
+func LoadConfig(args []string) (*Config, error) {
+    // Load file .env
+    if err := godotenv.Load(); err != nil {
+        slog.Warn(".env file not found, using system environment variables")
+    }
+
+    fs, configPath, flagValues := newFlagSet()
+    if err := fs.Parse(args); err != nil {
+        return nil, err
+    }
+    if fs.NArg() > 0 {
+        return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+    }
+
+    cfg := Default()
+    var errs []error
+
+    // Lapisan 1: file konfigurasi
+    path := *configPath
+    if path == "" {
+        path = os.Getenv("CONFIG_FILE")
+    }
+    if path != "" {
+        if err := cfg.loadFile(path); err != nil {
+            return nil, err
+        }
+    }
+
+    // Lapisan 2: environment variable
+    for _, s := range settings {
+        if s.env == "" {
+            continue
+        }
+        if value := os.Getenv(s.env); value != "" {
+            if err := s.target.set(cfg, value); err != nil {
+                errs = append(errs, fmt.Errorf("%s: %v", s.env, err))
+            }
+        }
+    }
+    for _, key := range []string{"DB_DSN", "JWT_SECRET"} {
+        if os.Getenv(key) != "" && os.Getenv(key+"_FILE") != "" {
+            errs = append(errs, fmt.Errorf("%s: both %s and %s_FILE are set, use only one", key, key, key))
+        }
+    }
+
+    // Lapisan 3: flag command-line (hanya flag yang diset secara eksplisit)
+    fs.Visit(func(f *flag.Flag) {
+        s, ok := flagValues[f.Name]
+        if !ok {
+            return
+        }
+        if err := s.target.set(cfg, f.Value.String()); err != nil {
+            errs = append(errs, fmt.Errorf("-%s: %v", f.Name, err))
+        }
+    })
+
+    // Secret dari file dan fallback default
+    if err := resolveSecret("database.dsn", &cfg.Database.DSN, cfg.Database.DSNFile); err != nil {
+        errs = append(errs, err)
+    }
+    if err := resolveSecret("jwt.secret", &cfg.JWT.Secret, cfg.JWT.SecretFile); err != nil {
+        errs = append(errs, err)
+    }
+    if cfg.Database.DSN == "" {
+        cfg.Database.DSN = defaultDBDSNs[cfg.Database.Driver]
+        cfg.dbDSNIsDefault = true
+    }
+    if cfg.JWT.Secret == "" {
+        cfg.JWT.Secret = defaultJWTSecret
+        cfg.jwtSecretIsDefault = true
+    }
+    cfg.Env = strings.ToLower(cfg.Env)
+
+    if err := cfg.Validate(); err != nil {
+        errs = append(errs, err)
+    }
+    if len(errs) > 0 {
+        return nil, errors.Join(errs...)
+    }
+
+    if !cfg.IsProduction() && cfg.jwtSecretIsDefault && cfg.JWT.UsesHMAC() {
+        slog.Warn("JWT_SECRET is not set, using insecure fallback secret (development only)")
+    }
+
+    return cfg, nil
+}
+
+// newFlagSet membuat flag set untuk semua setting yang memiliki flag
+func newFlagSet() (*flag.FlagSet, *string, map[string]setting) {
+    fs := flag.NewFlagSet("auth-service", flag.ContinueOnError)
+    configPath := fs.String("config", "", "path to YAML config file (overrides CONFIG_FILE)")
+
+    flagValues := make(map[string]setting)
+    for _, s := range settings {
+        if s.flag == "" {
+            continue
+        }
+        if _, ok := s.target.(boolSetFunc); ok {
+            fs.Bool(s.flag, false, s.usage)
+        } else {
+            fs.String(s.flag, "", s.usage)
+        }
+        flagValues[s.flag] = s
+    }
+    return fs, configPath, flagValues
+}
+
+// loadFile membaca file konfigurasi YAML ke atas nilai default.
+// Key yang tidak dikenal dianggap kesalahan agar salah ketik tidak diabaikan.
+func (c *Config) loadFile(path string) error {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return fmt.Errorf("failed to read config file: %v", err)
+    }
+
+    decoder := yaml.NewDecoder(bytes.NewReader(data))
+    decoder.KnownFields(true)
+    if err := decoder.Decode(c); err != nil && err != io.EOF {
+        return fmt.Errorf("failed to parse config file %s: %v", path, err)
+    }
+    return nil
+}
+
+// resolveSecret membaca secret dari file jika path diset
+func resolveSecret(name string, value *string, path string) error {
+    if path == "" {
+        return nil
+    }
+    if *value != "" {
+        return fmt.Errorf("%s: both %s and %s_file are set, use only one", name, name, name)
+    }
+
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return fmt.Errorf("%s_file: failed to read secret file: %v", name, err)
+    }
+
+    secret := strings.TrimRight(string(data), "\r\n")
+    if secret == "" {
+        return fmt.Errorf("%s_file: secret file %s is empty", name, path)
+    }
+    *value = secret
+    return nil
+}
+
+func setString(field func(c *Config) *string) setFunc {
+    return func(c *Config, value string) error {
+        *field(c) = value
+        return nil
+    }
+}
+
+func setInt(field func(c *Config) *int) setFunc {
+    return func(c *Config, value string) error {
+        n, err := strconv.Atoi(value)
+        if err != nil {
+            return fmt.Errorf("invalid integer %q", value)
+        }
+        *field(c) = n
+        return nil
+    }
+}
+
+func setInt64(field func(c *Config) *int64) setFunc {
+    return func(c *Config, value string) error {
+        n, err := strconv.ParseInt(value, 10, 64)
+        if err != nil {
+            return fmt.Errorf("invalid integer %q", value)
+        }
+        *field(c) = n
+        return nil
+    }
+}
+
+func setFloat(field func(c *Config) *float64) setFunc {
+    return func(c *Config, value string) error {
+        f, err := strconv.ParseFloat(value, 64)
+        if err != nil {
+            return fmt.Errorf("invalid number %q", value)
+        }
+        *field(c) = f
+        return nil
+    }
+}
+
+func setBool(field func(c *Config) *bool) boolSetFunc {
+    return func(c *Config, value string) error {
+        b, err := strconv.ParseBool(value)
+        if err != nil {
+            return fmt.Errorf("invalid boolean %q", value)
+        }
+        *field(c) = b
+        return nil
+    }
+}
+
+func setBoolPtr(field func(c *Config) **bool) boolSetFunc {
+    return func(c *Config, value string) error {
+        b, err := strconv.ParseBool(value)
+        if err != nil {
+            return fmt.Errorf("invalid boolean %q", value)
+        }
+        *field(c) = &b
+        return nil
+    }
+}
+
+func setDuration(field func(c *Config) *time.Duration) setFunc {
+    return func(c *Config, value string) error {
+        d, err := time.ParseDuration(value)
+        if err != nil {
+            return fmt.Errorf("invalid duration %q", value)
+        }
+        *field(c) = d
+        return nil
+    }
+}
+
+// setMinutes untuk nilai durasi lama yang dinyatakan dalam menit (JWT_TTL_MINUTES)
+func setMinutes(field func(c *Config) *time.Duration) setFunc {
+    return func(c *Config, value string) error {
+        n, err := strconv.Atoi(value)
+        if err != nil {
+            return fmt.Errorf("invalid integer %q", value)
+        }
+        *field(c) = time.Duration(n) * time.Minute
+        return nil
+    }
+}
+
+func setList(field func(c *Config) *[]string) setFunc {
+    return func(c *Config, value string) error {
+        var items []string
+        for _, item := range strings.Split(value, ",") {
+            if item = strings.TrimSpace(item); item != "" {
+                items = append(items, item)
+            }
+        }
+        *field(c) = items
+        return nil
+    }
+}
+
+// setSecret menimpa secret dan mengosongkan path file dari lapisan sebelumnya
+func setSecret(field func(c *Config) (*string, *string)) setFunc {
+    return func(c *Config, value string) error {
+        secret, file := field(c)
+        *secret, *file = value, ""
+        return nil
+    }
+}
+
+// setSecretFile menimpa path file secret dan mengosongkan secret dari lapisan sebelumnya
+func setSecretFile(field func(c *Config) (*string, *string)) setFunc {
+    return func(c *Config, value string) error {
+        secret, file := field(c)
+        *secret, *file = "", value
+        return nil
+    }
+}
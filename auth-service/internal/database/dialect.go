@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Nama driver yang didukung (nilai database.driver)
+const (
+    MySQL    = "mysql"
+    Postgres = "postgres"
+    SQLite   = "sqlite"
+)
+
+// Dialect menyimpan perbedaan perilaku antar database yang perlu diketahui
+// repository dan migration
+type Dialect struct {
+    // Name nama dialect, juga nama direktori migration
+    Name string
+    // DriverName nama driver database/sql
+    DriverName string
+    // System nilai atribut db.system OpenTelemetry
+    System string
+}
+
+var dialects = map[string]Dialect{
+    MySQL:    {Name: MySQL, DriverName: "mysql", System: "mysql"},
+    Postgres: {Name: Postgres, DriverName: "pgx", System: "postgresql"},
+    SQLite:   {Name: SQLite, DriverName: "sqlite", System: "sqlite"},
+}
+
+// DialectFor mengembalikan dialect untuk nama driver dari konfigurasi
+func DialectFor(driver string) (Dialect, error) {
+    d, ok := dialects[driver]
+    if !ok {
+        return Dialect{}, fmt.Errorf("unsupported database driver %q (expected mysql, postgres or sqlite)", driver)
+    }
+    return d, nil
+}
+
+// Rebind mengubah placeholder "?" menjadi "$1", "$2", ... untuk PostgreSQL.
+// "?" di dalam literal string ('...') atau identifier ("...") tidak diubah.
+func (d Dialect) Rebind(query string) string {
+    if d.Name != Postgres {
+        return query
+    }
+
+    var b strings.Builder
+    n := 0
+    var quote rune
+    for _, r := range query {
+        switch {
+        case quote != 0:
+            // Escape '' atau "" cukup diperlakukan sebagai dua quote berurutan
+            if r == quote {
+                quote = 0
+            }
+        case r == '\'' || r == '"':
+            quote = r
+        case r == '?':
+            n++
+            b.WriteString("$" + strconv.Itoa(n))
+            continue
+        }
+        b.WriteRune(r)
+    }
+    return b.String()
+}
+
+// InsertReturningID menjalankan INSERT dan mengembalikan ID baris baru.
+// PostgreSQL tidak mendukung LastInsertId sehingga memakai RETURNING id.
+func (d Dialect) InsertReturningID(ctx context.Context, db *sql.DB, query string, args ...interface{}) (int64, error) {
+    if d.Name == Postgres {
+        var id int64
+        err := db.QueryRowContext(ctx, d.Rebind(query)+" RETURNING id", args...).Scan(&id)
+        return id, err
+    }
+
+    result, err := db.ExecContext(ctx, query, args...)
+    if err != nil {
+        return 0, err
+    }
+    return result.LastInsertId()
+}
+
+// IsUniqueViolation memeriksa apakah err disebabkan pelanggaran unique constraint
+func (d Dialect) IsUniqueViolation(err error) bool {
+    var mysqlErr *mysql.MySQLError
+    if errors.As(err, &mysqlErr) {
+        return mysqlErr.Number == 1062 // ER_DUP_ENTRY
+    }
+    var pgErr *pgconn.PgError
+    if errors.As(err, &pgErr) {
+        return pgErr.Code == "23505" // unique_violation
+    }
+    var sqliteErr *sqlite.Error
+    if errors.As(err, &sqliteErr) {
+        return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+    }
+    return false
+}
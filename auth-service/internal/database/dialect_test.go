@@ -0,0 +1,29 @@
+package database
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"placeholders", "SELECT id FROM users WHERE email = ? AND role = ?", "SELECT id FROM users WHERE email = $1 AND role = $2"},
+		{"string literal", "SELECT 'why?' FROM users WHERE id = ?", "SELECT 'why?' FROM users WHERE id = $1"},
+		{"escaped quote", "SELECT 'it''s ?' WHERE a = ? AND b = '?'", "SELECT 'it''s ?' WHERE a = $1 AND b = '?'"},
+		{"quoted identifier", `SELECT "col?" FROM t WHERE x = ?`, `SELECT "col?" FROM t WHERE x = $1`},
+	}
+	postgres, _ := DialectFor(Postgres)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := postgres.Rebind(tt.query); got != tt.want {
+				t.Errorf("Rebind(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	mysql, _ := DialectFor(MySQL)
+	if got := mysql.Rebind("SELECT ? FROM t"); got != "SELECT ? FROM t" {
+		t.Errorf("MySQL Rebind changed the query: %q", got)
+	}
+}
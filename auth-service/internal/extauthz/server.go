@@ -0,0 +1,259 @@
+// Package extauthz mengimplementasikan Envoy external authorization v3
+// (envoy.service.auth.v3.Authorization/Check) di atas AuthService, sehingga
+// sidecar Envoy dapat mengotorisasi request tanpa logika JWT di setiap layanan.
+package extauthz
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/handler"
+	"auth-service/internal/i18n"
+	"auth-service/internal/middleware"
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+)
+
+// identityHeaders header identitas yang ditambahkan ke request upstream;
+// sama dengan header /auth/forward. Envoy memakai nama header huruf kecil.
+var identityHeaders = []string{
+    strings.ToLower(handler.HeaderUserID),
+    strings.ToLower(handler.HeaderUserEmail),
+    strings.ToLower(handler.HeaderUserRole),
+    strings.ToLower(handler.HeaderPrincipalType),
+    strings.ToLower(handler.HeaderImpersonatorID),
+}
+
+// Server implementasi authv3.AuthorizationServer
+type Server struct {
+    authv3.UnimplementedAuthorizationServer
+
+    authService   service.AuthService
+    cookieName    string
+    security      config.SecurityConfig
+    policies      []config.ExtAuthzPolicy
+    defaultLocale string
+}
+
+// NewServer membuat instance baru Server dari konfigurasi
+func NewServer(authService service.AuthService, cfg *config.Config) *Server {
+    return &Server{
+        authService:   authService,
+        cookieName:    cfg.Cookies.Name,
+        security:      cfg.Security,
+        policies:      cfg.ExtAuthz.Policies,
+        defaultLocale: cfg.I18n.DefaultLocale,
+    }
+}
+
+// Check memvalidasi token request asal (cookie JWT atau header Authorization)
+// dan memeriksa policy path-nya. Jika diizinkan, header identitas pengguna
+// ditambahkan ke request upstream; header identitas dari klien selalu
+// dihapus agar tidak dapat dipalsukan. Token ber-aud hanya diterima pada
+// policy dengan audience yang cocok. Path yang tidak dapat dinormalisasi
+// ditolak dengan 400. Jika ditolak, Envoy mengembalikan response
+// 400/401/403 application/problem+json ke klien.
+func (s *Server) Check(ctx context.Context, req *authv3.CheckRequest) (*authv3.CheckResponse, error) {
+    httpReq := req.GetAttributes().GetRequest().GetHttp()
+    headers := httpReq.GetHeaders()
+
+    locale := i18n.Negotiate(headers["accept-language"])
+    if locale == "" {
+        locale = s.defaultLocale
+    }
+
+    // Policy dicocokkan dengan path yang sudah dinormalisasi agar prefix
+    // tidak dapat dilewati dengan segmen "..", slash ganda atau encoding
+    requestPath, err := normalizePath(httpReq.GetPath())
+    if err != nil {
+        return s.deny(ctx, model.NewValidationError(err), locale, httpReq), nil
+    }
+    policy := s.match(httpReq.GetMethod(), requestPath)
+
+    tokenString, err := tokenFromHeaders(headers, s.cookieName)
+    var claims *model.JWTClaims
+    if err == nil {
+        claims, err = s.authService.ValidateToken(ctx, tokenString)
+    }
+    // Token untuk layanan lain ditolak; pada path publik request diteruskan
+    // tanpa identitas
+    if err == nil && !claims.ValidForAudience(policy.Audience) {
+        err = fmt.Errorf("%w: token audience does not include %q", model.ErrForbidden, policy.Audience)
+    }
+    if err != nil {
+        if policy.Public {
+            return allow(nil), nil
+        }
+        return s.deny(ctx, err, locale, httpReq), nil
+    }
+
+    if claims.Locale != "" && i18n.IsSupported(claims.Locale) {
+        locale = claims.Locale
+    }
+    // Role admin juga membutuhkan scope admin, sama seperti /auth/forward
+    if len(policy.Roles) > 0 && !claims.HasRequiredRole(policy.Roles, s.security.AdminRoles) {
+        return s.deny(ctx, model.ErrForbidden, locale, httpReq), nil
+    }
+    for _, permission := range policy.Permissions {
+        if !s.security.HasPermission(claims.Role, permission) || !claims.HasScope(permission) {
+            return s.deny(ctx, model.ErrForbidden, locale, httpReq), nil
+        }
+    }
+    return allow(claims), nil
+}
+
+// match mengembalikan policy pertama yang cocok dengan method dan path.
+// Tanpa policy yang cocok, request cukup terautentikasi.
+func (s *Server) match(method, path string) config.ExtAuthzPolicy {
+    for _, policy := range s.policies {
+        if len(policy.Methods) > 0 && !slices.ContainsFunc(policy.Methods, func(m string) bool { return strings.EqualFold(m, method) }) {
+            continue
+        }
+        if matchesPrefix(path, policy.PathPrefix) {
+            return policy
+        }
+    }
+    return config.ExtAuthzPolicy{}
+}
+
+// normalizePath membuang query string, men-decode percent-encoding dan
+// membersihkan path. Path dengan segmen "..", slash yang di-encode atau
+// slash ganda ditolak karena upstream dapat menafsirkannya berbeda.
+func normalizePath(rawPath string) (string, error) {
+    rawPath, _, _ = strings.Cut(rawPath, "?")
+    lower := strings.ToLower(rawPath)
+    if strings.Contains(lower, "%2f") || strings.Contains(lower, "%5c") {
+        return "", errors.New("path must not contain encoded slashes")
+    }
+    decoded, err := url.PathUnescape(rawPath)
+    if err != nil {
+        return "", fmt.Errorf("invalid path encoding: %w", err)
+    }
+    if !strings.HasPrefix(decoded, "/") {
+        return "", errors.New("path must start with \"/\"")
+    }
+    if strings.Contains(decoded, "//") {
+        return "", errors.New("path must not contain empty segments")
+    }
+    if slices.Contains(strings.Split(decoded, "/"), "..") {
+        return "", errors.New("path must not contain \"..\" segments")
+    }
+    return path.Clean(decoded), nil
+}
+
+// matchesPrefix mencocokkan prefix path per segmen
+func matchesPrefix(path, prefix string) bool {
+    prefix = strings.TrimSuffix(prefix, "/")
+    return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// allow membuat response OK; claims nil berarti request publik tanpa identitas
+func allow(claims *model.JWTClaims) *authv3.CheckResponse {
+    ok := &authv3.OkHttpResponse{}
+    if claims == nil {
+        ok.HeadersToRemove = identityHeaders
+    } else {
+        ok.Headers = []*corev3.HeaderValueOption{
+            header(identityHeaders[0], strconv.FormatInt(claims.UserID, 10)),
+            header(identityHeaders[1], claims.Email),
+            header(identityHeaders[2], claims.Role),
+            header(identityHeaders[3], claims.Principal().Type),
+        }
+        // Header impersonator palsu dari klien tidak boleh sampai ke upstream
+        if claims.IsImpersonated() {
+            ok.Headers = append(ok.Headers, header(identityHeaders[4], strconv.FormatInt(claims.Impersonator().UserID, 10)))
+        } else {
+            ok.HeadersToRemove = identityHeaders[4:]
+        }
+    }
+    return &authv3.CheckResponse{
+        Status:       &rpcstatus.Status{Code: int32(codes.OK)},
+        HttpResponse: &authv3.CheckResponse_OkResponse{OkResponse: ok},
+    }
+}
+
+// deny membuat response penolakan dengan body Problem yang sama seperti REST API
+func (s *Server) deny(ctx context.Context, err error, locale string, httpReq *authv3.AttributeContext_HttpRequest) *authv3.CheckResponse {
+    problem := middleware.NewProblem(err, locale)
+    problem.Instance, _, _ = strings.Cut(httpReq.GetPath(), "?")
+    problem.RequestID = httpReq.GetHeaders()["x-request-id"]
+
+    code := codes.Internal
+    switch problem.Status {
+    case http.StatusBadRequest:
+        code = codes.InvalidArgument
+    case http.StatusUnauthorized:
+        code = codes.Unauthenticated
+    case http.StatusForbidden:
+        code = codes.PermissionDenied
+    default:
+        slog.ErrorContext(ctx, "Authorization check failed", "error", err)
+    }
+
+    body, marshalErr := json.Marshal(problem)
+    if marshalErr != nil {
+        body = []byte(`{}`)
+    }
+    headers := []*corev3.HeaderValueOption{
+        header("content-type", middleware.ProblemContentType),
+        header("content-language", locale),
+    }
+    if problem.Status == http.StatusUnauthorized {
+        headers = append(headers, header("www-authenticate", "Bearer"))
+    }
+
+    return &authv3.CheckResponse{
+        Status: &rpcstatus.Status{Code: int32(code), Message: problem.Title},
+        HttpResponse: &authv3.CheckResponse_DeniedResponse{DeniedResponse: &authv3.DeniedHttpResponse{
+            Status:  &typev3.HttpStatus{Code: typev3.StatusCode(problem.Status)},
+            Headers: headers,
+            Body:    string(body),
+        }},
+    }
+}
+
+// header membuat header yang menimpa nilai yang sudah ada
+func header(key, value string) *corev3.HeaderValueOption {
+    return &corev3.HeaderValueOption{
+        Header:       &corev3.HeaderValue{Key: key, Value: value},
+        AppendAction: corev3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+    }
+}
+
+// tokenFromHeaders mengambil token dari cookie JWT atau header Authorization
+// dengan urutan yang sama seperti middleware.TokenFromRequest
+func tokenFromHeaders(headers map[string]string, cookieName string) (string, error) {
+    if cookieHeader := headers["cookie"]; cookieHeader != "" {
+        req := http.Request{Header: http.Header{"Cookie": {cookieHeader}}}
+        if cookie, err := req.Cookie(cookieName); err == nil {
+            return cookie.Value, nil
+        }
+    }
+
+    authHeader := headers["authorization"]
+    if authHeader == "" {
+        return "", model.ErrTokenMissing
+    }
+
+    // Format: Bearer <token>
+    parts := strings.Split(authHeader, " ")
+    if len(parts) != 2 || parts[0] != "Bearer" {
+        return "", fmt.Errorf("%w: authorization header format must be Bearer {token}", model.ErrTokenInvalid)
+    }
+    return parts[1], nil
+}
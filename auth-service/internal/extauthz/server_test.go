@@ -0,0 +1,250 @@
+package extauthz
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/i18n"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"auth-service/internal/service"
+	"auth-service/internal/signing"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+)
+
+// testSecret secret HMAC yang dipakai newTestServer
+const testSecret = "test-secret-that-is-long-enough-for-hs256"
+
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	os.Exit(m.Run())
+}
+
+// newTestServer membuat Server dengan repository di memori dan mengembalikan
+// token pengguna biasa dan admin
+func newTestServer(t *testing.T) (*Server, string, string) {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.JWT.Secret = testSecret
+	cfg.Security.Permissions = map[string][]string{"audit:read": {"admin"}}
+	cfg.ExtAuthz.Policies = []config.ExtAuthzPolicy{
+		{PathPrefix: "/public", Public: true},
+		{PathPrefix: "/reports", Methods: []string{"DELETE"}, Roles: []string{"admin"}},
+		{PathPrefix: "/admin", Roles: []string{"admin"}},
+		{PathPrefix: "/audit", Permissions: []string{"audit:read"}},
+		{PathPrefix: "/orders-api", Audience: "orders-api"},
+	}
+
+	keys, err := signing.NewHMAC(cfg.JWT.Secret)
+	if err != nil {
+		t.Fatalf("signing keys: %v", err)
+	}
+	authService := service.NewAuthService(
+		repository.NewMemoryUserRepository(),
+		keys,
+		cfg.JWT.Issuer,
+		cfg.JWT.TTL,
+		cfg.Security.Roles,
+		cfg.Security.DefaultRole,
+		service.NewTokenBlacklist(),
+		service.NewAuditLogger(repository.NewMemoryAuditRepository()),
+	)
+
+	register := func(email, role string) string {
+		_, token, err := authService.Register(context.Background(), &model.UserRegisterRequest{Name: email, Email: email, Password: "secret123", Role: role})
+		if err != nil {
+			t.Fatalf("register %s: %v", email, err)
+		}
+		return token
+	}
+	userToken := register("user@example.com", "user")
+	adminToken := register("admin@example.com", "admin")
+	return NewServer(authService, cfg), userToken, adminToken
+}
+
+// audienceToken menandatangani ulang token dengan claim aud, seperti token
+// hasil token exchange
+func audienceToken(t *testing.T, s *Server, token, audience string) string {
+	t.Helper()
+	claims, err := s.authService.ValidateToken(context.Background(), token)
+	if err != nil {
+		t.Fatalf("validate token: %v", err)
+	}
+	claims.Audience = []string{audience}
+	keys, err := signing.NewHMAC(testSecret)
+	if err != nil {
+		t.Fatalf("signing keys: %v", err)
+	}
+	signed, err := keys.Sign(claims)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+// checkRequest membuat CheckRequest untuk request HTTP asal
+func checkRequest(method, path string, headers map[string]string) *authv3.CheckRequest {
+	return &authv3.CheckRequest{Attributes: &authv3.AttributeContext{Request: &authv3.AttributeContext_Request{
+		Http: &authv3.AttributeContext_HttpRequest{Method: method, Path: path, Headers: headers},
+	}}}
+}
+
+func TestCheck(t *testing.T) {
+	s, userToken, adminToken := newTestServer(t)
+	bearer := func(token string) map[string]string { return map[string]string{"authorization": "Bearer " + token} }
+	ordersToken := audienceToken(t, s, userToken, "orders-api")
+	billingToken := audienceToken(t, s, userToken, "billing-api")
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		headers map[string]string
+		want    codes.Code
+	}{
+		{"authenticated by default", "GET", "/orders?page=1", bearer(userToken), codes.OK},
+		{"token from cookie", "GET", "/orders", map[string]string{"cookie": "theme=dark; jwt=" + userToken}, codes.OK},
+		{"missing token", "GET", "/orders", nil, codes.Unauthenticated},
+		{"malformed authorization", "GET", "/orders", map[string]string{"authorization": "Basic abc"}, codes.Unauthenticated},
+		{"public without token", "GET", "/public/logo.png", nil, codes.OK},
+		{"role denied", "GET", "/admin/users", bearer(userToken), codes.PermissionDenied},
+		{"role allowed", "GET", "/admin/users", bearer(adminToken), codes.OK},
+		{"prefix matches per segment", "GET", "/administrator", bearer(userToken), codes.OK},
+		{"method-specific policy skipped", "GET", "/reports/1", bearer(userToken), codes.OK},
+		{"method-specific policy applied", "DELETE", "/reports/1", bearer(userToken), codes.PermissionDenied},
+		{"permission denied", "GET", "/audit", bearer(userToken), codes.PermissionDenied},
+		{"permission granted", "GET", "/audit", bearer(adminToken), codes.OK},
+		{"audience matches", "GET", "/orders-api/1", bearer(ordersToken), codes.OK},
+		{"audience mismatch", "GET", "/orders-api/1", bearer(billingToken), codes.PermissionDenied},
+		{"audience token without audience policy", "GET", "/orders", bearer(ordersToken), codes.PermissionDenied},
+		{"token without aud on audience policy", "GET", "/orders-api/1", bearer(userToken), codes.OK},
+		{"dot segments are cleaned", "GET", "/admin/./users", bearer(userToken), codes.PermissionDenied},
+		{"parent segment rejected", "GET", "/public/../admin", bearer(userToken), codes.InvalidArgument},
+		{"double slash rejected", "GET", "//admin", bearer(userToken), codes.InvalidArgument},
+		{"encoded parent segment rejected", "GET", "/public/%2e%2e/admin", bearer(userToken), codes.InvalidArgument},
+		{"encoded slash rejected", "GET", "/public%2F..%2Fadmin", nil, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.Check(context.Background(), checkRequest(tt.method, tt.path, tt.headers))
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if got := codes.Code(resp.GetStatus().GetCode()); got != tt.want {
+				t.Errorf("code = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckHeaders(t *testing.T) {
+	s, userToken, _ := newTestServer(t)
+
+	t.Run("identity headers on success", func(t *testing.T) {
+		resp, _ := s.Check(context.Background(), checkRequest("GET", "/orders", map[string]string{"authorization": "Bearer " + userToken}))
+		headers := map[string]string{}
+		for _, h := range resp.GetOkResponse().GetHeaders() {
+			headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+		}
+		if headers["x-user-email"] != "user@example.com" || headers["x-user-role"] != "user" || headers["x-user-id"] == "" || headers["x-principal-type"] != "user" {
+			t.Errorf("headers = %v", headers)
+		}
+	})
+
+	t.Run("spoofed identity removed on public path", func(t *testing.T) {
+		resp, _ := s.Check(context.Background(), checkRequest("GET", "/public", map[string]string{"x-user-role": "admin"}))
+		if removed := resp.GetOkResponse().GetHeadersToRemove(); len(removed) != 5 {
+			t.Errorf("headers_to_remove = %v, want identity headers", removed)
+		}
+	})
+
+	t.Run("localized problem body on denial", func(t *testing.T) {
+		resp, _ := s.Check(context.Background(), checkRequest("GET", "/admin", map[string]string{
+			"authorization":   "Bearer " + userToken,
+			"accept-language": "id",
+			"x-request-id":    "req-1",
+		}))
+		denied := resp.GetDeniedResponse()
+		if denied.GetStatus().GetCode() != 403 {
+			t.Fatalf("denied status = %v, want 403", denied.GetStatus().GetCode())
+		}
+		var problem model.Problem
+		if err := json.Unmarshal([]byte(denied.GetBody()), &problem); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if problem.Code != "forbidden" || problem.Title != i18n.T(i18n.Indonesian, i18n.ProblemTitleKey("forbidden")) || problem.Instance != "/admin" || problem.RequestID != "req-1" {
+			t.Errorf("problem = %+v", problem)
+		}
+	})
+}
+
+func TestCheckAdminScope(t *testing.T) {
+	s, _, _ := newTestServer(t)
+
+	// Token admin yang dibatasi scope, seperti token dari API key tanpa scope admin
+	keys, err := signing.NewHMAC("test-secret-that-is-long-enough-for-hs256")
+	if err != nil {
+		t.Fatalf("signing keys: %v", err)
+	}
+	token, err := keys.Sign(&model.JWTClaims{
+		UserID: 2,
+		Email:  "admin@example.com",
+		Role:   "admin",
+		Scopes: []string{"audit:read"},
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    config.Default().JWT.Issuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	headers := map[string]string{"authorization": "Bearer " + token}
+
+	resp, _ := s.Check(context.Background(), checkRequest("GET", "/admin/users", headers))
+	if got := codes.Code(resp.GetStatus().GetCode()); got != codes.PermissionDenied {
+		t.Errorf("admin role without admin scope: code = %s, want PermissionDenied", got)
+	}
+	resp, _ = s.Check(context.Background(), checkRequest("GET", "/audit", headers))
+	if got := codes.Code(resp.GetStatus().GetCode()); got != codes.OK {
+		t.Errorf("granted scope: code = %s, want OK", got)
+	}
+}
+
+func TestCheckImpersonatedAdmin(t *testing.T) {
+	s, _, _ := newTestServer(t)
+
+	keys, err := signing.NewHMAC("test-secret-that-is-long-enough-for-hs256")
+	if err != nil {
+		t.Fatalf("signing keys: %v", err)
+	}
+	token, err := keys.Sign(&model.JWTClaims{
+		UserID: 2,
+		Email:  "admin@example.com",
+		Role:   "admin",
+		Act:    &model.ActorClaim{UserID: 3, Email: "root@example.com"},
+		// Impersonation penanda token impersonation (bukan token delegasi)
+		Impersonation: true,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    config.Default().JWT.Issuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	resp, _ := s.Check(context.Background(), checkRequest("GET", "/admin/users", map[string]string{"authorization": "Bearer " + token}))
+	if got := codes.Code(resp.GetStatus().GetCode()); got != codes.PermissionDenied {
+		t.Errorf("impersonation token on admin policy: code = %s, want PermissionDenied", got)
+	}
+}
@@ -0,0 +1,157 @@
+package grpcserver
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+	"auth-service/pkg/authpb"
+	"context"
+
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AuthServer implementasi authpb.AuthServiceServer di atas AuthService yang
+// sama dengan REST API
+type AuthServer struct {
+    authpb.UnimplementedAuthServiceServer
+
+    authService service.AuthService
+    adminRoles  []string
+}
+
+// NewAuthServer membuat instance baru AuthServer
+func NewAuthServer(authService service.AuthService, adminRoles []string) *AuthServer {
+    return &AuthServer{authService: authService, adminRoles: adminRoles}
+}
+
+// publicMethods method yang dapat dipanggil tanpa token
+var publicMethods = []string{
+    authpb.AuthService_Register_FullMethodName,
+    authpb.AuthService_Login_FullMethodName,
+}
+
+// Register mendaftarkan pengguna baru
+func (s *AuthServer) Register(ctx context.Context, req *authpb.RegisterRequest) (*authpb.RegisterResponse, error) {
+    userReq := &model.UserRegisterRequest{
+        Name:     req.GetName(),
+        Email:    req.GetEmail(),
+        Password: req.GetPassword(),
+        Role:     req.GetRole(),
+        Locale:   req.GetLocale(),
+    }
+    if err := binding.Validator.ValidateStruct(userReq); err != nil {
+        return nil, model.NewValidationError(err)
+    }
+
+    user, token, err := s.authService.Register(ctx, userReq)
+    if err != nil {
+        return nil, err
+    }
+    return &authpb.RegisterResponse{User: toUser(user), Token: token}, nil
+}
+
+// Login mengautentikasi pengguna
+func (s *AuthServer) Login(ctx context.Context, req *authpb.LoginRequest) (*authpb.LoginResponse, error) {
+    loginReq := &model.UserLoginRequest{Email: req.GetEmail(), Password: req.GetPassword()}
+    if err := binding.Validator.ValidateStruct(loginReq); err != nil {
+        return nil, model.NewValidationError(err)
+    }
+
+    token, user, err := s.authService.Login(ctx, loginReq.Email, loginReq.Password)
+    if err != nil {
+        return nil, err
+    }
+    return &authpb.LoginResponse{User: toUser(user), Token: token}, nil
+}
+
+// ValidateToken mengembalikan claims dan profil pemilik token pemanggil.
+// Service account tidak memiliki profil pengguna sehingga hanya claims yang
+// dikembalikan.
+func (s *AuthServer) ValidateToken(ctx context.Context, _ *authpb.ValidateTokenRequest) (*authpb.ValidateTokenResponse, error) {
+    claims, ok := ClaimsFromContext(ctx)
+    if !ok {
+        return nil, model.ErrTokenMissing
+    }
+    if claims.IsService() {
+        return &authpb.ValidateTokenResponse{Claims: toClaims(claims)}, nil
+    }
+
+    user, err := s.authService.GetUserProfile(ctx, claims.UserID)
+    if err != nil {
+        return nil, err
+    }
+    return &authpb.ValidateTokenResponse{Claims: toClaims(claims), User: toUser(user)}, nil
+}
+
+// Logout mencabut token pemanggil
+func (s *AuthServer) Logout(ctx context.Context, _ *authpb.LogoutRequest) (*authpb.LogoutResponse, error) {
+    token := tokenFromContext(ctx)
+    if token == "" {
+        return nil, model.ErrTokenMissing
+    }
+    if err := s.authService.Logout(ctx, token); err != nil {
+        return nil, err
+    }
+    return &authpb.LogoutResponse{}, nil
+}
+
+// GetUserProfile mengambil profil pemanggil, atau profil pengguna lain jika
+// pemanggil memiliki hak admin (role dan scope admin, bukan token
+// impersonation). Service account tidak memiliki profil sendiri; ID-nya
+// tidak pernah dianggap ID pengguna.
+func (s *AuthServer) GetUserProfile(ctx context.Context, req *authpb.GetUserProfileRequest) (*authpb.GetUserProfileResponse, error) {
+    claims, ok := ClaimsFromContext(ctx)
+    if !ok {
+        return nil, model.ErrTokenMissing
+    }
+
+    userID := req.GetUserId()
+    if userID == 0 {
+        if claims.IsService() {
+            return nil, model.ErrForbidden
+        }
+        userID = claims.UserID
+    }
+    self := !claims.IsService() && userID == claims.UserID
+    if !self && !claims.HasAdminAccess(s.adminRoles) {
+        return nil, model.ErrForbidden
+    }
+
+    user, err := s.authService.GetUserProfile(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    return &authpb.GetUserProfileResponse{User: toUser(user)}, nil
+}
+
+// toUser mengubah UserResponse menjadi pesan protobuf
+func toUser(user *model.UserResponse) *authpb.User {
+    return &authpb.User{
+        Id:        user.ID,
+        Name:      user.Name,
+        Email:     user.Email,
+        Role:      user.Role,
+        Locale:    user.Locale,
+        CreatedAt: timestamppb.New(user.CreatedAt),
+    }
+}
+
+// toClaims mengubah JWTClaims menjadi pesan protobuf
+func toClaims(claims *model.JWTClaims) *authpb.Claims {
+    pb := &authpb.Claims{
+        UserId:  claims.UserID,
+        Email:   claims.Email,
+        Name:    claims.Name,
+        Role:    claims.Role,
+        Locale:  claims.Locale,
+        Issuer:  claims.Issuer,
+        Subject: claims.Subject,
+    }
+    if claims.IssuedAt != nil {
+        pb.IssuedAt = timestamppb.New(claims.IssuedAt.Time)
+    }
+    if claims.ExpiresAt != nil {
+        pb.ExpiresAt = timestamppb.New(claims.ExpiresAt.Time)
+    }
+    return pb
+}
@@ -0,0 +1,230 @@
+package grpcserver
+
+import (
+	"auth-service/internal/i18n"
+	"auth-service/internal/logging"
+	"auth-service/internal/middleware"
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDMetadata key metadata untuk propagasi request ID (setara X-Request-ID)
+const requestIDMetadata = "x-request-id"
+
+type claimsKey struct{}
+type tokenKey struct{}
+
+// ClaimsFromContext mengambil claims pemanggil yang disimpan AuthInterceptor
+func ClaimsFromContext(ctx context.Context) (*model.JWTClaims, bool) {
+    claims, ok := ctx.Value(claimsKey{}).(*model.JWTClaims)
+    return claims, ok && claims != nil
+}
+
+// tokenFromContext mengambil token pemanggil yang sudah divalidasi
+func tokenFromContext(ctx context.Context) string {
+    token, _ := ctx.Value(tokenKey{}).(string)
+    return token
+}
+
+// AuthInterceptor mengautentikasi pemanggil gRPC dengan validasi token yang
+// sama seperti JWTAuthMiddleware. Token dibaca dari metadata
+// "authorization: Bearer <token>"; method publik dilewati. Token ber-aud
+// (hasil token exchange) ditujukan untuk layanan lain sehingga ditolak.
+type AuthInterceptor struct {
+    authService   service.AuthService
+    publicMethods map[string]bool
+}
+
+// NewAuthInterceptor membuat instance baru AuthInterceptor. publicMethods
+// berisi nama method lengkap (misalnya "/auth.v1.AuthService/Login") yang
+// tidak memerlukan token.
+func NewAuthInterceptor(authService service.AuthService, publicMethods ...string) *AuthInterceptor {
+    public := make(map[string]bool, len(publicMethods))
+    for _, method := range publicMethods {
+        public[method] = true
+    }
+    return &AuthInterceptor{authService: authService, publicMethods: public}
+}
+
+// Unary interceptor autentikasi untuk RPC unary
+func (a *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+    return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+        ctx, err := a.authenticate(ctx, info.FullMethod)
+        if err != nil {
+            return nil, toStatus(ctx, err)
+        }
+        resp, err := handler(ctx, req)
+        return resp, toStatus(ctx, err)
+    }
+}
+
+// Stream interceptor autentikasi untuk RPC streaming
+func (a *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+    return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+        ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+        if err != nil {
+            return toStatus(ctx, err)
+        }
+        return toStatus(ctx, handler(srv, &contextStream{ServerStream: ss, ctx: ctx}))
+    }
+}
+
+// authenticate memvalidasi token pemanggil dan menyimpan claims ke context
+func (a *AuthInterceptor) authenticate(ctx context.Context, method string) (context.Context, error) {
+    if a.publicMethods[method] {
+        return ctx, nil
+    }
+
+    md, _ := metadata.FromIncomingContext(ctx)
+    values := md.Get("authorization")
+    if len(values) == 0 || values[0] == "" {
+        return ctx, model.ErrTokenMissing
+    }
+
+    // Format: Bearer <token>
+    parts := strings.Split(values[0], " ")
+    if len(parts) != 2 || parts[0] != "Bearer" {
+        return ctx, fmt.Errorf("%w: authorization metadata format must be Bearer {token}", model.ErrTokenInvalid)
+    }
+
+    claims, err := a.authService.ValidateToken(ctx, parts[1])
+    if err != nil {
+        return ctx, err
+    }
+    if !claims.ValidForAudience("") {
+        return ctx, fmt.Errorf("%w: token is intended for another audience", model.ErrForbidden)
+    }
+
+    ctx = context.WithValue(ctx, claimsKey{}, claims)
+    ctx = context.WithValue(ctx, tokenKey{}, parts[1])
+    ctx = middleware.WithPrincipalContext(ctx, claims)
+    // Preferensi bahasa pengguna lebih diutamakan daripada accept-language
+    if claims.Locale != "" && i18n.IsSupported(claims.Locale) {
+        ctx = i18n.WithLocale(ctx, claims.Locale)
+    }
+    return ctx, nil
+}
+
+// RequestInterceptor mengisi context RPC seperti middleware RequestID,
+// ClientInfo dan Locale pada REST API, mencatat access log, memulihkan
+// panic, dan mengubah error domain yang tersisa menjadi status gRPC
+type RequestInterceptor struct {
+    defaultLocale string
+}
+
+// NewRequestInterceptor membuat instance baru RequestInterceptor
+func NewRequestInterceptor(defaultLocale string) *RequestInterceptor {
+    return &RequestInterceptor{defaultLocale: defaultLocale}
+}
+
+// Unary interceptor request untuk RPC unary
+func (r *RequestInterceptor) Unary() grpc.UnaryServerInterceptor {
+    return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+        ctx = r.prepare(ctx)
+        start := time.Now()
+        defer func() {
+            if recovered := recover(); recovered != nil {
+                slog.ErrorContext(ctx, "panic recovered", "panic", recovered)
+                err = fmt.Errorf("panic: %v", recovered)
+            }
+            err = toStatus(ctx, err)
+            accessLog(ctx, info.FullMethod, start, err)
+        }()
+        return handler(ctx, req)
+    }
+}
+
+// Stream interceptor request untuk RPC streaming
+func (r *RequestInterceptor) Stream() grpc.StreamServerInterceptor {
+    return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+        ctx := r.prepare(ss.Context())
+        start := time.Now()
+        defer func() {
+            if recovered := recover(); recovered != nil {
+                slog.ErrorContext(ctx, "panic recovered", "panic", recovered)
+                err = fmt.Errorf("panic: %v", recovered)
+            }
+            err = toStatus(ctx, err)
+            accessLog(ctx, info.FullMethod, start, err)
+        }()
+        return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+    }
+}
+
+// prepare menyimpan request ID, informasi klien dan bahasa ke context
+func (r *RequestInterceptor) prepare(ctx context.Context) context.Context {
+    md, _ := metadata.FromIncomingContext(ctx)
+
+    requestID := middleware.NormalizeRequestID(firstValue(md, requestIDMetadata))
+    ctx = logging.WithRequestID(ctx, requestID)
+    _ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, requestID))
+
+    info := service.ClientInfo{UserAgent: firstValue(md, "user-agent")}
+    if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+        info.IP = p.Addr.String()
+        if host, _, err := net.SplitHostPort(info.IP); err == nil {
+            info.IP = host
+        }
+    }
+    ctx = service.WithClientInfo(ctx, info)
+
+    locale := i18n.Negotiate(firstValue(md, "accept-language"))
+    if locale == "" {
+        locale = r.defaultLocale
+    }
+    return i18n.WithLocale(ctx, locale)
+}
+
+// accessLog mencatat satu RPC secara terstruktur. Metadata dan pesan sengaja
+// tidak dicatat karena dapat berisi token atau password.
+func accessLog(ctx context.Context, method string, start time.Time, err error) {
+    code := status.Code(err)
+    level := slog.LevelInfo
+    switch code {
+    case codes.OK:
+    case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+        level = slog.LevelError
+    default:
+        level = slog.LevelWarn
+    }
+
+    attrs := []slog.Attr{
+        slog.String("method", method),
+        slog.String("code", code.String()),
+        slog.Duration("latency", time.Since(start)),
+    }
+    if err != nil {
+        attrs = append(attrs, slog.String("errors", err.Error()))
+    }
+    slog.LogAttrs(ctx, level, "gRPC request", attrs...)
+}
+
+// firstValue mengambil nilai pertama key metadata
+func firstValue(md metadata.MD, key string) string {
+    if values := md.Get(key); len(values) > 0 {
+        return values[0]
+    }
+    return ""
+}
+
+// contextStream ServerStream dengan context yang sudah diperkaya interceptor
+type contextStream struct {
+    grpc.ServerStream
+    ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+    return s.ctx
+}
@@ -0,0 +1,121 @@
+// Package grpcserver menyediakan API gRPC (proto/auth/v1/auth.proto) yang
+// berjalan di samping REST API dengan AuthService, validasi token dan
+// pemetaan error yang sama.
+package grpcserver
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/extauthz"
+	"auth-service/internal/i18n"
+	"auth-service/internal/service"
+	"auth-service/pkg/authpb"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"time"
+
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Server membungkus grpc.Server dengan listener dan graceful shutdown
+type Server struct {
+    grpcServer      *grpc.Server
+    addr            string
+    drainPeriod     time.Duration
+    shutdownTimeout time.Duration
+}
+
+// New membuat server gRPC dari konfigurasi. Jika tlsConfig tidak nil, server
+// memakai TLS dengan sertifikat yang sama seperti server HTTP.
+func New(cfg *config.Config, authService service.AuthService, tlsConfig *tls.Config) *Server {
+    // Pesan error validasi diterjemahkan dan memakai nama field JSON
+    if err := i18n.RegisterBindingValidator(); err != nil {
+        panic("Failed to register validator translations: " + err.Error())
+    }
+
+    // Envoy memanggil Check tanpa token; token request asal ada di CheckRequest
+    public := slices.Clone(publicMethods)
+    if cfg.ExtAuthz.Enabled {
+        public = append(public, authv3.Authorization_Check_FullMethodName)
+    }
+
+    requestInterceptor := NewRequestInterceptor(cfg.I18n.DefaultLocale)
+    authInterceptor := NewAuthInterceptor(authService, public...)
+
+    opts := []grpc.ServerOption{
+        grpc.ChainUnaryInterceptor(requestInterceptor.Unary(), authInterceptor.Unary()),
+        grpc.ChainStreamInterceptor(requestInterceptor.Stream(), authInterceptor.Stream()),
+        grpc.MaxRecvMsgSize(int(cfg.Server.MaxBodyBytes)),
+    }
+    if tlsConfig != nil {
+        opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+    }
+
+    grpcServer := grpc.NewServer(opts...)
+    authpb.RegisterAuthServiceServer(grpcServer, NewAuthServer(authService, cfg.Security.AdminRoles))
+    if cfg.ExtAuthz.Enabled {
+        authv3.RegisterAuthorizationServer(grpcServer, extauthz.NewServer(authService, cfg))
+    }
+
+    return &Server{
+        grpcServer:      grpcServer,
+        addr:            ":" + cfg.GRPC.Port,
+        drainPeriod:     cfg.Server.DrainPeriod,
+        shutdownTimeout: cfg.Server.ShutdownTimeout,
+    }
+}
+
+// Run menjalankan server sampai ctx dibatalkan, lalu menunggu drain period
+// dan menghentikan server secara graceful. RPC yang belum selesai setelah
+// shutdown timeout dihentikan paksa.
+func (s *Server) Run(ctx context.Context) error {
+    listener, err := net.Listen("tcp", s.addr)
+    if err != nil {
+        return fmt.Errorf("failed to listen on %s: %v", s.addr, err)
+    }
+    return s.Serve(ctx, listener)
+}
+
+// Serve seperti Run dengan listener yang sudah dibuka
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+    serveErr := make(chan error, 1)
+    go func() {
+        slog.Info("gRPC server starting", "addr", s.addr)
+        serveErr <- s.grpcServer.Serve(listener)
+    }()
+
+    select {
+    case err := <-serveErr:
+        return fmt.Errorf("gRPC server stopped unexpectedly: %v", err)
+    case <-ctx.Done():
+    }
+
+    if s.drainPeriod > 0 {
+        slog.Info("Shutdown signal received, draining", "addr", s.addr, "drain_period", s.drainPeriod)
+        time.Sleep(s.drainPeriod)
+    }
+
+    slog.Info("Shutting down gRPC server", "addr", s.addr)
+    stopped := make(chan struct{})
+    go func() {
+        s.grpcServer.GracefulStop()
+        close(stopped)
+    }()
+    select {
+    case <-stopped:
+    case <-time.After(s.shutdownTimeout):
+        s.grpcServer.Stop()
+        return fmt.Errorf("failed to shutdown gRPC server gracefully: timeout after %s", s.shutdownTimeout)
+    }
+
+    if err := <-serveErr; err != nil {
+        return fmt.Errorf("gRPC server error: %v", err)
+    }
+    slog.Info("gRPC server stopped", "addr", s.addr)
+    return nil
+}
@@ -0,0 +1,279 @@
+package grpcserver
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"auth-service/internal/service"
+	"auth-service/internal/signing"
+	"auth-service/pkg/authpb"
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	os.Exit(m.Run())
+}
+
+// testSecret secret HMAC yang dipakai newTestClient
+const testSecret = "test-secret-that-is-long-enough-for-hs256"
+
+// newTestClient menjalankan server gRPC di atas bufconn dengan repository di memori
+func newTestClient(t *testing.T) authpb.AuthServiceClient {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Env = "test"
+	cfg.Server.ShutdownTimeout = time.Second
+	cfg.JWT.Secret = testSecret
+
+	keys, err := signing.NewHMAC(cfg.JWT.Secret)
+	if err != nil {
+		t.Fatalf("signing keys: %v", err)
+	}
+	authService := service.NewAuthService(
+		repository.NewMemoryUserRepository(),
+		keys,
+		cfg.JWT.Issuer,
+		cfg.JWT.TTL,
+		cfg.Security.Roles,
+		cfg.Security.DefaultRole,
+		service.NewTokenBlacklist(),
+		service.NewAuditLogger(repository.NewMemoryAuditRepository()),
+	)
+
+	listener := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = New(cfg, authService, nil).Serve(ctx, listener)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		cancel()
+		<-done
+	})
+	return authpb.NewAuthServiceClient(conn)
+}
+
+// withToken menambahkan metadata authorization Bearer
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// audienceToken menandatangani ulang token dengan claim aud, seperti token
+// hasil token exchange
+func audienceToken(t *testing.T, token, audience string) string {
+	t.Helper()
+	claims := &model.JWTClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		t.Fatalf("parse token: %v", err)
+	}
+	claims.Audience = []string{audience}
+	keys, err := signing.NewHMAC(testSecret)
+	if err != nil {
+		t.Fatalf("signing keys: %v", err)
+	}
+	signed, err := keys.Sign(claims)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+// errorReason mengambil reason ErrorInfo dari status gRPC
+func errorReason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+func TestAuthFlow(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	registered, err := client.Register(ctx, &authpb.RegisterRequest{Name: "Alice", Email: "alice@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if registered.GetToken() == "" || registered.GetUser().GetRole() != "user" {
+		t.Errorf("Register response = %v", registered)
+	}
+
+	login, err := client.Login(ctx, &authpb.LoginRequest{Email: "alice@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	validated, err := client.ValidateToken(withToken(ctx, login.GetToken()), &authpb.ValidateTokenRequest{})
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if validated.GetClaims().GetUserId() != registered.GetUser().GetId() || validated.GetUser().GetEmail() != "alice@example.com" {
+		t.Errorf("ValidateToken response = %v", validated)
+	}
+
+	profile, err := client.GetUserProfile(withToken(ctx, login.GetToken()), &authpb.GetUserProfileRequest{})
+	if err != nil || profile.GetUser().GetId() != registered.GetUser().GetId() {
+		t.Errorf("GetUserProfile = %v, %v", profile, err)
+	}
+
+	if _, err := client.Logout(withToken(ctx, login.GetToken()), &authpb.LogoutRequest{}); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	_, err = client.ValidateToken(withToken(ctx, login.GetToken()), &authpb.ValidateTokenRequest{})
+	if status.Code(err) != codes.Unauthenticated || errorReason(err) != "token_revoked" {
+		t.Errorf("ValidateToken after logout = %v, want Unauthenticated token_revoked", err)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	registered, err := client.Register(ctx, &authpb.RegisterRequest{Name: "Bob", Email: "bob@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	userCtx := withToken(ctx, registered.GetToken())
+
+	tests := []struct {
+		name   string
+		call   func() error
+		code   codes.Code
+		reason string
+	}{
+		{"duplicate email", func() error {
+			_, err := client.Register(ctx, &authpb.RegisterRequest{Name: "Bob", Email: "bob@example.com", Password: "secret123"})
+			return err
+		}, codes.AlreadyExists, "email_taken"},
+		{"validation", func() error {
+			_, err := client.Register(ctx, &authpb.RegisterRequest{Name: "Bob", Email: "not-an-email", Password: "1"})
+			return err
+		}, codes.InvalidArgument, "validation_failed"},
+		{"wrong password", func() error {
+			_, err := client.Login(ctx, &authpb.LoginRequest{Email: "bob@example.com", Password: "wrong-password"})
+			return err
+		}, codes.Unauthenticated, "invalid_credentials"},
+		{"missing token", func() error {
+			_, err := client.ValidateToken(ctx, &authpb.ValidateTokenRequest{})
+			return err
+		}, codes.Unauthenticated, "authentication_required"},
+		{"malformed token", func() error {
+			_, err := client.ValidateToken(withToken(ctx, "not-a-jwt"), &authpb.ValidateTokenRequest{})
+			return err
+		}, codes.Unauthenticated, "token_invalid"},
+		{"other user's profile", func() error {
+			_, err := client.GetUserProfile(userCtx, &authpb.GetUserProfileRequest{UserId: registered.GetUser().GetId() + 1})
+			return err
+		}, codes.PermissionDenied, "forbidden"},
+		{"token for another audience", func() error {
+			_, err := client.ValidateToken(withToken(ctx, audienceToken(t, registered.GetToken(), "orders-api")), &authpb.ValidateTokenRequest{})
+			return err
+		}, codes.PermissionDenied, "forbidden"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if status.Code(err) != tt.code || errorReason(err) != tt.reason {
+				t.Errorf("error = %v (reason %q), want %s %s", err, errorReason(err), tt.code, tt.reason)
+			}
+		})
+	}
+}
+
+func TestLocalizedStatus(t *testing.T) {
+	client := newTestClient(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "id")
+
+	_, err := client.Register(ctx, &authpb.RegisterRequest{Name: "Cici", Email: "cici@example.com"})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("code = %s, want InvalidArgument", st.Code())
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			violations = badRequest.GetFieldViolations()
+		}
+	}
+	if len(violations) != 1 || violations[0].GetField() != "password" || violations[0].GetDescription() != "password wajib diisi" {
+		t.Errorf("field violations = %v", violations)
+	}
+}
+
+func TestGetUserProfileAdminScope(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	registered, err := client.Register(ctx, &authpb.RegisterRequest{Name: "Dedi", Email: "dedi@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	keys, err := signing.NewHMAC("test-secret-that-is-long-enough-for-hs256")
+	if err != nil {
+		t.Fatalf("signing keys: %v", err)
+	}
+	adminToken := func(act *model.ActorClaim, scopes ...string) string {
+		token, err := keys.Sign(&model.JWTClaims{
+			UserID: registered.GetUser().GetId() + 1,
+			Email:  "admin@example.com",
+			Role:   "admin",
+			Scopes: scopes,
+			Act:    act,
+			// Impersonation penanda token impersonation (bukan token delegasi)
+			Impersonation: act != nil,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    config.Default().JWT.Issuer,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		})
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		return token
+	}
+	req := &authpb.GetUserProfileRequest{UserId: registered.GetUser().GetId()}
+
+	profile, err := client.GetUserProfile(withToken(ctx, adminToken(nil)), req)
+	if err != nil || profile.GetUser().GetEmail() != "dedi@example.com" {
+		t.Errorf("GetUserProfile as admin = %v, %v", profile, err)
+	}
+	_, err = client.GetUserProfile(withToken(ctx, adminToken(nil, "profile")), req)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("GetUserProfile as admin without admin scope = %v, want PermissionDenied", err)
+	}
+	_, err = client.GetUserProfile(withToken(ctx, adminToken(&model.ActorClaim{UserID: registered.GetUser().GetId() + 2})), req)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("GetUserProfile with impersonation token = %v, want PermissionDenied", err)
+	}
+}
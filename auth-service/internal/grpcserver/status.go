@@ -0,0 +1,74 @@
+package grpcserver
+
+import (
+	"auth-service/internal/i18n"
+	"auth-service/internal/middleware"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain domain pada detail google.rpc.ErrorInfo
+const errorDomain = "auth-service"
+
+// statusCodes pemetaan status HTTP dari Problem ke kode gRPC
+var statusCodes = map[int]codes.Code{
+    http.StatusBadRequest:            codes.InvalidArgument,
+    http.StatusUnauthorized:          codes.Unauthenticated,
+    http.StatusForbidden:             codes.PermissionDenied,
+    http.StatusNotFound:              codes.NotFound,
+    http.StatusMethodNotAllowed:      codes.Unimplemented,
+    http.StatusConflict:              codes.AlreadyExists,
+    http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+}
+
+// toStatus mengubah error domain menjadi status gRPC dengan pemetaan yang
+// sama seperti response problem+json REST API. Kode Problem dikirim sebagai
+// reason pada ErrorInfo dan pesan status memakai bahasa dari ctx; error yang
+// tidak dikenal dicatat ke log dan menjadi Internal tanpa detail. Error yang
+// sudah berupa status gRPC dikembalikan apa adanya.
+func toStatus(ctx context.Context, err error) error {
+    if err == nil {
+        return nil
+    }
+    if _, ok := status.FromError(err); ok {
+        return err
+    }
+    if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+        return status.FromContextError(err).Err()
+    }
+
+    problem := middleware.NewProblem(err, i18n.FromContext(ctx))
+    code, ok := statusCodes[problem.Status]
+    if !ok {
+        code = codes.Internal
+        slog.ErrorContext(ctx, "Request failed", "error", err)
+    }
+
+    message := problem.Title
+    if problem.Detail != "" {
+        message += ": " + problem.Detail
+    }
+    st, err := status.New(code, message).WithDetails(&errdetails.ErrorInfo{Reason: problem.Code, Domain: errorDomain})
+    if err != nil {
+        return status.Error(code, message)
+    }
+    if len(problem.Errors) > 0 {
+        badRequest := &errdetails.BadRequest{}
+        for _, fe := range problem.Errors {
+            badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+                Field:       fe.Field,
+                Description: fe.Message,
+            })
+        }
+        if withFields, err := st.WithDetails(badRequest); err == nil {
+            st = withFields
+        }
+    }
+    return st.Err()
+}
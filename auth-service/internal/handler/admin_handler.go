@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"auth-service/internal/i18n"
+	"auth-service/internal/middleware"
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+    defaultAuditLimit = 100
+    maxAuditLimit     = 1000
+)
+
+// AdminHandler menangani request HTTP khusus admin
+type AdminHandler struct {
+    authService service.AuthService
+    audit       *service.AuditLogger
+}
+
+// NewAdminHandler membuat instance baru AdminHandler
+func NewAdminHandler(authService service.AuthService, audit *service.AuditLogger) *AdminHandler {
+    return &AdminHandler{
+        authService: authService,
+        audit:       audit,
+    }
+}
+
+// UpdateUserRole menangani request perubahan role pengguna
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+    jwtClaims, err := claimsFromContext(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        _ = c.Error(model.NewValidationError(errors.New("invalid user ID")))
+        return
+    }
+
+    var req model.UpdateRoleRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        _ = c.Error(model.NewValidationError(err))
+        return
+    }
+
+    user, err := h.authService.UpdateUserRole(c.Request.Context(), auditActorID(c, jwtClaims), userID, req.Role)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": message(c, i18n.MessageRoleUpdated),
+        "user":    user,
+    })
+}
+
+// ListAuditEvents menangani query event audit dengan filter user, type dan rentang waktu
+func (h *AdminHandler) ListAuditEvents(c *gin.Context) {
+    filter, err := parseAuditFilter(c)
+    if err != nil {
+        _ = c.Error(model.NewValidationError(err))
+        return
+    }
+    if filter.Limit == 0 {
+        filter.Limit = defaultAuditLimit
+    }
+
+    events, err := h.audit.Find(c.Request.Context(), filter)
+    if err != nil {
+        _ = c.Error(fmt.Errorf("failed to query audit events: %w", err))
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "events": events,
+        "limit":  filter.Limit,
+        "offset": filter.Offset,
+    })
+}
+
+// ExportAuditEvents mengekspor event audit sesuai filter dalam format JSON Lines
+func (h *AdminHandler) ExportAuditEvents(c *gin.Context) {
+    jwtClaims, err := claimsFromContext(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    filter, err := parseAuditFilter(c)
+    if err != nil {
+        _ = c.Error(model.NewValidationError(err))
+        return
+    }
+
+    // Export itu sendiri juga dicatat di log audit
+    h.audit.Record(c.Request.Context(), model.AuditEvent{
+        EventType: model.AuditEventExport,
+        ActorID:   auditActorID(c, jwtClaims),
+        SubjectID: filter.UserID,
+        Outcome:   model.AuditOutcomeSuccess,
+        Reason:    c.Request.URL.RawQuery,
+    })
+
+    c.Header("Content-Type", "application/x-ndjson")
+    c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="audit-events-%s.jsonl"`, time.Now().UTC().Format("20060102T150405Z")))
+    c.Status(http.StatusOK)
+
+    encoder := json.NewEncoder(c.Writer)
+    err = h.audit.Export(c.Request.Context(), filter, func(event *model.AuditEvent) error {
+        return encoder.Encode(event)
+    })
+    if err != nil {
+        // Header sudah terkirim, jadi kegagalan hanya bisa dicatat di log
+        slog.ErrorContext(c.Request.Context(), "Audit export interrupted", "error", err)
+        return
+    }
+    c.Writer.Flush()
+}
+
+// parseAuditFilter membaca filter audit dari query string:
+// user_id, actor_type, type, from, to (RFC 3339), limit dan offset
+func parseAuditFilter(c *gin.Context) (model.AuditEventFilter, error) {
+    var filter model.AuditEventFilter
+
+    if value := c.Query("user_id"); value != "" {
+        userID, err := strconv.ParseInt(value, 10, 64)
+        if err != nil {
+            return filter, errors.New("invalid user_id")
+        }
+        filter.UserID = &userID
+    }
+    switch filter.ActorType = c.Query("actor_type"); filter.ActorType {
+    case "", model.PrincipalTypeUser, model.PrincipalTypeService:
+    default:
+        return filter, errors.New("invalid actor_type, must be user or service")
+    }
+    filter.EventType = c.Query("type")
+
+    if value := c.Query("from"); value != "" {
+        from, err := time.Parse(time.RFC3339, value)
+        if err != nil {
+            return filter, errors.New("invalid from, expected RFC 3339 timestamp")
+        }
+        filter.From = &from
+    }
+    if value := c.Query("to"); value != "" {
+        to, err := time.Parse(time.RFC3339, value)
+        if err != nil {
+            return filter, errors.New("invalid to, expected RFC 3339 timestamp")
+        }
+        filter.To = &to
+    }
+
+    if value := c.Query("limit"); value != "" {
+        limit, err := strconv.Atoi(value)
+        if err != nil || limit < 1 || limit > maxAuditLimit {
+            return filter, fmt.Errorf("invalid limit, must be between 1 and %d", maxAuditLimit)
+        }
+        filter.Limit = limit
+    }
+    if value := c.Query("offset"); value != "" {
+        offset, err := strconv.Atoi(value)
+        if err != nil || offset < 0 {
+            return filter, errors.New("invalid offset")
+        }
+        filter.Offset = offset
+    }
+
+    return filter, nil
+}
+
+// claimsFromContext mengambil JWT claims yang diisi middleware autentikasi
+func claimsFromContext(c *gin.Context) (*model.JWTClaims, error) {
+    claimsRaw, exists := c.Get("jwtClaims")
+    if !exists {
+        return nil, model.ErrTokenMissing
+    }
+
+    jwtClaims, ok := claimsRaw.(*model.JWTClaims)
+    if !ok {
+        return nil, errors.New("invalid JWT claims format")
+    }
+    return jwtClaims, nil
+}
+
+// auditActorID ID actor untuk event audit. Principal mTLS tidak memiliki ID;
+// AuditLogger mencatatnya dengan subject sertifikat klien.
+func auditActorID(c *gin.Context, claims *model.JWTClaims) *int64 {
+    if c.GetString("authMethod") == middleware.AuthMethodClientCert {
+        return nil
+    }
+    return &claims.UserID
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"auth-service/internal/i18n"
+	"auth-service/internal/middleware"
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler menangani pengelolaan API key milik pengguna yang login
+type APIKeyHandler struct {
+    apiKeyService service.APIKeyService
+}
+
+// NewAPIKeyHandler membuat instance baru APIKeyHandler
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+    return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// Create menangani pembuatan API key. Key lengkap hanya ada di response ini.
+func (h *APIKeyHandler) Create(c *gin.Context) {
+    jwtClaims, err := userSessionClaims(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    var req model.CreateAPIKeyRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        _ = c.Error(model.NewValidationError(err))
+        return
+    }
+
+    key, err := h.apiKeyService.Create(c.Request.Context(), jwtClaims.UserID, model.UserPrincipal(jwtClaims.UserID), &req)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": message(c, i18n.MessageAPIKeyCreated),
+        "api_key": key,
+    })
+}
+
+// List menangani daftar API key milik pengguna (tanpa secret)
+func (h *APIKeyHandler) List(c *gin.Context) {
+    jwtClaims, err := userSessionClaims(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    keys, err := h.apiKeyService.List(c.Request.Context(), model.UserPrincipal(jwtClaims.UserID))
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "api_keys": keys,
+    })
+}
+
+// Delete menangani pencabutan API key milik pengguna
+func (h *APIKeyHandler) Delete(c *gin.Context) {
+    jwtClaims, err := userSessionClaims(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        _ = c.Error(model.NewValidationError(errors.New("invalid API key ID")))
+        return
+    }
+
+    if err := h.apiKeyService.Delete(c.Request.Context(), jwtClaims.UserID, model.UserPrincipal(jwtClaims.UserID), id); err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": message(c, i18n.MessageAPIKeyDeleted),
+    })
+}
+
+// userSessionClaims mengambil claims pengguna yang login dengan token.
+// Principal layanan (mTLS atau service account) tidak memiliki profil
+// pengguna, dan API key maupun token untuk layanan lain (hasil token
+// exchange) tidak boleh dipakai untuk membuat kredensial baru atau
+// menerbitkan token.
+func userSessionClaims(c *gin.Context) (*model.JWTClaims, error) {
+    jwtClaims, err := claimsFromContext(c)
+    if err != nil {
+        return nil, err
+    }
+    if jwtClaims.IsService() || jwtClaims.IsAudienceRestricted() {
+        return nil, model.ErrForbidden
+    }
+
+    switch c.GetString("authMethod") {
+    case middleware.AuthMethodClientCert, middleware.AuthMethodAPIKey:
+        return nil, model.ErrForbidden
+    }
+    return jwtClaims, nil
+}
@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"auth-service/internal/config"
+	"auth-service/internal/i18n"
+	"auth-service/internal/metrics"
+	"auth-service/internal/middleware"
 	"auth-service/internal/model"
 	"auth-service/internal/service"
 	"net/http"
@@ -12,15 +16,15 @@ import (
 // AuthHandler menangani request HTTP untuk autentikasi
 type AuthHandler struct {
     authService service.AuthService
-    jwtSecret   string
+    cookie      config.CookieConfig
     jwtExpiry   time.Duration
 }
 
 // NewAuthHandler membuat instance baru AuthHandler
-func NewAuthHandler(authService service.AuthService, jwtSecret string, jwtExpiry time.Duration) *AuthHandler {
+func NewAuthHandler(authService service.AuthService, cookie config.CookieConfig, jwtExpiry time.Duration) *AuthHandler {
     return &AuthHandler{
         authService: authService,
-        jwtSecret:   jwtSecret,
+        cookie:      cookie,
         jwtExpiry:   jwtExpiry,
     }
 }
@@ -30,22 +34,23 @@ func (h *AuthHandler) Register(c *gin.Context) {
     var req model.UserRegisterRequest
 
     if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        _ = c.Error(model.NewValidationError(err))
         return
     }
 
     // Panggil service register yang sekarang mengembalikan token
-    user, token, err := h.authService.Register(&req)
+    user, token, err := h.authService.Register(c.Request.Context(), &req)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        _ = c.Error(err)
         return
     }
 
     // Set HttpOnly cookie
-    c.SetCookie("jwt", token, int(h.jwtExpiry.Seconds()), "/", "localhost", false, true)
+    h.setTokenCookie(c, token, int(h.jwtExpiry.Seconds()))
+    applyUserLocale(c, user.Locale)
 
     c.JSON(http.StatusCreated, gin.H{
-        "message": "User registered successfully",
+        "message": message(c, i18n.MessageRegistered),
         "user":    user,
     })
 }
@@ -55,72 +60,169 @@ func (h *AuthHandler) Login(c *gin.Context) {
     var req model.UserLoginRequest
 
     if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        metrics.LoginFailureTotal.WithLabelValues(metrics.LoginReasonInvalidRequest).Inc()
+        _ = c.Error(model.NewValidationError(err))
         return
     }
 
-    token, user, err := h.authService.Login(req.Email, req.Password)
+    token, user, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
     if err != nil {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+        _ = c.Error(err)
         return
     }
 
     // Set HttpOnly cookie
-    c.SetCookie("jwt", token, int(h.jwtExpiry.Seconds()), "/", "localhost", false, true)
+    h.setTokenCookie(c, token, int(h.jwtExpiry.Seconds()))
+
+    // Pesan login memakai preferensi bahasa pengguna jika ada
+    applyUserLocale(c, user.Locale)
 
     c.JSON(http.StatusOK, gin.H{
-        "message": "Login successful",
+        "message": message(c, i18n.MessageLoggedIn),
         "user":    user,
     })
 }
 
 // Logout menangani request logout pengguna
 func (h *AuthHandler) Logout(c *gin.Context) {
-    token, err := c.Cookie("jwt")
+    token, err := c.Cookie(h.cookie.Name)
     if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "No token provided"})
+        _ = c.Error(model.ErrTokenMissing)
         return
     }
 
     // Logout service -> masukkan token ke blacklist
-    if err := h.authService.Logout(token); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+    if err := h.authService.Logout(c.Request.Context(), token); err != nil {
+        _ = c.Error(err)
         return
     }
 
     // Hapus cookie dengan expiry ke waktu lampau
-    c.SetCookie("jwt", "", -1, "/", "localhost", false, true)
+    h.setTokenCookie(c, "", -1)
 
     c.JSON(http.StatusOK, gin.H{
-        "message": "Logout successful",
+        "message": message(c, i18n.MessageLoggedOut),
     })
 }
 
 // Validate menangani request validasi token
 func (h *AuthHandler) Validate(c *gin.Context) {
-    claimsRaw, exists := c.Get("jwtClaims")
-    if !exists {
-        c.JSON(http.StatusUnauthorized, gin.H{"error": "JWT claims not found"})
+    jwtClaims, err := claimsFromContext(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    // Principal layanan (mTLS) tidak memiliki profil pengguna di database
+    if c.GetString("authMethod") == middleware.AuthMethodClientCert {
+        c.JSON(http.StatusOK, gin.H{
+            "valid":         true,
+            "principal":     jwtClaims.Name,
+            "principalType": model.PrincipalTypeService,
+            "role":          jwtClaims.Role,
+            "authMethod":    middleware.AuthMethodClientCert,
+        })
+        return
+    }
+
+    response := gin.H{
+        "valid":         true,
+        "principalType": jwtClaims.Principal().Type,
+        "issuer":        jwtClaims.Issuer,
+        "issuedAt":      jwtClaims.IssuedAt,
+        "expiresAt":     jwtClaims.ExpiresAt,
+    }
+    if jwtClaims.IsService() {
+        // Service account juga tidak memiliki profil pengguna
+        response["principal"] = jwtClaims.Name
+        response["serviceAccountId"] = jwtClaims.UserID
+        response["role"] = jwtClaims.Role
+        if len(jwtClaims.Scopes) > 0 {
+            response["scopes"] = jwtClaims.Scopes
+        }
+    } else {
+        user, err := h.authService.GetUserProfile(c.Request.Context(), jwtClaims.UserID)
+        if err != nil {
+            _ = c.Error(err)
+            return
+        }
+        response["user"] = user
+    }
+    // Token impersonation ditandai agar UI dapat menampilkan bahwa admin
+    // sedang bertindak atas nama pengguna; token delegasi ditandai terpisah.
+    // actor berisi rantai claim act pada keduanya.
+    if jwtClaims.IsImpersonated() {
+        response["impersonated"] = true
+    }
+    if jwtClaims.IsDelegated() {
+        response["delegated"] = true
+    }
+    if jwtClaims.Act != nil {
+        response["actor"] = jwtClaims.Act
+    }
+    // Token hasil token exchange hanya berlaku untuk audience dan scope-nya;
+    // layanan tujuan wajib memeriksa bahwa dirinya termasuk audience
+    if jwtClaims.IsAudienceRestricted() {
+        response["audience"] = jwtClaims.Audience
+        response["scopes"] = jwtClaims.Scopes
+    }
+    // API key dibatasi scope-nya; expiresAt kosong jika key tanpa kedaluwarsa
+    if c.GetString("authMethod") == middleware.AuthMethodAPIKey {
+        response["authMethod"] = middleware.AuthMethodAPIKey
+        response["scopes"] = jwtClaims.Scopes
+    }
+    c.JSON(http.StatusOK, response)
+}
+
+// UpdateLocale menangani perubahan preferensi bahasa pengguna. Token baru
+// yang membawa preferensi tersebut langsung dikirim lewat cookie.
+func (h *AuthHandler) UpdateLocale(c *gin.Context) {
+    jwtClaims, err := userSessionClaims(c)
+    if err != nil {
+        _ = c.Error(err)
         return
     }
 
-    jwtClaims, ok := claimsRaw.(*model.JWTClaims)
-    if !ok {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid JWT claims format"})
+    var req model.UpdateLocaleRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        _ = c.Error(model.NewValidationError(err))
         return
     }
 
-    user, err := h.authService.GetUserProfile(jwtClaims.UserID)
+    user, token, err := h.authService.UpdateLocale(c.Request.Context(), jwtClaims.UserID, req.Locale)
     if err != nil {
-        c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+        _ = c.Error(err)
         return
     }
 
+    h.setTokenCookie(c, token, int(h.jwtExpiry.Seconds()))
+    applyUserLocale(c, user.Locale)
+
     c.JSON(http.StatusOK, gin.H{
-        "valid":     true,
-        "user":      user,
-        "issuer":    jwtClaims.Issuer,
-        "issuedAt":  jwtClaims.IssuedAt,
-        "expiresAt": jwtClaims.ExpiresAt,
+        "message": message(c, i18n.MessageLocaleUpdated),
+        "user":    user,
     })
 }
+
+// setTokenCookie menulis cookie JWT sesuai konfigurasi cookie
+func (h *AuthHandler) setTokenCookie(c *gin.Context, value string, maxAge int) {
+    writeTokenCookie(c, h.cookie, value, maxAge)
+}
+
+// writeTokenCookie menulis cookie JWT; maxAge negatif menghapus cookie
+func writeTokenCookie(c *gin.Context, cookie config.CookieConfig, value string, maxAge int) {
+    c.SetSameSite(cookie.SameSiteMode())
+    c.SetCookie(cookie.Name, value, maxAge, cookie.Path, cookie.Domain, cookie.Secure, cookie.HTTPOnly)
+}
+
+// message mengambil pesan response dalam bahasa request
+func message(c *gin.Context, key string) string {
+    return i18n.T(i18n.FromContext(c.Request.Context()), key)
+}
+
+// applyUserLocale memakai preferensi bahasa pengguna untuk sisa request
+func applyUserLocale(c *gin.Context, locale string) {
+    if locale != "" {
+        middleware.SetLocale(c, locale)
+    }
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"auth-service/internal/openapi"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DocsHandler menyajikan spesifikasi OpenAPI dan halaman dokumentasinya
+type DocsHandler struct {
+    spec []byte
+}
+
+// NewDocsHandler membuat instance baru DocsHandler
+func NewDocsHandler() (*DocsHandler, error) {
+    spec, err := openapi.JSON()
+    if err != nil {
+        return nil, err
+    }
+    return &DocsHandler{spec: spec}, nil
+}
+
+// Spec menyajikan /openapi.json
+func (h *DocsHandler) Spec(c *gin.Context) {
+    c.Data(http.StatusOK, "application/json", h.spec)
+}
+
+// UI menyajikan halaman dokumentasi yang merender /openapi.json
+func (h *DocsHandler) UI(c *gin.Context) {
+    c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.DocsHTML())
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/middleware"
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Header identitas yang dikembalikan /auth/forward jika akses diizinkan.
+// Reverse proxy meneruskannya ke aplikasi upstream. Untuk service account,
+// X-User-Id berisi ID service account dan X-Principal-Type bernilai service.
+// X-Impersonator-Id (ID admin) hanya ada untuk token impersonation.
+const (
+    HeaderUserID         = "X-User-Id"
+    HeaderUserEmail      = "X-User-Email"
+    HeaderUserRole       = "X-User-Role"
+    HeaderPrincipalType  = "X-Principal-Type"
+    HeaderImpersonatorID = "X-Impersonator-Id"
+)
+
+// Header persyaratan akses; setara dengan query parameter role, permission
+// dan audience
+const (
+    HeaderRequiredRole       = "X-Auth-Required-Role"
+    HeaderRequiredPermission = "X-Auth-Required-Permission"
+    HeaderRequiredAudience   = "X-Auth-Required-Audience"
+)
+
+// ForwardAuthHandler menangani subrequest autentikasi dari reverse proxy
+// (nginx auth_request, Traefik ForwardAuth)
+type ForwardAuthHandler struct {
+    authService   service.AuthService
+    apiKeyService service.APIKeyService
+    cookieName    string
+    security      config.SecurityConfig
+    loginURL      string
+}
+
+// NewForwardAuthHandler membuat instance baru ForwardAuthHandler.
+// apiKeyService boleh nil jika API key dinonaktifkan.
+func NewForwardAuthHandler(authService service.AuthService, apiKeyService service.APIKeyService, cookieName string, security config.SecurityConfig, forwardAuth config.ForwardAuthConfig) *ForwardAuthHandler {
+    return &ForwardAuthHandler{
+        authService:   authService,
+        apiKeyService: apiKeyService,
+        cookieName:    cookieName,
+        security:      security,
+        loginURL:      forwardAuth.LoginURL,
+    }
+}
+
+// Forward memvalidasi token dari cookie atau header Authorization request
+// asal (Bearer atau ApiKey). Jika valid dan memenuhi persyaratan
+// role/permission, response 200 membawa header identitas pengguna. Request
+// tanpa token valid mendapat 401, atau redirect ke halaman login jika
+// redirect=true dan login_url diatur; persyaratan yang tidak terpenuhi
+// mendapat 403. Token ber-aud (hasil token exchange) hanya diterima jika
+// audience upstream dinyatakan dan cocok.
+func (h *ForwardAuthHandler) Forward(c *gin.Context) {
+    // Response bergantung pada cookie/token sehingga tidak boleh di-cache proxy
+    c.Header("Cache-Control", "no-store")
+
+    claims, err := h.authenticate(c)
+    if err != nil {
+        if h.loginURL != "" && wantsRedirect(c) {
+            c.Redirect(http.StatusFound, h.loginRedirect(c))
+            return
+        }
+        _ = c.Error(err)
+        return
+    }
+    applyUserLocale(c, claims.Locale)
+
+    // Audience dari query dan header harus cocok semuanya; tanpa audience,
+    // token ber-aud ditolak
+    audiences := append(splitList(c.QueryArray("audience")), splitList(c.Request.Header.Values(HeaderRequiredAudience))...)
+    if len(audiences) == 0 {
+        audiences = []string{""}
+    }
+    for _, audience := range audiences {
+        if !claims.ValidForAudience(audience) {
+            _ = c.Error(model.ErrForbidden)
+            return
+        }
+    }
+
+    // Role: cukup salah satu dari daftar; permission: semua harus dimiliki.
+    // Query dan header diperiksa terpisah karena proxy seperti Traefik ikut
+    // meneruskan header klien, sehingga header hanya boleh mempersempit akses.
+    // Role admin juga membutuhkan scope admin, sama seperti endpoint /api/admin.
+    for _, roles := range [][]string{splitList(c.QueryArray("role")), splitList(c.Request.Header.Values(HeaderRequiredRole))} {
+        if len(roles) > 0 && !claims.HasRequiredRole(roles, h.security.AdminRoles) {
+            _ = c.Error(model.ErrForbidden)
+            return
+        }
+    }
+    permissions := append(splitList(c.QueryArray("permission")), splitList(c.Request.Header.Values(HeaderRequiredPermission))...)
+    for _, permission := range permissions {
+        if !h.security.HasPermission(claims.Role, permission) || !claims.HasScope(permission) {
+            _ = c.Error(model.ErrForbidden)
+            return
+        }
+    }
+
+    c.Header(HeaderUserID, strconv.FormatInt(claims.UserID, 10))
+    c.Header(HeaderUserEmail, claims.Email)
+    c.Header(HeaderUserRole, claims.Role)
+    c.Header(HeaderPrincipalType, claims.Principal().Type)
+    if claims.IsImpersonated() {
+        c.Header(HeaderImpersonatorID, strconv.FormatInt(claims.Impersonator().UserID, 10))
+    }
+    c.Status(http.StatusOK)
+}
+
+// authenticate memvalidasi API key atau token dari request asal
+func (h *ForwardAuthHandler) authenticate(c *gin.Context) (*model.JWTClaims, error) {
+    if key, ok := middleware.APIKeyFromRequest(c); ok && h.apiKeyService != nil {
+        return h.apiKeyService.Authenticate(c.Request.Context(), key)
+    }
+
+    tokenString, err := middleware.TokenFromRequest(c, h.cookieName)
+    if err != nil {
+        return nil, err
+    }
+    return h.authService.ValidateToken(c.Request.Context(), tokenString)
+}
+
+// loginRedirect URL login dengan URL asal request sebagai parameter "rd"
+func (h *ForwardAuthHandler) loginRedirect(c *gin.Context) string {
+    target, err := url.Parse(h.loginURL)
+    if err != nil {
+        return h.loginURL
+    }
+    if original := originalURL(c); original != "" {
+        query := target.Query()
+        query.Set("rd", original)
+        target.RawQuery = query.Encode()
+    }
+    return target.String()
+}
+
+// wantsRedirect memeriksa query parameter redirect. nginx auth_request hanya
+// menerima 2xx, 401 dan 403 sehingga redirect harus diminta secara eksplisit
+// (misalnya di alamat ForwardAuth Traefik).
+func wantsRedirect(c *gin.Context) bool {
+    redirect, _ := strconv.ParseBool(c.Query("redirect"))
+    return redirect
+}
+
+// originalURL merekonstruksi URL yang diminta klien dari header reverse
+// proxy: X-Original-URL (nginx) atau X-Forwarded-Proto/Host/Uri (Traefik)
+func originalURL(c *gin.Context) string {
+    if original := c.GetHeader("X-Original-URL"); original != "" {
+        return original
+    }
+
+    host := c.GetHeader("X-Forwarded-Host")
+    if host == "" {
+        return ""
+    }
+    proto := c.GetHeader("X-Forwarded-Proto")
+    if proto == "" {
+        proto = "https"
+    }
+    return proto + "://" + host + c.GetHeader("X-Forwarded-Uri")
+}
+
+// splitList menggabungkan nilai query parameter atau header yang boleh
+// berulang dan dipisahkan koma
+func splitList(raws []string) []string {
+    var values []string
+    for _, raw := range raws {
+        for _, value := range strings.Split(raw, ",") {
+            if value = strings.TrimSpace(value); value != "" {
+                values = append(values, value)
+            }
+        }
+    }
+    return values
+}
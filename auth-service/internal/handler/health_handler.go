@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"auth-service/internal/health"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler menangani endpoint liveness dan readiness
+type HealthHandler struct {
+    checker *health.Checker
+}
+
+// NewHealthHandler membuat instance baru HealthHandler
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+    return &HealthHandler{checker: checker}
+}
+
+// Livez menandakan proses masih hidup; tidak memeriksa dependency agar
+// gangguan database tidak membuat orchestrator me-restart pod
+func (h *HealthHandler) Livez(c *gin.Context) {
+    c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// Readyz menjalankan pemeriksaan dependency dan mengembalikan 503 jika ada
+// yang gagal atau server sedang shutdown
+func (h *HealthHandler) Readyz(c *gin.Context) {
+    report := h.checker.Check(c.Request.Context())
+
+    status := http.StatusOK
+    if report.Status != health.StatusOK {
+        status = http.StatusServiceUnavailable
+    }
+    c.JSON(status, report)
+}
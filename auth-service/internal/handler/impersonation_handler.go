@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/i18n"
+	"auth-service/internal/middleware"
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// impersonatorCookieSuffix akhiran nama cookie yang menyimpan token admin
+// selama impersonation lewat cookie
+const impersonatorCookieSuffix = "_impersonator"
+
+// ImpersonationHandler menangani awal dan akhir impersonation pengguna oleh admin
+type ImpersonationHandler struct {
+    impersonationService service.ImpersonationService
+    cookie               config.CookieConfig
+    tokenTTL             time.Duration
+    stopPath             string
+}
+
+// NewImpersonationHandler membuat instance baru ImpersonationHandler.
+// stopPath path endpoint Stop; cookie penyimpan token admin hanya dikirim
+// browser ke path ini.
+func NewImpersonationHandler(impersonationService service.ImpersonationService, cookie config.CookieConfig, tokenTTL time.Duration, stopPath string) *ImpersonationHandler {
+    return &ImpersonationHandler{
+        impersonationService: impersonationService,
+        cookie:               cookie,
+        tokenTTL:             tokenTTL,
+        stopPath:             stopPath,
+    }
+}
+
+// Start menangani permintaan admin untuk meng-impersonate pengguna. Token
+// impersonation selalu dikembalikan di body. Jika admin login dengan cookie,
+// cookie session diganti token impersonation sehingga browser admin langsung
+// melihat aplikasi sebagai pengguna tersebut; token admin disimpan di cookie
+// terpisah dan dipulihkan oleh Stop. Sesi admin tidak dicabut.
+func (h *ImpersonationHandler) Start(c *gin.Context) {
+    jwtClaims, err := userSessionClaims(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        _ = c.Error(model.NewValidationError(errors.New("invalid user ID")))
+        return
+    }
+
+    user, token, err := h.impersonationService.Start(c.Request.Context(), jwtClaims, userID)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    if adminToken, err := c.Cookie(h.cookie.Name); err == nil && adminToken != "" {
+        h.writeImpersonatorCookie(c, adminToken, int(h.tokenTTL.Seconds()))
+        writeTokenCookie(c, h.cookie, token, int(h.tokenTTL.Seconds()))
+    }
+    c.JSON(http.StatusOK, gin.H{
+        "message":    message(c, i18n.MessageImpersonationStarted),
+        "user":       user,
+        "token":      token,
+        "expires_in": int64(h.tokenTTL.Seconds()),
+    })
+}
+
+// Stop menangani akhir impersonation: token impersonation dicabut dan, jika
+// berasal dari cookie, cookie session dikembalikan ke token admin yang
+// disimpan Start (atau dihapus jika tidak ada)
+func (h *ImpersonationHandler) Stop(c *gin.Context) {
+    jwtClaims, err := claimsFromContext(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+    if !jwtClaims.IsImpersonated() || jwtClaims.IsDelegated() {
+        _ = c.Error(model.ErrNotImpersonating)
+        return
+    }
+
+    token, err := middleware.TokenFromRequest(c, h.cookie.Name)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+    if err := h.impersonationService.Stop(c.Request.Context(), token); err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    if cookie, err := c.Cookie(h.cookie.Name); err == nil && cookie == token {
+        if adminToken, err := c.Cookie(h.cookie.Name + impersonatorCookieSuffix); err == nil && adminToken != "" {
+            // Cookie sesi browser; masa berlaku token admin tetap diperiksa dari exp
+            writeTokenCookie(c, h.cookie, adminToken, 0)
+            h.writeImpersonatorCookie(c, "", -1)
+        } else {
+            writeTokenCookie(c, h.cookie, "", -1)
+        }
+    }
+    c.JSON(http.StatusOK, gin.H{
+        "message": message(c, i18n.MessageImpersonationStopped),
+    })
+}
+
+// writeImpersonatorCookie menulis cookie penyimpan token admin dengan atribut
+// yang sama seperti cookie session, tetapi hanya untuk path endpoint Stop
+func (h *ImpersonationHandler) writeImpersonatorCookie(c *gin.Context, value string, maxAge int) {
+    cookie := h.cookie
+    cookie.Name += impersonatorCookieSuffix
+    cookie.Path = h.stopPath
+    writeTokenCookie(c, cookie, value, maxAge)
+}
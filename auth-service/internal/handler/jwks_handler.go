@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"auth-service/internal/signing"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler menyajikan public key penandatangan JWT
+type JWKSHandler struct {
+    keys *signing.Keys
+}
+
+// NewJWKSHandler membuat instance baru JWKSHandler
+func NewJWKSHandler(keys *signing.Keys) *JWKSHandler {
+    return &JWKSHandler{keys: keys}
+}
+
+// JWKS menyajikan /.well-known/jwks.json. Untuk HS256 daftar key kosong
+// karena token hanya dapat diverifikasi dengan secret bersama.
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+    c.Header("Cache-Control", "public, max-age=300")
+    c.JSON(http.StatusOK, h.keys.JWKS())
+}
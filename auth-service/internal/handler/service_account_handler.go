@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"auth-service/internal/i18n"
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceAccountHandler menangani pengelolaan service account oleh admin,
+// termasuk API key milik service account
+type ServiceAccountHandler struct {
+    serviceAccountService service.ServiceAccountService
+    apiKeyService         service.APIKeyService
+}
+
+// NewServiceAccountHandler membuat instance baru ServiceAccountHandler.
+// apiKeyService boleh nil jika API key tidak diaktifkan.
+func NewServiceAccountHandler(serviceAccountService service.ServiceAccountService, apiKeyService service.APIKeyService) *ServiceAccountHandler {
+    return &ServiceAccountHandler{
+        serviceAccountService: serviceAccountService,
+        apiKeyService:         apiKeyService,
+    }
+}
+
+// Create menangani pembuatan service account. Client secret hanya ada di
+// response ini.
+func (h *ServiceAccountHandler) Create(c *gin.Context) {
+    jwtClaims, err := claimsFromContext(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    var req model.CreateServiceAccountRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        _ = c.Error(model.NewValidationError(err))
+        return
+    }
+
+    account, err := h.serviceAccountService.Create(c.Request.Context(), jwtClaims.UserID, &req)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message":         message(c, i18n.MessageServiceAccountCreated),
+        "service_account": account,
+    })
+}
+
+// List menangani daftar service account
+func (h *ServiceAccountHandler) List(c *gin.Context) {
+    accounts, err := h.serviceAccountService.List(c.Request.Context())
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "service_accounts": accounts,
+    })
+}
+
+// Get menangani detail satu service account
+func (h *ServiceAccountHandler) Get(c *gin.Context) {
+    id, err := serviceAccountID(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    account, err := h.serviceAccountService.Get(c.Request.Context(), id)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "service_account": account,
+    })
+}
+
+// Update menangani perubahan description, role atau status disabled
+func (h *ServiceAccountHandler) Update(c *gin.Context) {
+    jwtClaims, err := claimsFromContext(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    id, err := serviceAccountID(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    var req model.UpdateServiceAccountRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        _ = c.Error(model.NewValidationError(err))
+        return
+    }
+
+    account, err := h.serviceAccountService.Update(c.Request.Context(), jwtClaims.UserID, id, &req)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":         message(c, i18n.MessageServiceAccountUpdated),
+        "service_account": account,
+    })
+}
+
+// Delete menangani penghapusan service account beserta API key miliknya
+func (h *ServiceAccountHandler) Delete(c *gin.Context) {
+    jwtClaims, err := claimsFromContext(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    id, err := serviceAccountID(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    if err := h.serviceAccountService.Delete(c.Request.Context(), jwtClaims.UserID, id); err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": message(c, i18n.MessageServiceAccountDeleted),
+    })
+}
+
+// RotateSecret menangani pembuatan client secret baru
+func (h *ServiceAccountHandler) RotateSecret(c *gin.Context) {
+    jwtClaims, err := claimsFromContext(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    id, err := serviceAccountID(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    account, err := h.serviceAccountService.RotateSecret(c.Request.Context(), jwtClaims.UserID, id)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message":         message(c, i18n.MessageServiceAccountRotated),
+        "service_account": account,
+    })
+}
+
+// CreateAPIKey menangani pembuatan API key untuk service account; scope
+// dibatasi role service account, bukan role admin
+func (h *ServiceAccountHandler) CreateAPIKey(c *gin.Context) {
+    jwtClaims, err := claimsFromContext(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    id, err := serviceAccountID(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    var req model.CreateAPIKeyRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        _ = c.Error(model.NewValidationError(err))
+        return
+    }
+
+    key, err := h.apiKeyService.Create(c.Request.Context(), jwtClaims.UserID, model.ServicePrincipal(id), &req)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "message": message(c, i18n.MessageAPIKeyCreated),
+        "api_key": key,
+    })
+}
+
+// ListAPIKeys menangani daftar API key milik service account
+func (h *ServiceAccountHandler) ListAPIKeys(c *gin.Context) {
+    id, err := serviceAccountID(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    // Service account yang tidak ada menghasilkan 404, bukan daftar kosong
+    if _, err := h.serviceAccountService.Get(c.Request.Context(), id); err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    keys, err := h.apiKeyService.List(c.Request.Context(), model.ServicePrincipal(id))
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "api_keys": keys,
+    })
+}
+
+// DeleteAPIKey menangani pencabutan API key milik service account
+func (h *ServiceAccountHandler) DeleteAPIKey(c *gin.Context) {
+    jwtClaims, err := claimsFromContext(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    id, err := serviceAccountID(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    keyID, err := strconv.ParseInt(c.Param("keyId"), 10, 64)
+    if err != nil {
+        _ = c.Error(model.NewValidationError(errors.New("invalid API key ID")))
+        return
+    }
+
+    if err := h.apiKeyService.Delete(c.Request.Context(), jwtClaims.UserID, model.ServicePrincipal(id), keyID); err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "message": message(c, i18n.MessageAPIKeyDeleted),
+    })
+}
+
+// serviceAccountID membaca path parameter :id
+func serviceAccountID(c *gin.Context) (int64, error) {
+    id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+    if err != nil {
+        return 0, model.NewValidationError(errors.New("invalid service account ID"))
+    }
+    return id, nil
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokenHandler menangani token endpoint OAuth 2.0 (RFC 6749)
+type TokenHandler struct {
+    serviceAccountService service.ServiceAccountService
+    tokenExchangeService  service.TokenExchangeService
+}
+
+// NewTokenHandler membuat instance baru TokenHandler. tokenExchangeService
+// boleh nil jika token exchange tidak diaktifkan.
+func NewTokenHandler(serviceAccountService service.ServiceAccountService, tokenExchangeService service.TokenExchangeService) *TokenHandler {
+    return &TokenHandler{
+        serviceAccountService: serviceAccountService,
+        tokenExchangeService:  tokenExchangeService,
+    }
+}
+
+// Token menangani request token berformat application/x-www-form-urlencoded.
+// Grant yang didukung adalah client_credentials dan token exchange (RFC
+// 8693); keduanya untuk service account yang mengautentikasi diri dengan
+// HTTP Basic atau parameter client_id dan client_secret. Error ditulis dalam
+// format OAuth 2.0 oleh middleware.OAuthErrorHandler.
+func (h *TokenHandler) Token(c *gin.Context) {
+    // Response token tidak boleh disimpan cache (RFC 6749 5.1)
+    c.Header("Cache-Control", "no-store")
+    c.Header("Pragma", "no-cache")
+
+    grantType := c.PostForm("grant_type")
+    var exchange *model.TokenExchangeRequest
+    switch {
+    case grantType == "":
+        _ = c.Error(model.NewValidationError(errors.New("grant_type is required")))
+        return
+    case grantType == model.GrantTypeClientCredentials:
+    case grantType == model.GrantTypeTokenExchange && h.tokenExchangeService != nil:
+        var err error
+        if exchange, err = tokenExchangeRequest(c); err != nil {
+            _ = c.Error(err)
+            return
+        }
+    default:
+        _ = c.Error(fmt.Errorf("%w: %q", model.ErrUnsupportedGrantType, grantType))
+        return
+    }
+
+    clientID, clientSecret, basic, err := clientCredentials(c)
+    if err != nil {
+        _ = c.Error(err)
+        return
+    }
+
+    var token *model.TokenResponse
+    if exchange != nil {
+        token, err = h.tokenExchangeService.Exchange(c.Request.Context(), clientID, clientSecret, exchange)
+    } else {
+        token, err = h.serviceAccountService.IssueToken(c.Request.Context(), clientID, clientSecret, c.PostForm("scope"))
+    }
+    if err != nil {
+        if basic && errors.Is(err, model.ErrInvalidClient) {
+            c.Header("WWW-Authenticate", `Basic realm="auth-service"`)
+        }
+        _ = c.Error(err)
+        return
+    }
+
+    c.JSON(http.StatusOK, token)
+}
+
+// tokenExchangeRequest membaca parameter token exchange (RFC 8693 2.1).
+// Parameter resource tidak didukung; layanan tujuan dinyatakan dengan audience.
+func tokenExchangeRequest(c *gin.Context) (*model.TokenExchangeRequest, error) {
+    req := &model.TokenExchangeRequest{
+        SubjectToken:     c.PostForm("subject_token"),
+        SubjectTokenType: c.PostForm("subject_token_type"),
+        ActorToken:       c.PostForm("actor_token"),
+        ActorTokenType:   c.PostForm("actor_token_type"),
+        Audience:         c.PostFormArray("audience"),
+        Scope:            c.PostForm("scope"),
+    }
+
+    switch {
+    case req.SubjectToken == "":
+        return nil, model.NewValidationError(errors.New("subject_token is required"))
+    case !isExchangeTokenType(req.SubjectTokenType):
+        return nil, model.NewValidationError(errors.New("subject_token_type must be an access_token or jwt token type"))
+    case req.ActorToken == "" && req.ActorTokenType != "":
+        return nil, model.NewValidationError(errors.New("actor_token_type requires actor_token"))
+    case req.ActorToken != "" && !isExchangeTokenType(req.ActorTokenType):
+        return nil, model.NewValidationError(errors.New("actor_token_type must be an access_token or jwt token type"))
+    case c.PostForm("resource") != "":
+        return nil, fmt.Errorf("%w: resource is not supported, use audience", model.ErrInvalidTarget)
+    case len(req.Audience) == 0 || slices.Contains(req.Audience, ""):
+        return nil, model.NewValidationError(errors.New("audience is required"))
+    }
+    return req, nil
+}
+
+// isExchangeTokenType memeriksa jenis token yang dapat ditukar
+func isExchangeTokenType(tokenType string) bool {
+    return tokenType == model.TokenTypeAccessToken || tokenType == model.TokenTypeJWT
+}
+
+// clientCredentials mengambil client ID dan secret dari header HTTP Basic
+// (di-encode form-urlencoded sesuai RFC 6749 2.3.1) atau dari body form.
+// basic bernilai true jika kredensial berasal dari header.
+func clientCredentials(c *gin.Context) (clientID, clientSecret string, basic bool, err error) {
+    if user, pass, ok := c.Request.BasicAuth(); ok {
+        clientID, err = url.QueryUnescape(user)
+        if err == nil {
+            clientSecret, err = url.QueryUnescape(pass)
+        }
+        if err != nil {
+            return "", "", true, model.ErrInvalidClient
+        }
+        return clientID, clientSecret, true, nil
+    }
+
+    clientID, clientSecret = c.PostForm("client_id"), c.PostForm("client_secret")
+    if clientID == "" || clientSecret == "" {
+        return "", "", false, model.ErrInvalidClient
+    }
+    return clientID, clientSecret, false, nil
+}
@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status hasil pemeriksaan
+const (
+    StatusOK   = "ok"
+    StatusFail = "fail"
+)
+
+// CheckFunc memeriksa satu dependency; error berarti dependency tidak siap
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult hasil satu pemeriksaan dependency
+type CheckResult struct {
+    Status   string `json:"status"`
+    Error    string `json:"error,omitempty"`
+    Duration string `json:"duration"`
+}
+
+// Report ringkasan readiness beserta rincian per pemeriksaan
+type Report struct {
+    Status       string                 `json:"status"`
+    ShuttingDown bool                   `json:"shutting_down,omitempty"`
+    Checks       map[string]CheckResult `json:"checks"`
+}
+
+type namedCheck struct {
+    name  string
+    check CheckFunc
+}
+
+// Checker menjalankan pemeriksaan readiness. Setelah SetShuttingDown dipanggil,
+// readiness selalu gagal agar load balancer berhenti mengirim request baru
+// selama drain period graceful shutdown.
+type Checker struct {
+    timeout      time.Duration
+    checks       []namedCheck
+    shuttingDown atomic.Bool
+}
+
+// NewChecker membuat instance baru Checker; timeout berlaku untuk tiap pemeriksaan
+func NewChecker(timeout time.Duration) *Checker {
+    return &Checker{timeout: timeout}
+}
+
+// Add mendaftarkan pemeriksaan dependency. Tidak aman dipanggil bersamaan
+// dengan Check, jadi daftarkan semua pemeriksaan sebelum server berjalan.
+func (c *Checker) Add(name string, check CheckFunc) {
+    c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// SetShuttingDown menandai bahwa server sedang shutdown
+func (c *Checker) SetShuttingDown() {
+    c.shuttingDown.Store(true)
+}
+
+// Check menjalankan semua pemeriksaan secara paralel, masing-masing dengan timeout
+func (c *Checker) Check(ctx context.Context) Report {
+    report := Report{
+        Status:       StatusOK,
+        ShuttingDown: c.shuttingDown.Load(),
+        Checks:       make(map[string]CheckResult, len(c.checks)),
+    }
+
+    var mu sync.Mutex
+    var wg sync.WaitGroup
+    for _, nc := range c.checks {
+        wg.Add(1)
+        go func(nc namedCheck) {
+            defer wg.Done()
+            result := c.run(ctx, nc.check)
+
+            mu.Lock()
+            defer mu.Unlock()
+            report.Checks[nc.name] = result
+            if result.Status != StatusOK {
+                report.Status = StatusFail
+            }
+        }(nc)
+    }
+    wg.Wait()
+
+    if report.ShuttingDown {
+        report.Status = StatusFail
+    }
+    return report
+}
+
+// run menjalankan satu pemeriksaan dengan timeout
+func (c *Checker) run(ctx context.Context, check CheckFunc) CheckResult {
+    ctx, cancel := context.WithTimeout(ctx, c.timeout)
+    defer cancel()
+
+    start := time.Now()
+    err := check(ctx)
+    result := CheckResult{Status: StatusOK, Duration: time.Since(start).String()}
+    if err != nil {
+        result.Status = StatusFail
+        result.Error = err.Error()
+    }
+    return result
+}
@@ -0,0 +1,86 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+const (
+    // English locale bahasa Inggris, juga fallback jika pesan tidak ditemukan
+    English = "en"
+    // Indonesian locale bahasa Indonesia
+    Indonesian = "id"
+)
+
+// locales bahasa yang didukung; urutannya harus sama dengan tag pada matcher
+var locales = []string{English, Indonesian}
+
+var matcher = language.NewMatcher([]language.Tag{language.English, language.Indonesian})
+
+// IsSupported memeriksa apakah locale memiliki katalog pesan
+func IsSupported(locale string) bool {
+    _, ok := catalogs[locale]
+    return ok
+}
+
+// Negotiate memilih locale yang didukung dari header Accept-Language.
+// Mengembalikan string kosong jika header kosong, tidak valid, atau tidak
+// ada bahasa yang cocok.
+func Negotiate(acceptLanguage string) string {
+    if acceptLanguage == "" {
+        return ""
+    }
+
+    tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+    if err != nil || len(tags) == 0 {
+        return ""
+    }
+    _, index, confidence := matcher.Match(tags...)
+    if confidence == language.No {
+        return ""
+    }
+    return locales[index]
+}
+
+// T mengembalikan pesan untuk key dalam locale tertentu. Pesan yang tidak
+// ada di locale tersebut diambil dari katalog bahasa Inggris; jika tetap
+// tidak ada, key dikembalikan apa adanya. args diformat dengan fmt.Sprintf.
+func T(locale, key string, args ...interface{}) string {
+    message, ok := catalogs[locale][key]
+    if !ok {
+        message, ok = catalogs[English][key]
+    }
+    if !ok {
+        return key
+    }
+    if len(args) > 0 {
+        return fmt.Sprintf(message, args...)
+    }
+    return message
+}
+
+// Has memeriksa apakah key tersedia di locale atau di katalog bahasa Inggris
+func Has(locale, key string) bool {
+    if _, ok := catalogs[locale][key]; ok {
+        return true
+    }
+    _, ok := catalogs[English][key]
+    return ok
+}
+
+type localeKey struct{}
+
+// WithLocale menyimpan locale request ke context
+func WithLocale(ctx context.Context, locale string) context.Context {
+    return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext mengambil locale request dari context (default bahasa Inggris)
+func FromContext(ctx context.Context) string {
+    if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+        return locale
+    }
+    return English
+}
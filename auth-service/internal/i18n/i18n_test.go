@@ -0,0 +1,31 @@
+package i18n
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"id", Indonesian},
+		{"id-ID,id;q=0.9,en;q=0.8", Indonesian},
+		{"en-GB,en;q=0.9", English},
+		{"fr-FR,id;q=0.5", Indonesian},
+		{"fr", ""},
+		{"not a language tag;;", ""},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	for key := range catalogs[English] {
+		if _, ok := catalogs[Indonesian][key]; !ok {
+			t.Errorf("key %q has no Indonesian translation", key)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package i18n
+
+// Key pesan response sukses handler
+const (
+    MessageRegistered    = "message.registered"
+    MessageLoggedIn      = "message.logged_in"
+    MessageLoggedOut     = "message.logged_out"
+    MessageRoleUpdated   = "message.role_updated"
+    MessageLocaleUpdated = "message.locale_updated"
+    MessageAPIKeyCreated = "message.api_key_created"
+    MessageAPIKeyDeleted = "message.api_key_deleted"
+
+    MessageServiceAccountCreated = "message.service_account_created"
+    MessageServiceAccountUpdated = "message.service_account_updated"
+    MessageServiceAccountDeleted = "message.service_account_deleted"
+    MessageServiceAccountRotated = "message.service_account_secret_rotated"
+
+    MessageImpersonationStarted = "message.impersonation_started"
+    MessageImpersonationStopped = "message.impersonation_stopped"
+)
+
+// ProblemTitleKey key judul Problem untuk kode error
+func ProblemTitleKey(code string) string {
+    return "problem." + code + ".title"
+}
+
+// ProblemDetailKey key detail Problem untuk kode error
+func ProblemDetailKey(code string) string {
+    return "problem." + code + ".detail"
+}
+
+// catalogs katalog pesan per locale. Key problem.<kode>.* mengikuti kode
+// error stabil pada response application/problem+json.
+var catalogs = map[string]map[string]string{
+    English: {
+        MessageRegistered:    "User registered successfully",
+        MessageLoggedIn:      "Login successful",
+        MessageLoggedOut:     "Logout successful",
+        MessageRoleUpdated:   "Role updated successfully",
+        MessageLocaleUpdated: "Language preference updated successfully",
+        MessageAPIKeyCreated: "API key created; store it now, it will not be shown again",
+        MessageAPIKeyDeleted: "API key deleted successfully",
+
+        MessageServiceAccountCreated: "Service account created; store the client secret now, it will not be shown again",
+        MessageServiceAccountUpdated: "Service account updated successfully",
+        MessageServiceAccountDeleted: "Service account deleted successfully",
+        MessageServiceAccountRotated: "Client secret rotated; store it now, it will not be shown again",
+
+        MessageImpersonationStarted: "Impersonation started; sensitive actions are disabled until it ends",
+        MessageImpersonationStopped: "Impersonation ended",
+
+        "problem.email_taken.title":              "Email already registered",
+        "problem.email_taken.detail":             "An account with this email address already exists.",
+        "problem.invalid_credentials.title":      "Invalid credentials",
+        "problem.invalid_credentials.detail":     "The email or password is incorrect.",
+        "problem.user_not_found.title":           "User not found",
+        "problem.user_not_found.detail":          "The requested user does not exist.",
+        "problem.invalid_role.title":             "Invalid role",
+        "problem.invalid_role.detail":            "The role is not one of the configured roles.",
+        "problem.authentication_required.title":  "Authentication required",
+        "problem.authentication_required.detail": "This endpoint requires a valid token.",
+        "problem.token_expired.title":            "Token expired",
+        "problem.token_expired.detail":           "The token has expired, please log in again.",
+        "problem.token_revoked.title":            "Token revoked",
+        "problem.token_revoked.detail":           "The token has been revoked, please log in again.",
+        "problem.token_invalid.title":            "Invalid token",
+        "problem.token_invalid.detail":           "The token is malformed or its signature is invalid.",
+        "problem.forbidden.title":                "Forbidden",
+        "problem.forbidden.detail":               "You do not have permission to access this resource.",
+        "problem.api_key_not_found.title":        "API key not found",
+        "problem.api_key_not_found.detail":       "The requested API key does not exist.",
+        "problem.invalid_scope.title":            "Invalid scope",
+        "problem.invalid_scope.detail":           "The scope is unknown or not granted to your account.",
+        "problem.api_key_limit_reached.title":    "API key limit reached",
+        "problem.api_key_limit_reached.detail":   "Delete an existing API key before creating a new one.",
+        "problem.request_too_large.title":        "Request too large",
+        "problem.request_too_large.detail":       "The request body exceeds the allowed size.",
+        "problem.not_found.title":                "Not found",
+        "problem.not_found.detail":               "The requested resource does not exist.",
+        "problem.method_not_allowed.title":       "Method not allowed",
+        "problem.method_not_allowed.detail":      "The HTTP method is not supported for this resource.",
+        "problem.validation_failed.title":        "Validation failed",
+        "problem.validation_failed.detail":       "The request contains invalid fields.",
+        "problem.invalid_request.detail":         "The request is invalid: %s",
+        "problem.internal_error.title":           "Internal server error",
+
+        "problem.service_account_not_found.title":   "Service account not found",
+        "problem.service_account_not_found.detail":  "The requested service account does not exist.",
+        "problem.service_account_name_taken.title":  "Service account name taken",
+        "problem.service_account_name_taken.detail": "A service account with this name already exists.",
+        "problem.invalid_client.title":              "Invalid client",
+        "problem.invalid_client.detail":             "The client ID or secret is incorrect, or the client is disabled.",
+        "problem.unsupported_grant_type.title":      "Unsupported grant type",
+        "problem.unsupported_grant_type.detail":     "The grant_type is not supported by this token endpoint.",
+
+        "problem.impersonation_not_allowed.title":  "Impersonation not allowed",
+        "problem.impersonation_not_allowed.detail": "Admins cannot impersonate themselves or other admins.",
+        "problem.impersonation_restricted.title":   "Not allowed while impersonating",
+        "problem.impersonation_restricted.detail":  "This action cannot be performed with an impersonation token.",
+        "problem.not_impersonating.title":          "Not impersonating",
+        "problem.not_impersonating.detail":         "The token used is not an impersonation token.",
+
+        "problem.invalid_grant.title":        "Invalid grant",
+        "problem.invalid_grant.detail":       "The subject or actor token is invalid, expired or revoked.",
+        "problem.invalid_target.title":       "Invalid target",
+        "problem.invalid_target.detail":      "The requested audience is not allowed for this client or token.",
+        "problem.unauthorized_client.title":  "Unauthorized client",
+        "problem.unauthorized_client.detail": "The client is not allowed to use this grant type.",
+    },
+    Indonesian: {
+        MessageRegistered:    "Pengguna berhasil didaftarkan",
+        MessageLoggedIn:      "Login berhasil",
+        MessageLoggedOut:     "Logout berhasil",
+        MessageRoleUpdated:   "Role berhasil diubah",
+        MessageLocaleUpdated: "Preferensi bahasa berhasil diubah",
+        MessageAPIKeyCreated: "API key berhasil dibuat; simpan sekarang, key tidak akan ditampilkan lagi",
+        MessageAPIKeyDeleted: "API key berhasil dihapus",
+
+        MessageServiceAccountCreated: "Service account berhasil dibuat; simpan client secret sekarang, secret tidak akan ditampilkan lagi",
+        MessageServiceAccountUpdated: "Service account berhasil diubah",
+        MessageServiceAccountDeleted: "Service account berhasil dihapus",
+        MessageServiceAccountRotated: "Client secret berhasil diganti; simpan sekarang, secret tidak akan ditampilkan lagi",
+
+        MessageImpersonationStarted: "Impersonation dimulai; aksi sensitif dinonaktifkan sampai impersonation berakhir",
+        MessageImpersonationStopped: "Impersonation diakhiri",
+
+        "problem.email_taken.title":              "Email sudah terdaftar",
+        "problem.email_taken.detail":             "Akun dengan alamat email ini sudah ada.",
+        "problem.invalid_credentials.title":      "Kredensial tidak valid",
+        "problem.invalid_credentials.detail":     "Email atau password salah.",
+        "problem.user_not_found.title":           "Pengguna tidak ditemukan",
+        "problem.user_not_found.detail":          "Pengguna yang diminta tidak ada.",
+        "problem.invalid_role.title":             "Role tidak valid",
+        "problem.invalid_role.detail":            "Role tidak termasuk dalam daftar role yang dikonfigurasi.",
+        "problem.authentication_required.title":  "Autentikasi diperlukan",
+        "problem.authentication_required.detail": "Endpoint ini memerlukan token yang valid.",
+        "problem.token_expired.title":            "Token kedaluwarsa",
+        "problem.token_expired.detail":           "Token sudah kedaluwarsa, silakan login kembali.",
+        "problem.token_revoked.title":            "Token dicabut",
+        "problem.token_revoked.detail":           "Token sudah dicabut, silakan login kembali.",
+        "problem.token_invalid.title":            "Token tidak valid",
+        "problem.token_invalid.detail":           "Format atau tanda tangan token tidak valid.",
+        "problem.forbidden.title":                "Akses ditolak",
+        "problem.forbidden.detail":               "Anda tidak memiliki izin untuk mengakses resource ini.",
+        "problem.api_key_not_found.title":        "API key tidak ditemukan",
+        "problem.api_key_not_found.detail":       "API key yang diminta tidak ada.",
+        "problem.invalid_scope.title":            "Scope tidak valid",
+        "problem.invalid_scope.detail":           "Scope tidak dikenal atau tidak dimiliki akun Anda.",
+        "problem.api_key_limit_reached.title":    "Batas API key tercapai",
+        "problem.api_key_limit_reached.detail":   "Hapus API key yang ada sebelum membuat yang baru.",
+        "problem.request_too_large.title":        "Request terlalu besar",
+        "problem.request_too_large.detail":       "Ukuran body request melebihi batas yang diizinkan.",
+        "problem.not_found.title":                "Tidak ditemukan",
+        "problem.not_found.detail":               "Resource yang diminta tidak ada.",
+        "problem.method_not_allowed.title":       "Method tidak diizinkan",
+        "problem.method_not_allowed.detail":      "Method HTTP ini tidak didukung untuk resource ini.",
+        "problem.validation_failed.title":        "Validasi gagal",
+        "problem.validation_failed.detail":       "Request berisi field yang tidak valid.",
+        "problem.invalid_request.detail":         "Request tidak valid: %s",
+        "problem.internal_error.title":           "Kesalahan internal server",
+
+        "problem.service_account_not_found.title":   "Service account tidak ditemukan",
+        "problem.service_account_not_found.detail":  "Service account yang diminta tidak ada.",
+        "problem.service_account_name_taken.title":  "Nama service account sudah dipakai",
+        "problem.service_account_name_taken.detail": "Service account dengan nama ini sudah ada.",
+        "problem.invalid_client.title":              "Client tidak valid",
+        "problem.invalid_client.detail":             "Client ID atau secret salah, atau client dinonaktifkan.",
+        "problem.unsupported_grant_type.title":      "Grant type tidak didukung",
+        "problem.unsupported_grant_type.detail":     "grant_type tidak didukung oleh token endpoint ini.",
+
+        "problem.impersonation_not_allowed.title":  "Impersonation tidak diizinkan",
+        "problem.impersonation_not_allowed.detail": "Admin tidak dapat meng-impersonate dirinya sendiri atau admin lain.",
+        "problem.impersonation_restricted.title":   "Tidak diizinkan selama impersonation",
+        "problem.impersonation_restricted.detail":  "Aksi ini tidak dapat dilakukan dengan token impersonation.",
+        "problem.not_impersonating.title":          "Bukan impersonation",
+        "problem.not_impersonating.detail":         "Token yang dipakai bukan token impersonation.",
+
+        "problem.invalid_grant.title":        "Grant tidak valid",
+        "problem.invalid_grant.detail":       "Subject token atau actor token tidak valid, kedaluwarsa atau sudah dicabut.",
+        "problem.invalid_target.title":       "Target tidak valid",
+        "problem.invalid_target.detail":      "Audience yang diminta tidak diizinkan untuk client atau token ini.",
+        "problem.unauthorized_client.title":  "Client tidak diizinkan",
+        "problem.unauthorized_client.detail": "Client tidak diizinkan memakai grant type ini.",
+    },
+}
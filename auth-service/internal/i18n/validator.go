@@ -0,0 +1,67 @@
+package i18n
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+)
+
+var universal = ut.New(en.New(), en.New(), id.New())
+
+var (
+    registerOnce sync.Once
+    registerErr  error
+)
+
+// RegisterValidator mendaftarkan terjemahan pesan validasi bawaan untuk semua
+// locale dan memakai nama tag json sebagai nama field, sehingga pesan error
+// menyebut field seperti yang dikirim klien. Validator binding Gin bersifat
+// global, jadi registrasi hanya dilakukan sekali.
+func RegisterValidator(v *validator.Validate) error {
+    registerOnce.Do(func() {
+        v.RegisterTagNameFunc(jsonFieldName)
+
+        enTrans, _ := universal.GetTranslator(English)
+        if registerErr = en_translations.RegisterDefaultTranslations(v, enTrans); registerErr != nil {
+            return
+        }
+        idTrans, _ := universal.GetTranslator(Indonesian)
+        registerErr = id_translations.RegisterDefaultTranslations(v, idTrans)
+    })
+    return registerErr
+}
+
+// RegisterBindingValidator mendaftarkan terjemahan ke validator binding Gin.
+// Dipanggil oleh server HTTP maupun gRPC yang sama-sama memakai binding Gin.
+func RegisterBindingValidator() error {
+    if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+        return RegisterValidator(v)
+    }
+    return nil
+}
+
+// TranslateFieldError menerjemahkan satu error validasi field ke locale
+func TranslateFieldError(locale string, fe validator.FieldError) string {
+    trans, _ := universal.GetTranslator(locale)
+    return fe.Translate(trans)
+}
+
+// jsonFieldName mengambil nama field dari tag json
+func jsonFieldName(field reflect.StructField) string {
+    name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+    if name == "-" {
+        return ""
+    }
+    if name == "" {
+        return field.Name
+    }
+    return name
+}
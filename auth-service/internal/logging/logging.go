@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"auth-service/internal/config"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const redactedValue = "[REDACTED]"
+
+// sensitiveKeys bagian nama atribut yang nilainya selalu disamarkan
+var sensitiveKeys = []string{"password", "token", "secret", "cookie", "authorization", "dsn", "api_key", "apikey"}
+
+// New membuat logger slog sesuai konfigurasi. Setiap baris log otomatis
+// dilengkapi request_id, user_id dan trace_id dari context (jika ada), dan
+// atribut sensitif (password, token, cookie, ...) selalu disamarkan.
+func New(cfg config.LoggingConfig, w io.Writer) (*slog.Logger, error) {
+    var level slog.Level
+    if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+        return nil, fmt.Errorf("invalid log level %q: %v", cfg.Level, err)
+    }
+
+    opts := &slog.HandlerOptions{
+        Level:       level,
+        ReplaceAttr: redactAttr,
+    }
+
+    var handler slog.Handler
+    switch cfg.Format {
+    case "json":
+        handler = slog.NewJSONHandler(w, opts)
+    case "text":
+        handler = slog.NewTextHandler(w, opts)
+    default:
+        return nil, fmt.Errorf("invalid log format %q", cfg.Format)
+    }
+
+    return slog.New(&contextHandler{Handler: handler}), nil
+}
+
+// redactAttr menyamarkan nilai atribut yang namanya mengandung kata sensitif
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+    if IsSensitiveKey(a.Key) {
+        return slog.String(a.Key, redactedValue)
+    }
+    return a
+}
+
+// IsSensitiveKey mengembalikan true jika nama atribut/header dianggap sensitif
+func IsSensitiveKey(key string) bool {
+    key = strings.ToLower(key)
+    for _, sensitive := range sensitiveKeys {
+        if strings.Contains(key, sensitive) {
+            return true
+        }
+    }
+    return false
+}
+
+// contextHandler menambahkan atribut dari context ke setiap record log
+type contextHandler struct {
+    slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+    if requestID := RequestID(ctx); requestID != "" {
+        record.AddAttrs(slog.String("request_id", requestID))
+    }
+    if userID, ok := ctx.Value(userIDKey{}).(int64); ok {
+        record.AddAttrs(slog.Int64("user_id", userID))
+    }
+    if principal, ok := ctx.Value(principalKey{}).(string); ok {
+        record.AddAttrs(slog.String("principal", principal))
+    }
+    if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+        record.AddAttrs(slog.String("trace_id", spanContext.TraceID().String()))
+    }
+    return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+    return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+    return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+type requestIDKey struct{}
+type userIDKey struct{}
+type principalKey struct{}
+
+// WithRequestID menyimpan request ID ke context
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+    return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID mengambil request ID dari context
+func RequestID(ctx context.Context) string {
+    requestID, _ := ctx.Value(requestIDKey{}).(string)
+    return requestID
+}
+
+// WithUserID menyimpan ID pengguna terautentikasi ke context
+func WithUserID(ctx context.Context, userID int64) context.Context {
+    return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// WithPrincipal menyimpan nama principal non-pengguna (misalnya layanan mTLS) ke context
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+    return context.WithValue(ctx, principalKey{}, principal)
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "auth"
+
+// Alasan kegagalan login untuk label "reason"
+const (
+    LoginReasonInvalidRequest  = "invalid_request"
+    LoginReasonUnknownEmail    = "unknown_email"
+    LoginReasonInvalidPassword = "invalid_password"
+    LoginReasonTokenError      = "token_error"
+)
+
+// Hasil validasi token untuk label "result"
+const (
+    TokenValid         = "valid"
+    TokenRevoked       = "revoked"
+    TokenInvalid       = "invalid"
+    TokenInvalidIssuer = "invalid_issuer"
+)
+
+var (
+    // HTTPRequestsTotal jumlah request HTTP per method, route dan status
+    HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "http_requests_total",
+        Help:      "Total number of HTTP requests by method, route and status.",
+    }, []string{"method", "route", "status"})
+
+    // HTTPRequestDuration latensi request HTTP per method, route dan status
+    HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+        Namespace: namespace,
+        Name:      "http_request_duration_seconds",
+        Help:      "HTTP request latency by method, route and status.",
+        Buckets:   prometheus.DefBuckets,
+    }, []string{"method", "route", "status"})
+
+    // LoginSuccessTotal jumlah login yang berhasil
+    LoginSuccessTotal = prometheus.NewCounter(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "login_success_total",
+        Help:      "Total number of successful logins.",
+    })
+
+    // LoginFailureTotal jumlah login yang gagal per alasan
+    LoginFailureTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "login_failure_total",
+        Help:      "Total number of failed logins by reason.",
+    }, []string{"reason"})
+
+    // RegistrationsTotal jumlah registrasi per hasil (success/failure)
+    RegistrationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "registrations_total",
+        Help:      "Total number of user registrations by result.",
+    }, []string{"result"})
+
+    // TokenValidationsTotal jumlah validasi token per hasil
+    TokenValidationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "token_validations_total",
+        Help:      "Total number of token validations by result.",
+    }, []string{"result"})
+
+    // TokenRevocationsTotal jumlah token yang di-revoke (logout)
+    TokenRevocationsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+        Namespace: namespace,
+        Name:      "token_revocations_total",
+        Help:      "Total number of revoked tokens.",
+    })
+
+    // PasswordHashDuration durasi operasi bcrypt (hash dan compare)
+    PasswordHashDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+        Namespace: namespace,
+        Name:      "password_hash_duration_seconds",
+        Help:      "Duration of bcrypt password operations by operation.",
+        Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.2, 0.3, 0.5, 1, 2},
+    }, []string{"operation"})
+)
+
+// NewRegistry membuat registry Prometheus berisi semua metrik aplikasi,
+// metrik runtime Go/proses dan statistik pool sql.DB
+func NewRegistry(db *sql.DB) *prometheus.Registry {
+    registry := prometheus.NewRegistry()
+    registry.MustRegister(
+        collectors.NewGoCollector(),
+        collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+        HTTPRequestsTotal,
+        HTTPRequestDuration,
+        LoginSuccessTotal,
+        LoginFailureTotal,
+        RegistrationsTotal,
+        TokenValidationsTotal,
+        TokenRevocationsTotal,
+        PasswordHashDuration,
+    )
+    if db != nil {
+        registry.MustRegister(collectors.NewDBStatsCollector(db, "auth"))
+    }
+    return registry
+}
+
+// Handler mengembalikan http.Handler untuk endpoint /metrics
+func Handler(registry *prometheus.Registry) http.Handler {
+    return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"auth-service/internal/i18n"
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthMethodAPIKey nilai "authMethod" di context untuk principal API key
+const AuthMethodAPIKey = "api_key"
+
+// APIKeyAuthMiddleware middleware untuk autentikasi dengan header
+// "Authorization: ApiKey <key>". Dipasang sebelum JWTAuthMiddleware; request
+// dengan skema lain diteruskan tanpa perubahan.
+type APIKeyAuthMiddleware struct {
+    apiKeyService service.APIKeyService
+}
+
+// NewAPIKeyAuthMiddleware membuat instance baru APIKeyAuthMiddleware
+func NewAPIKeyAuthMiddleware(apiKeyService service.APIKeyService) *APIKeyAuthMiddleware {
+    return &APIKeyAuthMiddleware{apiKeyService: apiKeyService}
+}
+
+// Middleware function untuk memvalidasi API key dan mengisi claims pemiliknya
+func (m *APIKeyAuthMiddleware) Middleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        // Lewati jika principal sudah diautentikasi middleware lain (misalnya mTLS)
+        if _, exists := c.Get("jwtClaims"); exists {
+            c.Next()
+            return
+        }
+
+        key, ok := APIKeyFromRequest(c)
+        if !ok {
+            c.Next()
+            return
+        }
+
+        claims, err := m.apiKeyService.Authenticate(c.Request.Context(), key)
+        if err != nil {
+            abortWithError(c, err)
+            return
+        }
+
+        c.Set("jwtClaims", claims)
+        c.Set("authMethod", AuthMethodAPIKey)
+        c.Request = c.Request.WithContext(WithPrincipalContext(c.Request.Context(), claims))
+        if claims.Locale != "" && i18n.IsSupported(claims.Locale) {
+            SetLocale(c, claims.Locale)
+        }
+        c.Next()
+    }
+}
+
+// APIKeyFromRequest mengambil key dari header "Authorization: ApiKey <key>"
+func APIKeyFromRequest(c *gin.Context) (string, bool) {
+    scheme, key, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+    if !ok || scheme != model.APIKeyScheme || key == "" {
+        return "", false
+    }
+    return key, true
+}
+
+// RequireScope middleware yang menolak principal yang tidak memiliki scope
+// (misalnya API key tanpa scope admin). Token login biasa selalu lolos.
+func RequireScope(scope string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        claims, ok := c.Get("jwtClaims")
+        jwtClaims, _ := claims.(*model.JWTClaims)
+        if !ok || jwtClaims == nil {
+            abortWithError(c, model.ErrTokenMissing)
+            return
+        }
+        if !jwtClaims.HasScope(scope) {
+            abortWithError(c, model.ErrForbidden)
+            return
+        }
+        c.Next()
+    }
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"auth-service/internal/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit membatasi ukuran body request. Request dengan Content-Length
+// melebihi batas langsung ditolak, sedangkan body tanpa Content-Length
+// dibungkus http.MaxBytesReader sehingga pembacaan gagal setelah batas terlewati.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if c.Request.ContentLength > maxBytes {
+            abortWithError(c, model.ErrRequestTooLarge)
+            return
+        }
+
+        c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+        c.Next()
+    }
+}
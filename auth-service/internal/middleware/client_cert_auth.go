@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/service"
+	"auth-service/internal/tlsauth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AuthMethodClientCert nilai "authMethod" di context untuk principal mTLS
+const AuthMethodClientCert = "mtls"
+
+// ClientCertAuthMiddleware middleware untuk autentikasi layanan internal lewat
+// sertifikat klien (mTLS). Dipasang sebelum JWTAuthMiddleware; jika sertifikat
+// cocok dengan service principal, claims diisi sehingga JWT tidak diperlukan.
+type ClientCertAuthMiddleware struct {
+    mapper *tlsauth.PrincipalMapper
+}
+
+// NewClientCertAuthMiddleware membuat instance baru ClientCertAuthMiddleware
+func NewClientCertAuthMiddleware(mapper *tlsauth.PrincipalMapper) *ClientCertAuthMiddleware {
+    return &ClientCertAuthMiddleware{mapper: mapper}
+}
+
+// Middleware function untuk memetakan sertifikat klien terverifikasi ke claims
+func (m *ClientCertAuthMiddleware) Middleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        // Hanya sertifikat yang sudah diverifikasi terhadap client CA yang dipercaya
+        state := c.Request.TLS
+        if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+            c.Next()
+            return
+        }
+
+        principal, ok := m.mapper.Map(state.VerifiedChains[0][0])
+        if !ok {
+            c.Next()
+            return
+        }
+
+        claims := &model.JWTClaims{
+            Name:          principal.Name,
+            Role:          principal.Role,
+            PrincipalType: model.PrincipalTypeService,
+            RegisteredClaims: jwt.RegisteredClaims{
+                Subject: "service:" + principal.Name,
+            },
+        }
+        c.Set("jwtClaims", claims)
+        c.Set("authMethod", AuthMethodClientCert)
+        ctx := WithPrincipalContext(c.Request.Context(), claims)
+        c.Request = c.Request.WithContext(service.WithActorSubject(ctx, state.VerifiedChains[0][0].Subject.String()))
+        c.Next()
+    }
+}
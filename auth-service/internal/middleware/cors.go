@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"auth-service/internal/config"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CORSMiddleware middleware CORS dengan policy yang dapat berbeda per route group
+type CORSMiddleware struct {
+    defaultHandler gin.HandlerFunc
+    groups         []corsGroup
+}
+
+// corsGroup policy CORS untuk satu prefix path
+type corsGroup struct {
+    prefix  string
+    handler gin.HandlerFunc
+}
+
+// NewCORSMiddleware membuat instance baru CORSMiddleware dari konfigurasi
+func NewCORSMiddleware(cfg config.CORSConfig) (*CORSMiddleware, error) {
+    defaultHandler, err := newCORSHandler(cfg.CORSPolicy)
+    if err != nil {
+        return nil, fmt.Errorf("invalid cors policy: %v", err)
+    }
+
+    m := &CORSMiddleware{defaultHandler: defaultHandler}
+    for prefix, policy := range cfg.Groups {
+        handler, err := newCORSHandler(cfg.Inherit(policy))
+        if err != nil {
+            return nil, fmt.Errorf("invalid cors policy for group %s: %v", prefix, err)
+        }
+        m.groups = append(m.groups, corsGroup{prefix: strings.TrimSuffix(prefix, "/"), handler: handler})
+    }
+
+    // Prefix terpanjang diperiksa terlebih dahulu
+    sort.Slice(m.groups, func(i, j int) bool {
+        return len(m.groups[i].prefix) > len(m.groups[j].prefix)
+    })
+
+    return m, nil
+}
+
+// Middleware function untuk menerapkan policy CORS sesuai path request.
+// Harus dipasang di level engine agar preflight OPTIONS ke route yang tidak
+// mendaftarkan method OPTIONS tetap dijawab oleh middleware ini.
+func (m *CORSMiddleware) Middleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        path := c.Request.URL.Path
+        for _, group := range m.groups {
+            if path == group.prefix || strings.HasPrefix(path, group.prefix+"/") {
+                group.handler(c)
+                return
+            }
+        }
+        m.defaultHandler(c)
+    }
+}
+
+// newCORSHandler membuat handler gin-contrib/cors dari satu policy
+func newCORSHandler(policy config.CORSPolicy) (gin.HandlerFunc, error) {
+    corsConfig := cors.Config{
+        AllowMethods:     policy.AllowMethods,
+        AllowHeaders:     policy.AllowHeaders,
+        ExposeHeaders:    policy.ExposeHeaders,
+        AllowCredentials: policy.Credentials(),
+        MaxAge:           policy.MaxAge,
+    }
+
+    for _, origin := range policy.AllowOrigins {
+        if origin == "*" {
+            corsConfig.AllowAllOrigins = true
+            continue
+        }
+        if strings.Contains(origin, "*") {
+            // Pola wildcard subdomain, misalnya https://*.example.com
+            corsConfig.AllowWildcard = true
+        }
+        corsConfig.AllowOrigins = append(corsConfig.AllowOrigins, origin)
+    }
+    if corsConfig.AllowAllOrigins {
+        corsConfig.AllowOrigins = nil
+    }
+
+    if err := corsConfig.Validate(); err != nil {
+        return nil, err
+    }
+    return cors.New(corsConfig), nil
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"auth-service/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DenyImpersonation menolak request yang diautentikasi dengan token
+// impersonation atau token delegasi. Dipasang pada aksi sensitif yang tidak
+// boleh dilakukan pihak lain atas nama pengguna: perubahan kredensial,
+// password, email atau MFA, penerbitan session baru dan endpoint admin.
+func DenyImpersonation() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        claims, _ := c.Get("jwtClaims")
+        if jwtClaims, ok := claims.(*model.JWTClaims); ok {
+            if jwtClaims.IsImpersonated() {
+                abortWithError(c, model.ErrImpersonationRestricted)
+                return
+            }
+            if jwtClaims.IsDelegated() {
+                abortWithError(c, model.ErrForbidden)
+                return
+            }
+        }
+        c.Next()
+    }
+}
+
+// DenyAudienceRestricted menolak token yang ditujukan untuk layanan lain
+// (claim aud dari token exchange). Token tersebut hanya boleh dipakai di
+// layanan tujuannya dan untuk validasi, bukan untuk endpoint auth-service.
+func DenyAudienceRestricted() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if claims, ok := c.Get("jwtClaims"); ok {
+            if jwtClaims, ok := claims.(*model.JWTClaims); ok && jwtClaims.IsAudienceRestricted() {
+                abortWithError(c, model.ErrForbidden)
+                return
+            }
+        }
+        c.Next()
+    }
+}
@@ -1,8 +1,12 @@
 package middleware
 
 import (
+    "auth-service/internal/i18n"
+    "auth-service/internal/logging"
+    "auth-service/internal/model"
     "auth-service/internal/service"
-    "net/http"
+    "context"
+    "fmt"
     "strings"
 
     "github.com/gin-gonic/gin"
@@ -11,51 +15,77 @@ import (
 // JWTAuthMiddleware middleware untuk autentikasi JWT
 type JWTAuthMiddleware struct {
     authService service.AuthService
+    cookieName  string
 }
 
 // NewJWTAuthMiddleware membuat instance baru JWTAuthMiddleware
-func NewJWTAuthMiddleware(authService service.AuthService) *JWTAuthMiddleware {
-    return &JWTAuthMiddleware{authService: authService}
+func NewJWTAuthMiddleware(authService service.AuthService, cookieName string) *JWTAuthMiddleware {
+    return &JWTAuthMiddleware{authService: authService, cookieName: cookieName}
 }
 
 // Middleware function untuk memeriksa dan memvalidasi JWT token
 func (m *JWTAuthMiddleware) Middleware() gin.HandlerFunc {
     return func(c *gin.Context) {
+        // Lewati jika principal sudah diautentikasi middleware lain (misalnya mTLS)
+        if _, exists := c.Get("jwtClaims"); exists {
+            c.Next()
+            return
+        }
+
         // Dapatkan token dari cookie atau header
-        var tokenString string
-        
-        // Coba dapatkan dari cookie terlebih dahulu
-        tokenString, err := c.Cookie("jwt")
+        tokenString, err := TokenFromRequest(c, m.cookieName)
         if err != nil {
-            // Jika tidak ada di cookie, coba dari header Authorization
-            authHeader := c.GetHeader("Authorization")
-            if authHeader == "" {
-                c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header or cookie required"})
-                c.Abort()
-                return
-            }
-            
-            // Format: Bearer <token>
-            parts := strings.Split(authHeader, " ")
-            if len(parts) != 2 || parts[0] != "Bearer" {
-                c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be Bearer {token}"})
-                c.Abort()
-                return
-            }
-            
-            tokenString = parts[1]
+            abortWithError(c, err)
+            return
         }
 
         // Validasi token
-        claims, err := m.authService.ValidateToken(tokenString)
+        claims, err := m.authService.ValidateToken(c.Request.Context(), tokenString)
         if err != nil {
-            c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-            c.Abort()
+            abortWithError(c, err)
             return
         }
 
         // Set claims ke context untuk digunakan di handler
         c.Set("jwtClaims", claims)
+        c.Request = c.Request.WithContext(WithPrincipalContext(c.Request.Context(), claims))
+        // Preferensi bahasa pengguna lebih diutamakan daripada Accept-Language
+        if claims.Locale != "" && i18n.IsSupported(claims.Locale) {
+            SetLocale(c, claims.Locale)
+        }
         c.Next()
     }
-}
\ No newline at end of file
+}
+
+// WithPrincipalContext menyimpan identitas principal claims ke context untuk
+// log (user ID atau nama service account) dan jenis actor pada event audit
+func WithPrincipalContext(ctx context.Context, claims *model.JWTClaims) context.Context {
+    if claims.IsService() {
+        ctx = logging.WithPrincipal(ctx, claims.Name)
+    } else {
+        ctx = logging.WithUserID(ctx, claims.UserID)
+    }
+    return service.WithActorType(ctx, claims.Principal().Type)
+}
+
+// TokenFromRequest mengambil token dari cookie JWT atau, jika cookie tidak
+// ada, dari header "Authorization: Bearer <token>"
+func TokenFromRequest(c *gin.Context, cookieName string) (string, error) {
+    // Coba dapatkan dari cookie terlebih dahulu
+    if tokenString, err := c.Cookie(cookieName); err == nil {
+        return tokenString, nil
+    }
+
+    // Jika tidak ada di cookie, coba dari header Authorization
+    authHeader := c.GetHeader("Authorization")
+    if authHeader == "" {
+        return "", model.ErrTokenMissing
+    }
+
+    // Format: Bearer <token>
+    parts := strings.Split(authHeader, " ")
+    if len(parts) != 2 || parts[0] != "Bearer" {
+        return "", fmt.Errorf("%w: authorization header format must be Bearer {token}", model.ErrTokenInvalid)
+    }
+    return parts[1], nil
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"auth-service/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Locale middleware yang menentukan bahasa pesan response dari header
+// Accept-Language, dengan defaultLocale sebagai fallback. Untuk request
+// terautentikasi, preferensi locale pengguna pada token menggantikan
+// pilihan ini (lihat JWTAuthMiddleware).
+func Locale(defaultLocale string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        locale := i18n.Negotiate(c.GetHeader("Accept-Language"))
+        if locale == "" {
+            locale = defaultLocale
+        }
+
+        c.Writer.Header().Add("Vary", "Accept-Language")
+        SetLocale(c, locale)
+        c.Next()
+    }
+}
+
+// SetLocale menyimpan locale request ke context dan header Content-Language
+func SetLocale(c *gin.Context, locale string) {
+    c.Header("Content-Language", locale)
+    c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"auth-service/internal/metrics"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics middleware untuk mencatat jumlah dan latensi request HTTP.
+// Label route memakai pola route Gin (misalnya /api/login) agar kardinalitas
+// tetap rendah; request yang tidak cocok dengan route manapun dicatat sebagai "unmatched"
+// dan method di luar method standar sebagai "other".
+func Metrics() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        start := time.Now()
+        c.Next()
+
+        route := c.FullPath()
+        if route == "" {
+            route = "unmatched"
+        }
+        method := metricsMethod(c.Request.Method)
+        status := strconv.Itoa(c.Writer.Status())
+
+        metrics.HTTPRequestsTotal.WithLabelValues(method, route, status).Inc()
+        metrics.HTTPRequestDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+    }
+}
+
+// metricsMethod label method untuk metrics. net/http menerima token apa pun
+// sebagai method, sehingga method non-standar digabung menjadi "other".
+func metricsMethod(method string) string {
+    switch method {
+    case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+        http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+        return method
+    }
+    return "other"
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"auth-service/internal/i18n"
+	"auth-service/internal/model"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oauthErrorCodes kode Problem yang sama dengan kode error OAuth 2.0
+// (RFC 6749 5.2, RFC 8693 2.2.2)
+var oauthErrorCodes = map[string]bool{
+    "invalid_client":         true,
+    "invalid_grant":          true,
+    "invalid_scope":          true,
+    "invalid_target":         true,
+    "unauthorized_client":    true,
+    "unsupported_grant_type": true,
+}
+
+// OAuthErrorHandler middleware untuk token endpoint OAuth 2.0. Error yang
+// dicatat handler ditulis sebagai {"error", "error_description"} (RFC 6749
+// 5.2) alih-alih application/problem+json agar dapat dibaca client OAuth
+// standar. Dipasang di dalam ErrorHandler sehingga ErrorHandler tidak
+// menulis response lagi.
+func OAuthErrorHandler() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        c.Next()
+
+        if len(c.Errors) == 0 || c.Writer.Written() {
+            return
+        }
+
+        err := c.Errors.Last().Err
+        status, oauthErr := NewOAuthError(err, i18n.FromContext(c.Request.Context()))
+        if status >= http.StatusInternalServerError {
+            slog.ErrorContext(c.Request.Context(), "Request failed", "error", err)
+        }
+        c.AbortWithStatusJSON(status, oauthErr)
+    }
+}
+
+// NewOAuthError memetakan err ke status HTTP dan error OAuth 2.0 dengan
+// deskripsi dalam locale. Error validasi menjadi invalid_request dan error
+// yang tidak dikenal menjadi server_error.
+func NewOAuthError(err error, locale string) (int, model.OAuthError) {
+    problem := NewProblem(err, locale)
+    oauthErr := model.OAuthError{Error: problem.Code, ErrorDescription: problem.Detail}
+    switch {
+    case oauthErrorCodes[problem.Code]:
+    case problem.Status >= http.StatusInternalServerError:
+        oauthErr.Error = "server_error"
+    default:
+        oauthErr.Error = "invalid_request"
+    }
+    return problem.Status, oauthErr
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"auth-service/internal/i18n"
+	"auth-service/internal/model"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ProblemContentType media type response error RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// problemTypeBase prefix URI "type" pada Problem; diikuti kode error
+const problemTypeBase = "urn:auth-service:problem:"
+
+// problemType status HTTP dan kode stabil untuk satu jenis error
+type problemType struct {
+    err    error
+    status int
+    code   string
+}
+
+// problemTypes pemetaan error domain ke response. Urutan penting karena
+// error dicocokkan dengan errors.Is dari atas ke bawah.
+var problemTypes = []problemType{
+    {model.ErrEmailTaken, http.StatusConflict, "email_taken"},
+    {model.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials"},
+    {model.ErrUserNotFound, http.StatusNotFound, "user_not_found"},
+    {model.ErrInvalidRole, http.StatusBadRequest, "invalid_role"},
+    {model.ErrTokenMissing, http.StatusUnauthorized, "authentication_required"},
+    {model.ErrTokenExpired, http.StatusUnauthorized, "token_expired"},
+    {model.ErrTokenRevoked, http.StatusUnauthorized, "token_revoked"},
+    {model.ErrTokenInvalid, http.StatusUnauthorized, "token_invalid"},
+    {model.ErrForbidden, http.StatusForbidden, "forbidden"},
+    {model.ErrAPIKeyNotFound, http.StatusNotFound, "api_key_not_found"},
+    {model.ErrInvalidScope, http.StatusBadRequest, "invalid_scope"},
+    {model.ErrAPIKeyLimitReached, http.StatusConflict, "api_key_limit_reached"},
+    {model.ErrServiceAccountNotFound, http.StatusNotFound, "service_account_not_found"},
+    {model.ErrServiceAccountNameTaken, http.StatusConflict, "service_account_name_taken"},
+    {model.ErrInvalidClient, http.StatusUnauthorized, "invalid_client"},
+    {model.ErrUnsupportedGrantType, http.StatusBadRequest, "unsupported_grant_type"},
+    {model.ErrInvalidGrant, http.StatusBadRequest, "invalid_grant"},
+    {model.ErrInvalidTarget, http.StatusBadRequest, "invalid_target"},
+    {model.ErrUnauthorizedClient, http.StatusBadRequest, "unauthorized_client"},
+    {model.ErrImpersonationNotAllowed, http.StatusForbidden, "impersonation_not_allowed"},
+    {model.ErrImpersonationRestricted, http.StatusForbidden, "impersonation_restricted"},
+    {model.ErrNotImpersonating, http.StatusBadRequest, "not_impersonating"},
+    {model.ErrRequestTooLarge, http.StatusRequestEntityTooLarge, "request_too_large"},
+    {model.ErrRouteNotFound, http.StatusNotFound, "not_found"},
+    {model.ErrMethodNotAllowed, http.StatusMethodNotAllowed, "method_not_allowed"},
+}
+
+// ErrorHandler middleware yang mengubah error yang dicatat handler lewat
+// c.Error menjadi response application/problem+json. Handler dan middleware
+// lain cukup memanggil c.Error(err) (dan c.Abort() bila perlu) tanpa menulis
+// response sendiri.
+func ErrorHandler() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        c.Next()
+
+        if len(c.Errors) == 0 || c.Writer.Written() {
+            return
+        }
+
+        err := c.Errors.Last().Err
+        if problem := writeProblem(c, err); problem.Status >= http.StatusInternalServerError {
+            slog.ErrorContext(c.Request.Context(), "Request failed", "error", err)
+        }
+    }
+}
+
+// abortWithError mencatat err untuk ErrorHandler dan menghentikan chain
+func abortWithError(c *gin.Context, err error) {
+    _ = c.Error(err)
+    c.Abort()
+}
+
+// writeProblem menulis response Problem untuk err dalam bahasa request
+func writeProblem(c *gin.Context, err error) model.Problem {
+    problem := NewProblem(err, i18n.FromContext(c.Request.Context()))
+    problem.Instance = c.Request.URL.Path
+    problem.RequestID = c.GetString("requestID")
+
+    c.Header("Content-Type", ProblemContentType)
+    c.AbortWithStatusJSON(problem.Status, problem)
+    return problem
+}
+
+// NewProblem memetakan err ke Problem dengan judul dan detail dalam locale.
+// Error yang tidak dikenal menjadi 500 tanpa detail agar pesan internal
+// (misalnya dari database) tidak bocor.
+func NewProblem(err error, locale string) model.Problem {
+    var maxBytesErr *http.MaxBytesError
+    if errors.As(err, &maxBytesErr) {
+        err = model.ErrRequestTooLarge
+    }
+
+    var validationErr *model.ValidationError
+    if errors.As(err, &validationErr) {
+        problem := newProblem(http.StatusBadRequest, "validation_failed", locale)
+        var fieldErrs validator.ValidationErrors
+        if !errors.As(validationErr.Err, &fieldErrs) {
+            // Error binding lain (misalnya JSON tidak valid) atau validasi manual handler
+            problem.Detail = i18n.T(locale, i18n.ProblemDetailKey("invalid_request"), validationErr.Error())
+            return problem
+        }
+        for _, fe := range fieldErrs {
+            problem.Errors = append(problem.Errors, model.FieldError{
+                Field:   fe.Field(),
+                Message: i18n.TranslateFieldError(locale, fe),
+            })
+        }
+        return problem
+    }
+
+    for _, pt := range problemTypes {
+        if errors.Is(err, pt.err) {
+            return newProblem(pt.status, pt.code, locale)
+        }
+    }
+    return newProblem(http.StatusInternalServerError, "internal_error", locale)
+}
+
+// newProblem membuat Problem dengan judul dan detail dari katalog pesan
+func newProblem(status int, code, locale string) model.Problem {
+    problem := model.Problem{
+        Type:   problemTypeBase + code,
+        Title:  i18n.T(locale, i18n.ProblemTitleKey(code)),
+        Status: status,
+        Code:   code,
+    }
+    if key := i18n.ProblemDetailKey(code); i18n.Has(locale, key) {
+        problem.Detail = i18n.T(locale, key)
+    }
+    return problem
+}
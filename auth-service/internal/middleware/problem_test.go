@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"auth-service/internal/i18n"
+	"auth-service/internal/model"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestNewProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail bool
+	}{
+		{"sentinel", model.ErrEmailTaken, http.StatusConflict, "email_taken", true},
+		{"wrapped sentinel", fmt.Errorf("failed to get user: %w", model.ErrUserNotFound), http.StatusNotFound, "user_not_found", true},
+		{"validation", model.NewValidationError(errors.New("invalid offset")), http.StatusBadRequest, "validation_failed", true},
+		{"max bytes", &http.MaxBytesError{Limit: 10}, http.StatusRequestEntityTooLarge, "request_too_large", true},
+		{"unknown error hides detail", errors.New("dial tcp 10.0.0.1:3306: connection refused"), http.StatusInternalServerError, "internal_error", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProblem(tt.err, i18n.English)
+			if p.Status != tt.wantStatus || p.Code != tt.wantCode {
+				t.Errorf("got %d %s, want %d %s", p.Status, p.Code, tt.wantStatus, tt.wantCode)
+			}
+			if (p.Detail != "") != tt.wantDetail {
+				t.Errorf("detail = %q, want detail present = %v", p.Detail, tt.wantDetail)
+			}
+			if p.Type != problemTypeBase+tt.wantCode {
+				t.Errorf("type = %q", p.Type)
+			}
+		})
+	}
+}
+
+func TestNewOAuthError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"oauth code", fmt.Errorf("%w: service account is disabled", model.ErrInvalidClient), http.StatusUnauthorized, "invalid_client"},
+		{"exchange code", model.ErrInvalidTarget, http.StatusBadRequest, "invalid_target"},
+		{"validation", model.NewValidationError(errors.New("grant_type is required")), http.StatusBadRequest, "invalid_request"},
+		{"unknown error", errors.New("connection refused"), http.StatusInternalServerError, "server_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, oauthErr := NewOAuthError(tt.err, i18n.English)
+			if status != tt.wantStatus || oauthErr.Error != tt.wantCode {
+				t.Errorf("got %d %s, want %d %s", status, oauthErr.Error, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"auth-service/internal/logging"
+	"auth-service/internal/service"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader header untuk propagasi request ID
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID membatasi request ID dari klien agar aman ditulis ke log
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID middleware yang meneruskan X-Request-ID dari klien (jika valid)
+// atau membuat ID baru, lalu menyimpannya ke context request dan header response
+func RequestID() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        requestID := NormalizeRequestID(c.GetHeader(RequestIDHeader))
+        c.Set("requestID", requestID)
+        c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+        c.Header(RequestIDHeader, requestID)
+        c.Next()
+    }
+}
+
+// ClientInfo middleware yang menyimpan IP dan user agent klien ke context
+// request, digunakan oleh AuditLogger
+func ClientInfo() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        info := service.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+        c.Request = c.Request.WithContext(service.WithClientInfo(c.Request.Context(), info))
+        c.Next()
+    }
+}
+
+// AccessLog middleware untuk mencatat setiap request secara terstruktur.
+// Query string, header dan body sengaja tidak dicatat karena dapat berisi token.
+func AccessLog() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        start := time.Now()
+        c.Next()
+
+        status := c.Writer.Status()
+        level := slog.LevelInfo
+        switch {
+        case status >= http.StatusInternalServerError:
+            level = slog.LevelError
+        case status >= http.StatusBadRequest:
+            level = slog.LevelWarn
+        }
+
+        attrs := []slog.Attr{
+            slog.String("method", c.Request.Method),
+            slog.String("path", c.Request.URL.Path),
+            slog.String("route", c.FullPath()),
+            slog.Int("status", status),
+            slog.Duration("latency", time.Since(start)),
+            slog.String("client_ip", c.ClientIP()),
+            slog.String("user_agent", c.Request.UserAgent()),
+        }
+        if len(c.Errors) > 0 {
+            attrs = append(attrs, slog.String("errors", c.Errors.String()))
+        }
+
+        slog.LogAttrs(c.Request.Context(), level, "HTTP request", attrs...)
+    }
+}
+
+// Recovery middleware untuk menangani panic dan mencatatnya lewat slog
+func Recovery() gin.HandlerFunc {
+    return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+        slog.ErrorContext(c.Request.Context(), "panic recovered", "panic", recovered)
+        writeProblem(c, fmt.Errorf("panic: %v", recovered))
+    })
+}
+
+// NormalizeRequestID mengembalikan requestID dari klien jika valid, atau ID
+// baru jika kosong atau tidak valid
+func NormalizeRequestID(requestID string) string {
+    if !validRequestID.MatchString(requestID) {
+        return newRequestID()
+    }
+    return requestID
+}
+
+// newRequestID membuat request ID acak 128-bit dalam bentuk hex
+func newRequestID() string {
+    b := make([]byte, 16)
+    if _, err := rand.Read(b); err != nil {
+        return time.Now().UTC().Format("20060102150405.000000000")
+    }
+    return hex.EncodeToString(b)
+}
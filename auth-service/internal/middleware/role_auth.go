@@ -2,19 +2,24 @@ package middleware
 
 import (
     "auth-service/internal/model"
-    "net/http"
+    "errors"
+    "slices"
 
     "github.com/gin-gonic/gin"
 )
 
-// RoleAuthMiddleware middleware untuk autorisasi berdasarkan role
+// RoleAuthMiddleware middleware untuk autorisasi berdasarkan role dan,
+// opsional, jenis principal (user atau service)
 type RoleAuthMiddleware struct {
-    allowedRoles []string
+    allowedRoles          []string
+    allowedPrincipalTypes []string
 }
 
-// NewRoleAuthMiddleware membuat instance baru RoleAuthMiddleware
-func NewRoleAuthMiddleware(allowedRoles []string) *RoleAuthMiddleware {
-    return &RoleAuthMiddleware{allowedRoles: allowedRoles}
+// NewRoleAuthMiddleware membuat instance baru RoleAuthMiddleware. Tanpa
+// principalTypes, semua jenis principal dengan role yang diizinkan diterima;
+// misalnya model.PrincipalTypeUser membatasi endpoint untuk manusia saja.
+func NewRoleAuthMiddleware(allowedRoles []string, principalTypes ...string) *RoleAuthMiddleware {
+    return &RoleAuthMiddleware{allowedRoles: allowedRoles, allowedPrincipalTypes: principalTypes}
 }
 
 // Middleware function untuk memeriksa role pengguna
@@ -23,15 +28,13 @@ func (m *RoleAuthMiddleware) Middleware() gin.HandlerFunc {
         // Dapatkan claims dari context
         claims, exists := c.Get("jwtClaims")
         if !exists {
-            c.JSON(http.StatusUnauthorized, gin.H{"error": "JWT claims not found"})
-            c.Abort()
+            abortWithError(c, model.ErrTokenMissing)
             return
         }
 
         jwtClaims, ok := claims.(*model.JWTClaims)
         if !ok {
-            c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid JWT claims format"})
-            c.Abort()
+            abortWithError(c, errors.New("invalid JWT claims format"))
             return
         }
 
@@ -45,8 +48,13 @@ func (m *RoleAuthMiddleware) Middleware() gin.HandlerFunc {
         }
 
         if !hasAccess {
-            c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
-            c.Abort()
+            abortWithError(c, model.ErrForbidden)
+            return
+        }
+
+        // Periksa apakah jenis principal (manusia atau mesin) diizinkan
+        if len(m.allowedPrincipalTypes) > 0 && !slices.Contains(m.allowedPrincipalTypes, jwtClaims.Principal().Type) {
+            abortWithError(c, model.ErrForbidden)
             return
         }
 
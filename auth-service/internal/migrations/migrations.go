@@ -0,0 +1,360 @@
+package migrations
+
+import (
+	"auth-service/internal/database"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+const (
+    // lockName nama advisory lock MySQL yang mencegah beberapa replika
+    // menjalankan migration bersamaan
+    lockName = "auth_service_schema_migrations"
+    // lockKey kunci advisory lock PostgreSQL (bigint) dengan tujuan yang sama
+    lockKey = 7417307346521000001
+    // lockTimeoutSeconds batas waktu menunggu lock dipegang replika lain
+    lockTimeoutSeconds = 60
+)
+
+// fileNamePattern format nama file migration: 0001_nama.up.sql / 0001_nama.down.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration satu langkah perubahan schema
+type Migration struct {
+    Version int64
+    Name    string
+    Up      string
+    Down    string
+}
+
+// Status status satu migration di database
+type Status struct {
+    Version   int64
+    Name      string
+    Applied   bool
+    AppliedAt *time.Time
+}
+
+// Migrator menjalankan migration SQL yang di-embed ke binary dan mencatat
+// versi yang sudah diterapkan di tabel schema_migrations
+type Migrator struct {
+    db         *sql.DB
+    dialect    database.Dialect
+    migrations []Migration
+}
+
+// New membuat instance baru Migrator dengan migration yang di-embed untuk dialect
+func New(db *sql.DB, dialect database.Dialect) (*Migrator, error) {
+    migrations, err := load(files, dialect.Name)
+    if err != nil {
+        return nil, err
+    }
+    return &Migrator{db: db, dialect: dialect, migrations: migrations}, nil
+}
+
+// load membaca dan mengurutkan file migration dari dir
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+    entries, err := fs.ReadDir(fsys, dir)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read migrations: %v", err)
+    }
+
+    byVersion := make(map[int64]*Migration)
+    for _, entry := range entries {
+        match := fileNamePattern.FindStringSubmatch(entry.Name())
+        if match == nil {
+            return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+        }
+        version, err := strconv.ParseInt(match[1], 10, 64)
+        if err != nil {
+            return nil, fmt.Errorf("invalid migration version in %q: %v", entry.Name(), err)
+        }
+
+        content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+        if err != nil {
+            return nil, fmt.Errorf("failed to read migration %q: %v", entry.Name(), err)
+        }
+
+        m, ok := byVersion[version]
+        if !ok {
+            m = &Migration{Version: version, Name: match[2]}
+            byVersion[version] = m
+        } else if m.Name != match[2] {
+            return nil, fmt.Errorf("migration version %d has conflicting names %q and %q", version, m.Name, match[2])
+        }
+        if match[3] == "up" {
+            m.Up = string(content)
+        } else {
+            m.Down = string(content)
+        }
+    }
+
+    migrations := make([]Migration, 0, len(byVersion))
+    for _, m := range byVersion {
+        if strings.TrimSpace(m.Up) == "" {
+            return nil, fmt.Errorf("migration %04d_%s has no up script", m.Version, m.Name)
+        }
+        migrations = append(migrations, *m)
+    }
+    sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+    return migrations, nil
+}
+
+// Up menerapkan semua migration yang belum diterapkan dan mengembalikan jumlahnya
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+    count := 0
+    err := m.withLock(ctx, func(conn *sql.Conn) error {
+        applied, err := appliedVersions(ctx, conn)
+        if err != nil {
+            return err
+        }
+
+        for _, migration := range m.migrations {
+            if _, ok := applied[migration.Version]; ok {
+                continue
+            }
+            if err := execScript(ctx, conn, migration.Up); err != nil {
+                return fmt.Errorf("migration %04d_%s failed: %v", migration.Version, migration.Name, err)
+            }
+            _, err := conn.ExecContext(ctx, m.dialect.Rebind(`INSERT INTO schema_migrations (version, name) VALUES (?, ?)`),
+                migration.Version, migration.Name)
+            if err != nil {
+                return fmt.Errorf("failed to record migration %04d_%s: %v", migration.Version, migration.Name, err)
+            }
+
+            slog.InfoContext(ctx, "Migration applied", "version", migration.Version, "name", migration.Name)
+            count++
+        }
+        return nil
+    })
+    return count, err
+}
+
+// Down membatalkan steps migration terakhir yang sudah diterapkan
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+    count := 0
+    err := m.withLock(ctx, func(conn *sql.Conn) error {
+        applied, err := appliedVersions(ctx, conn)
+        if err != nil {
+            return err
+        }
+
+        for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+            migration := m.migrations[i]
+            if _, ok := applied[migration.Version]; !ok {
+                continue
+            }
+            if strings.TrimSpace(migration.Down) == "" {
+                return fmt.Errorf("migration %04d_%s has no down script", migration.Version, migration.Name)
+            }
+            if err := execScript(ctx, conn, migration.Down); err != nil {
+                return fmt.Errorf("rollback of migration %04d_%s failed: %v", migration.Version, migration.Name, err)
+            }
+            _, err := conn.ExecContext(ctx, m.dialect.Rebind(`DELETE FROM schema_migrations WHERE version = ?`), migration.Version)
+            if err != nil {
+                return fmt.Errorf("failed to remove migration record %04d_%s: %v", migration.Version, migration.Name, err)
+            }
+
+            slog.InfoContext(ctx, "Migration rolled back", "version", migration.Version, "name", migration.Name)
+            count++
+        }
+        return nil
+    })
+    return count, err
+}
+
+// Status mengembalikan status semua migration yang dikenal binary ini
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+    conn, err := m.db.Conn(ctx)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get database connection: %v", err)
+    }
+    defer conn.Close()
+
+    if err := ensureTable(ctx, conn); err != nil {
+        return nil, err
+    }
+    applied, err := appliedVersions(ctx, conn)
+    if err != nil {
+        return nil, err
+    }
+
+    statuses := make([]Status, 0, len(m.migrations))
+    for _, migration := range m.migrations {
+        status := Status{Version: migration.Version, Name: migration.Name}
+        if appliedAt, ok := applied[migration.Version]; ok {
+            status.Applied = true
+            status.AppliedAt = &appliedAt
+        }
+        statuses = append(statuses, status)
+    }
+    return statuses, nil
+}
+
+// CheckCurrent mengembalikan error jika masih ada migration yang belum
+// diterapkan; digunakan oleh pemeriksaan readiness
+func (m *Migrator) CheckCurrent(ctx context.Context) error {
+    conn, err := m.db.Conn(ctx)
+    if err != nil {
+        return fmt.Errorf("failed to get database connection: %v", err)
+    }
+    defer conn.Close()
+
+    applied, err := appliedVersions(ctx, conn)
+    if err != nil {
+        return err
+    }
+
+    var pending []string
+    for _, migration := range m.migrations {
+        if _, ok := applied[migration.Version]; !ok {
+            pending = append(pending, fmt.Sprintf("%04d_%s", migration.Version, migration.Name))
+        }
+    }
+    if len(pending) > 0 {
+        return fmt.Errorf("pending migrations: %s", strings.Join(pending, ", "))
+    }
+    return nil
+}
+
+// withLock menjalankan fn dengan advisory lock yang dipegang oleh satu koneksi
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+    conn, err := m.db.Conn(ctx)
+    if err != nil {
+        return fmt.Errorf("failed to get database connection: %v", err)
+    }
+    defer conn.Close()
+
+    release, err := m.lock(ctx, conn)
+    if err != nil {
+        return err
+    }
+    defer release()
+
+    if err := ensureTable(ctx, conn); err != nil {
+        return err
+    }
+    return fn(conn)
+}
+
+// lock mengambil advisory lock sesuai dialect dan mengembalikan fungsi untuk melepasnya.
+// SQLite tidak memerlukan lock karena penulisan ke file database sudah diserialisasi.
+func (m *Migrator) lock(ctx context.Context, conn *sql.Conn) (func(), error) {
+    var releaseQuery string
+    var key interface{}
+    switch m.dialect.Name {
+    case database.MySQL:
+        var acquired sql.NullInt64
+        if err := conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, ?)`, lockName, lockTimeoutSeconds).Scan(&acquired); err != nil {
+            return nil, fmt.Errorf("failed to acquire migration lock: %v", err)
+        }
+        if !acquired.Valid || acquired.Int64 != 1 {
+            return nil, errors.New("failed to acquire migration lock: timed out waiting for another migration to finish")
+        }
+        releaseQuery, key = `SELECT RELEASE_LOCK(?)`, lockName
+    case database.Postgres:
+        releaseQuery, key = `SELECT pg_advisory_unlock($1)`, int64(lockKey)
+        lockCtx, cancel := context.WithTimeout(ctx, lockTimeoutSeconds*time.Second)
+        defer cancel()
+        if _, err := conn.ExecContext(lockCtx, `SELECT pg_advisory_lock($1)`, key); err != nil {
+            return nil, fmt.Errorf("failed to acquire migration lock: %v", err)
+        }
+    default:
+        return func() {}, nil
+    }
+
+    return func() {
+        // Context request bisa saja sudah dibatalkan; lock tetap harus dilepas
+        if _, err := conn.ExecContext(context.Background(), releaseQuery, key); err != nil {
+            slog.Error("Failed to release migration lock", "error", err)
+        }
+    }, nil
+}
+
+// ensureTable membuat tabel schema_migrations jika belum ada
+func ensureTable(ctx context.Context, conn *sql.Conn) error {
+    query := `
+    CREATE TABLE IF NOT EXISTS schema_migrations (
+        version BIGINT PRIMARY KEY,
+        name VARCHAR(255) NOT NULL,
+        applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+    )
+    `
+    if _, err := conn.ExecContext(ctx, query); err != nil {
+        return fmt.Errorf("failed to create schema_migrations table: %v", err)
+    }
+    return nil
+}
+
+// appliedVersions mengambil versi migration yang sudah diterapkan beserta waktunya
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+    rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+    if err != nil {
+        return nil, fmt.Errorf("failed to query schema_migrations: %v", err)
+    }
+    defer rows.Close()
+
+    applied := make(map[int64]time.Time)
+    for rows.Next() {
+        var version int64
+        var appliedAt time.Time
+        if err := rows.Scan(&version, &appliedAt); err != nil {
+            return nil, fmt.Errorf("failed to scan schema_migrations: %v", err)
+        }
+        applied[version] = appliedAt
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to query schema_migrations: %v", err)
+    }
+    return applied, nil
+}
+
+// execScript menjalankan script migration statement demi statement karena
+// tidak semua driver mengizinkan multi statement dalam satu Exec.
+// Statement dipisahkan oleh ";" di akhir baris; baris komentar "--" diabaikan.
+func execScript(ctx context.Context, conn *sql.Conn, script string) error {
+    for _, statement := range splitStatements(script) {
+        if _, err := conn.ExecContext(ctx, statement); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// splitStatements memecah script SQL menjadi statement terpisah
+func splitStatements(script string) []string {
+    var statements []string
+    var current strings.Builder
+
+    for _, line := range strings.Split(script, "\n") {
+        trimmed := strings.TrimSpace(line)
+        if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+            continue
+        }
+        current.WriteString(line)
+        current.WriteString("\n")
+
+        if strings.HasSuffix(trimmed, ";") {
+            statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+            current.Reset()
+        }
+    }
+    if rest := strings.TrimSpace(current.String()); rest != "" {
+        statements = append(statements, rest)
+    }
+    return statements
+}
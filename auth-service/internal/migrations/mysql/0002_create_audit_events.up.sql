@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    event_type VARCHAR(64) NOT NULL,
+    actor_id BIGINT NULL,
+    subject_id BIGINT NULL,
+    subject_email VARCHAR(255) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    outcome VARCHAR(16) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_audit_events_created_at (created_at),
+    INDEX idx_audit_events_actor (actor_id, created_at),
+    INDEX idx_audit_events_subject (subject_id, created_at),
+    INDEX idx_audit_events_type (event_type, created_at)
+);
@@ -0,0 +1,15 @@
+-- API key milik pengguna. Secret hanya disimpan sebagai hash SHA-256;
+-- key_id adalah bagian publik key yang dipakai untuk lookup.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    key_id VARCHAR(32) NOT NULL UNIQUE,
+    key_hash CHAR(64) NOT NULL,
+    scopes VARCHAR(1024) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP(6) NULL,
+    last_used_at TIMESTAMP(6) NULL,
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    INDEX idx_api_keys_user (user_id),
+    CONSTRAINT fk_api_keys_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
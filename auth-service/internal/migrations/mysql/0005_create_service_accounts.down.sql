@@ -0,0 +1,6 @@
+DELETE FROM api_keys WHERE service_account_id IS NOT NULL;
+ALTER TABLE api_keys DROP FOREIGN KEY fk_api_keys_service_account;
+ALTER TABLE api_keys DROP INDEX idx_api_keys_service_account;
+ALTER TABLE api_keys DROP COLUMN service_account_id;
+ALTER TABLE api_keys MODIFY user_id INT NOT NULL;
+DROP TABLE IF EXISTS service_accounts;
@@ -0,0 +1,17 @@
+-- Service account: principal non-manusia tanpa password maupun email.
+-- Client secret (client credentials) hanya disimpan sebagai hash SHA-256.
+CREATE TABLE IF NOT EXISTS service_accounts (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    role VARCHAR(50) NOT NULL,
+    client_id VARCHAR(64) NOT NULL UNIQUE,
+    client_secret_hash CHAR(64) NOT NULL,
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+);
+-- API key dimiliki tepat satu dari pengguna atau service account
+ALTER TABLE api_keys MODIFY user_id INT NULL;
+ALTER TABLE api_keys ADD COLUMN service_account_id BIGINT NULL;
+ALTER TABLE api_keys ADD INDEX idx_api_keys_service_account (service_account_id);
+ALTER TABLE api_keys ADD CONSTRAINT fk_api_keys_service_account FOREIGN KEY (service_account_id) REFERENCES service_accounts (id) ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+-- Preferensi bahasa pesan API; string kosong berarti mengikuti Accept-Language
+ALTER TABLE users ADD COLUMN locale VARCHAR(10) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+DELETE FROM api_keys WHERE service_account_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_api_keys_service_account;
+ALTER TABLE api_keys DROP COLUMN service_account_id;
+ALTER TABLE api_keys ALTER COLUMN user_id SET NOT NULL;
+DROP TABLE IF EXISTS service_accounts;
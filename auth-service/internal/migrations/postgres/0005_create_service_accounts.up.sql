@@ -0,0 +1,16 @@
+-- Service account: principal non-manusia tanpa password maupun email.
+-- Client secret (client credentials) hanya disimpan sebagai hash SHA-256.
+CREATE TABLE IF NOT EXISTS service_accounts (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    role VARCHAR(50) NOT NULL,
+    client_id VARCHAR(64) NOT NULL UNIQUE,
+    client_secret_hash CHAR(64) NOT NULL,
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- API key dimiliki tepat satu dari pengguna atau service account
+ALTER TABLE api_keys ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE api_keys ADD COLUMN service_account_id BIGINT NULL REFERENCES service_accounts (id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_api_keys_service_account ON api_keys (service_account_id);
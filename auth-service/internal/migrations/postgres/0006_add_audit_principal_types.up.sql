@@ -0,0 +1,6 @@
+-- Jenis principal actor dan subject (user atau service) agar event dari
+-- service account dapat dibedakan dari pengguna dengan ID yang sama
+ALTER TABLE audit_events ADD COLUMN actor_type VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE audit_events ADD COLUMN subject_type VARCHAR(16) NOT NULL DEFAULT '';
+UPDATE audit_events SET actor_type = 'user' WHERE actor_id IS NOT NULL;
+UPDATE audit_events SET subject_type = 'user' WHERE subject_id IS NOT NULL;
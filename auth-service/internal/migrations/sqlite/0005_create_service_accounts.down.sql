@@ -0,0 +1,17 @@
+CREATE TABLE api_keys_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    key_id VARCHAR(32) NOT NULL UNIQUE,
+    key_hash CHAR(64) NOT NULL,
+    scopes VARCHAR(1024) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NULL,
+    last_used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO api_keys_old (id, user_id, name, key_id, key_hash, scopes, expires_at, last_used_at, created_at)
+    SELECT id, user_id, name, key_id, key_hash, scopes, expires_at, last_used_at, created_at FROM api_keys WHERE user_id IS NOT NULL;
+DROP TABLE api_keys;
+ALTER TABLE api_keys_old RENAME TO api_keys;
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys (user_id);
+DROP TABLE IF EXISTS service_accounts;
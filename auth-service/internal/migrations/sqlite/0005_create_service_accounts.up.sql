@@ -0,0 +1,32 @@
+-- Service account: principal non-manusia tanpa password maupun email.
+-- Client secret (client credentials) hanya disimpan sebagai hash SHA-256.
+CREATE TABLE IF NOT EXISTS service_accounts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    role VARCHAR(50) NOT NULL,
+    client_id VARCHAR(64) NOT NULL UNIQUE,
+    client_secret_hash CHAR(64) NOT NULL,
+    disabled BOOLEAN NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- API key dimiliki tepat satu dari pengguna atau service account. SQLite
+-- tidak dapat mengubah NOT NULL sehingga tabel dibuat ulang.
+CREATE TABLE api_keys_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id BIGINT NULL REFERENCES users (id) ON DELETE CASCADE,
+    service_account_id BIGINT NULL REFERENCES service_accounts (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    key_id VARCHAR(32) NOT NULL UNIQUE,
+    key_hash CHAR(64) NOT NULL,
+    scopes VARCHAR(1024) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NULL,
+    last_used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO api_keys_new (id, user_id, name, key_id, key_hash, scopes, expires_at, last_used_at, created_at)
+    SELECT id, user_id, name, key_id, key_hash, scopes, expires_at, last_used_at, created_at FROM api_keys;
+DROP TABLE api_keys;
+ALTER TABLE api_keys_new RENAME TO api_keys;
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys (user_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_service_account ON api_keys (service_account_id);
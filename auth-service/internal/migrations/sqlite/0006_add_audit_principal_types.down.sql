@@ -0,0 +1,2 @@
+ALTER TABLE audit_events DROP COLUMN subject_type;
+ALTER TABLE audit_events DROP COLUMN actor_type;
@@ -0,0 +1,2 @@
+-- Subject sertifikat klien untuk actor layanan mTLS, yang tidak memiliki ID
+ALTER TABLE audit_events ADD COLUMN actor_subject VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// APIKeyScheme skema header "Authorization: ApiKey <key>"
+const APIKeyScheme = "ApiKey"
+
+// ScopeAdmin scope API key untuk endpoint /api/admin. Selain scope ini,
+// nama permission dari security.permissions juga dapat dipakai sebagai scope.
+const ScopeAdmin = "admin"
+
+// APIKey merepresentasikan API key milik pengguna atau service account.
+// Secret tidak pernah disimpan; hanya hash SHA-256 dari key lengkap.
+type APIKey struct {
+    ID         int64
+    Owner      Principal
+    Name       string
+    // KeyID bagian publik key untuk lookup, juga ditampilkan sebagai prefix
+    KeyID      string
+    KeyHash    string
+    Scopes     []string
+    ExpiresAt  *time.Time
+    LastUsedAt *time.Time
+    CreatedAt  time.Time
+}
+
+// CreateAPIKeyRequest struct untuk request pembuatan API key
+type CreateAPIKeyRequest struct {
+    Name      string     `json:"name" binding:"required,max=100"`
+    Scopes    []string   `json:"scopes,omitempty" binding:"omitempty,max=20,dive,required"`
+    ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKeyResponse struct untuk response API key (tanpa secret)
+type APIKeyResponse struct {
+    ID         int64      `json:"id"`
+    Name       string     `json:"name"`
+    Prefix     string     `json:"prefix"`
+    Scopes     []string   `json:"scopes"`
+    ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+    LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+    CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse response pembuatan API key; Key hanya dikembalikan
+// sekali dan tidak dapat diambil kembali
+type CreatedAPIKeyResponse struct {
+    APIKeyResponse
+    Key string `json:"key"`
+}
@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// Jenis event audit
+const (
+    AuditEventRegister     = "register"
+    AuditEventLogin        = "login"
+    AuditEventLogout       = "logout"
+    AuditEventRoleChange   = "role_change"
+    AuditEventExport       = "audit_export"
+    AuditEventAPIKeyCreate = "api_key_create"
+    AuditEventAPIKeyDelete = "api_key_delete"
+
+    AuditEventServiceAccountCreate = "service_account_create"
+    AuditEventServiceAccountUpdate = "service_account_update"
+    AuditEventServiceAccountDelete = "service_account_delete"
+    AuditEventServiceAccountSecret = "service_account_secret_rotate"
+    AuditEventServiceToken         = "service_token"
+
+    AuditEventImpersonationStart = "impersonation_start"
+    AuditEventImpersonationStop  = "impersonation_stop"
+    AuditEventTokenExchange      = "token_exchange"
+)
+
+// Hasil event audit
+const (
+    AuditOutcomeSuccess = "success"
+    AuditOutcomeFailure = "failure"
+)
+
+// AuditEvent merepresentasikan satu catatan audit autentikasi (append-only).
+// ActorType dan SubjectType membedakan pengguna dari service account.
+// Layanan mTLS tidak memiliki ID sehingga dicatat lewat ActorSubject.
+type AuditEvent struct {
+    ID           int64     `json:"id"`
+    EventType    string    `json:"event_type"`
+    ActorID      *int64    `json:"actor_id,omitempty"`
+    ActorType    string    `json:"actor_type,omitempty"`
+    ActorSubject string    `json:"actor_subject,omitempty"`
+    SubjectID    *int64    `json:"subject_id,omitempty"`
+    SubjectType  string    `json:"subject_type,omitempty"`
+    SubjectEmail string    `json:"subject_email,omitempty"`
+    IP           string    `json:"ip,omitempty"`
+    UserAgent    string    `json:"user_agent,omitempty"`
+    Outcome      string    `json:"outcome"`
+    Reason       string    `json:"reason,omitempty"`
+    CreatedAt    time.Time `json:"created_at"`
+}
+
+// AuditEventFilter filter untuk query event audit. UserID cocok dengan
+// pengguna (bukan service account) sebagai actor maupun subject event;
+// ActorType membatasi jenis principal actor.
+type AuditEventFilter struct {
+    UserID    *int64
+    ActorType string
+    EventType string
+    From      *time.Time
+    To        *time.Time
+    Limit     int
+    Offset    int
+}
@@ -0,0 +1,59 @@
+package model
+
+import "errors"
+
+// Error domain yang dapat dibandingkan dengan errors.Is di semua layer.
+// Error dari layer bawah dibungkus dengan %w sehingga sentinel tetap dapat dikenali.
+var (
+    // ErrEmailTaken dikembalikan saat email sudah terdaftar, apa pun backend database-nya
+    ErrEmailTaken         = errors.New("email already registered")
+    ErrInvalidCredentials = errors.New("invalid email or password")
+    ErrUserNotFound       = errors.New("user not found")
+    ErrInvalidRole        = errors.New("invalid role")
+
+    ErrTokenMissing = errors.New("authorization header or cookie required")
+    ErrTokenInvalid = errors.New("invalid token")
+    ErrTokenExpired = errors.New("token has expired")
+    ErrTokenRevoked = errors.New("token has been revoked")
+    ErrForbidden    = errors.New("insufficient permissions")
+
+    ErrAPIKeyNotFound     = errors.New("api key not found")
+    ErrInvalidScope       = errors.New("invalid scope")
+    ErrAPIKeyLimitReached = errors.New("api key limit reached")
+
+    ErrServiceAccountNotFound  = errors.New("service account not found")
+    ErrServiceAccountNameTaken = errors.New("service account name already taken")
+    ErrInvalidClient           = errors.New("invalid client credentials")
+    ErrUnsupportedGrantType    = errors.New("unsupported grant type")
+
+    ErrImpersonationNotAllowed = errors.New("user cannot be impersonated")
+    ErrImpersonationRestricted = errors.New("action not allowed while impersonating")
+    ErrNotImpersonating        = errors.New("token is not an impersonation token")
+
+    ErrInvalidGrant       = errors.New("invalid subject or actor token")
+    ErrInvalidTarget      = errors.New("audience not allowed")
+    ErrUnauthorizedClient = errors.New("client is not allowed to use this grant")
+
+    ErrRequestTooLarge  = errors.New("request body too large")
+    ErrRouteNotFound    = errors.New("route not found")
+    ErrMethodNotAllowed = errors.New("method not allowed")
+)
+
+// ValidationError membungkus kesalahan validasi input request (body, query
+// atau path parameter)
+type ValidationError struct {
+    Err error
+}
+
+// NewValidationError membuat ValidationError dari err
+func NewValidationError(err error) *ValidationError {
+    return &ValidationError{Err: err}
+}
+
+func (e *ValidationError) Error() string {
+    return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+    return e.Err
+}
@@ -0,0 +1,20 @@
+package model
+
+// Problem format response error RFC 7807 (application/problem+json).
+// Code adalah kode error yang stabil untuk dipakai klien.
+type Problem struct {
+    Type      string       `json:"type"`
+    Title     string       `json:"title"`
+    Status    int          `json:"status"`
+    Detail    string       `json:"detail,omitempty"`
+    Instance  string       `json:"instance,omitempty"`
+    Code      string       `json:"code"`
+    RequestID string       `json:"request_id,omitempty"`
+    Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError kesalahan validasi pada satu field request
+type FieldError struct {
+    Field   string `json:"field"`
+    Message string `json:"message"`
+}
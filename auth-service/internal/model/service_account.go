@@ -0,0 +1,97 @@
+package model
+
+import "time"
+
+// Jenis principal yang dibawa claim principal_type. Token lama tanpa claim
+// tersebut diperlakukan sebagai pengguna.
+const (
+    PrincipalTypeUser    = "user"
+    PrincipalTypeService = "service"
+)
+
+// Principal identitas pemilik kredensial: pengguna atau service account
+type Principal struct {
+    Type string
+    ID   int64
+}
+
+// UserPrincipal membuat Principal untuk pengguna
+func UserPrincipal(id int64) Principal {
+    return Principal{Type: PrincipalTypeUser, ID: id}
+}
+
+// ServicePrincipal membuat Principal untuk service account
+func ServicePrincipal(id int64) Principal {
+    return Principal{Type: PrincipalTypeService, ID: id}
+}
+
+// IsService memeriksa apakah principal adalah service account
+func (p Principal) IsService() bool {
+    return p.Type == PrincipalTypeService
+}
+
+// GrantTypeClientCredentials grant OAuth 2.0 untuk service account (RFC 6749 4.4)
+const GrantTypeClientCredentials = "client_credentials"
+
+// ServiceAccount merepresentasikan principal non-manusia yang dikelola admin.
+// Tidak memiliki password maupun email; kredensialnya berupa API key atau
+// client credentials. Client secret hanya disimpan sebagai hash SHA-256.
+type ServiceAccount struct {
+    ID               int64
+    Name             string
+    Description      string
+    Role             string
+    ClientID         string
+    ClientSecretHash string
+    Disabled         bool
+    CreatedAt        time.Time
+}
+
+// CreateServiceAccountRequest struct untuk request pembuatan service account
+type CreateServiceAccountRequest struct {
+    Name        string `json:"name" binding:"required,max=100"`
+    Description string `json:"description,omitempty" binding:"max=255"`
+    Role        string `json:"role" binding:"required"`
+}
+
+// UpdateServiceAccountRequest struct untuk request perubahan service account;
+// field kosong tidak diubah
+type UpdateServiceAccountRequest struct {
+    Description *string `json:"description,omitempty" binding:"omitempty,max=255"`
+    Role        *string `json:"role,omitempty" binding:"omitempty,min=1"`
+    Disabled    *bool   `json:"disabled,omitempty"`
+}
+
+// ServiceAccountResponse struct untuk response service account (tanpa secret)
+type ServiceAccountResponse struct {
+    ID          int64     `json:"id"`
+    Name        string    `json:"name"`
+    Description string    `json:"description,omitempty"`
+    Role        string    `json:"role"`
+    ClientID    string    `json:"client_id"`
+    Disabled    bool      `json:"disabled"`
+    CreatedAt   time.Time `json:"created_at"`
+}
+
+// ServiceAccountCredentialsResponse response pembuatan service account atau
+// rotasi secret; ClientSecret hanya dikembalikan sekali
+type ServiceAccountCredentialsResponse struct {
+    ServiceAccountResponse
+    ClientSecret string `json:"client_secret"`
+}
+
+// TokenResponse response token endpoint OAuth 2.0 (RFC 6749 5.1)
+type TokenResponse struct {
+    AccessToken string `json:"access_token"`
+    // IssuedTokenType hanya diisi untuk token exchange (RFC 8693 2.2.1)
+    IssuedTokenType string `json:"issued_token_type,omitempty"`
+    TokenType       string `json:"token_type"`
+    ExpiresIn       int64  `json:"expires_in"`
+    Scope           string `json:"scope,omitempty"`
+}
+
+// OAuthError response error token endpoint OAuth 2.0 (RFC 6749 5.2)
+type OAuthError struct {
+    Error            string `json:"error"`
+    ErrorDescription string `json:"error_description,omitempty"`
+}
@@ -0,0 +1,25 @@
+package model
+
+// GrantTypeTokenExchange grant OAuth 2.0 token exchange (RFC 8693)
+const GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// Jenis token yang diterima dan diterbitkan token exchange (RFC 8693 3).
+// Token auth-service adalah JWT sekaligus access token.
+const (
+    TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+    TokenTypeJWT         = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// TokenExchangeRequest parameter grant token exchange. SubjectToken adalah
+// token pengguna yang ditukar; ActorToken (opsional) adalah token pihak yang
+// akan bertindak atas nama pengguna dan dicatat di claim "act".
+type TokenExchangeRequest struct {
+    SubjectToken     string
+    SubjectTokenType string
+    ActorToken       string
+    ActorTokenType   string
+    // Audience layanan tujuan token; wajib diisi dan dibatasi policy client
+    Audience []string
+    // Scope dipisahkan spasi; harus bagian dari scope subject token
+    Scope string
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -13,7 +14,9 @@ type User struct {
     Email        string    `json:"email"`
     PasswordHash string    `json:"-"`
     Role         string    `json:"role"`
-    CreatedAt    time.Time `json:"created_at"`
+    // Locale preferensi bahasa pesan API; kosong berarti mengikuti Accept-Language
+    Locale    string    `json:"locale,omitempty"`
+    CreatedAt time.Time `json:"created_at"`
 }
 
 // UserRegisterRequest struct untuk request registrasi
@@ -22,6 +25,7 @@ type UserRegisterRequest struct {
     Email    string `json:"email" binding:"required,email"`
     Password string `json:"password" binding:"required,min=6"`
     Role     string `json:"role,omitempty"`
+    Locale   string `json:"locale,omitempty" binding:"omitempty,oneof=en id"`
 }
 
 // UserLoginRequest struct untuk request login
@@ -30,12 +34,23 @@ type UserLoginRequest struct {
     Password string `json:"password" binding:"required"`
 }
 
+// UpdateRoleRequest struct untuk request perubahan role pengguna
+type UpdateRoleRequest struct {
+    Role string `json:"role" binding:"required"`
+}
+
+// UpdateLocaleRequest struct untuk request perubahan preferensi bahasa
+type UpdateLocaleRequest struct {
+    Locale string `json:"locale" binding:"required,oneof=en id"`
+}
+
 // UserResponse struct untuk response pengguna
 type UserResponse struct {
     ID        int64     `json:"id"`
     Name      string    `json:"name"`
     Email     string    `json:"email"`
     Role      string    `json:"role"`
+    Locale    string    `json:"locale,omitempty"`
     CreatedAt time.Time `json:"created_at"`
 }
 
@@ -45,5 +60,115 @@ type JWTClaims struct {
     Email  string `json:"email"`
     Name   string `json:"name"`
     Role   string `json:"role"`
+    Locale string `json:"locale,omitempty"`
+    // Scopes membatasi akses principal (lihat HasScope)
+    Scopes []string `json:"scopes,omitempty"`
+    // PrincipalType jenis principal (user atau service); kosong berarti user.
+    // Untuk service account, UserID berisi ID service account.
+    PrincipalType string `json:"principal_type,omitempty"`
+    // Act identitas pihak yang bertindak atas nama pengguna (claim "act",
+    // RFC 8693 4.1): admin pada token impersonation, atau actor token pada
+    // token hasil token exchange
+    Act *ActorClaim `json:"act,omitempty"`
+    // Impersonation menandai token impersonation admin; admin tersebut
+    // adalah actor terdalam pada rantai Act. Dipertahankan token exchange.
+    Impersonation bool `json:"impersonation,omitempty"`
+    // APIKeyID ID API key jika principal diautentikasi dengan API key;
+    // tidak pernah ditulis ke token
+    APIKeyID int64 `json:"-"`
     jwt.RegisteredClaims
-}
\ No newline at end of file
+}
+
+// ActorClaim isi claim "act": principal yang sebenarnya memakai token. Act
+// bersarang berisi actor sebelumnya dalam rantai delegasi.
+type ActorClaim struct {
+    UserID        int64       `json:"sub"`
+    Email         string      `json:"email,omitempty"`
+    PrincipalType string      `json:"principal_type,omitempty"`
+    Act           *ActorClaim `json:"act,omitempty"`
+}
+
+// IsImpersonated memeriksa apakah token berasal dari impersonation admin,
+// termasuk token hasil token exchange dari token impersonation
+func (c *JWTClaims) IsImpersonated() bool {
+    return c.Impersonation && c.Act != nil
+}
+
+// Impersonator mengembalikan admin yang melakukan impersonation, atau nil
+// jika token bukan token impersonation
+func (c *JWTClaims) Impersonator() *ActorClaim {
+    if !c.IsImpersonated() {
+        return nil
+    }
+    act := c.Act
+    for act.Act != nil {
+        act = act.Act
+    }
+    return act
+}
+
+// IsDelegated memeriksa apakah token dipakai actor lain lewat token exchange
+// dengan actor token (delegasi), selain admin pada impersonation
+func (c *JWTClaims) IsDelegated() bool {
+    if c.IsImpersonated() {
+        return c.Act.Act != nil
+    }
+    return c.Act != nil
+}
+
+// Principal mengembalikan jenis dan ID principal pemilik claims
+func (c *JWTClaims) Principal() Principal {
+    if c.PrincipalType == "" {
+        return UserPrincipal(c.UserID)
+    }
+    return Principal{Type: c.PrincipalType, ID: c.UserID}
+}
+
+// IsService memeriksa apakah claims milik principal non-manusia (service
+// account atau layanan mTLS)
+func (c *JWTClaims) IsService() bool {
+    return c.PrincipalType == PrincipalTypeService
+}
+
+// IsAudienceRestricted memeriksa apakah token ditujukan untuk layanan lain
+// (claim aud, diisi token exchange)
+func (c *JWTClaims) IsAudienceRestricted() bool {
+    return len(c.Audience) > 0
+}
+
+// ValidForAudience memeriksa apakah token boleh diterima oleh layanan dengan
+// audience expected. Token tanpa aud berlaku di semua layanan; token ber-aud
+// hanya berlaku untuk audience-nya dan selalu ditolak jika expected kosong.
+func (c *JWTClaims) ValidForAudience(expected string) bool {
+    if !c.IsAudienceRestricted() {
+        return true
+    }
+    return expected != "" && slices.Contains(c.Audience, expected)
+}
+
+// HasScope memeriksa apakah principal boleh memakai scope. Token login biasa
+// tidak dibatasi scope; API key dan token hasil token exchange hanya memiliki
+// scope yang diberikan saat diterbitkan.
+func (c *JWTClaims) HasScope(scope string) bool {
+    if c.APIKeyID == 0 && len(c.Scopes) == 0 && !c.IsAudienceRestricted() {
+        return true
+    }
+    return slices.Contains(c.Scopes, scope)
+}
+
+// HasAdminAccess memeriksa apakah principal boleh memakai hak admin: role-nya
+// termasuk adminRoles, principal memiliki scope admin dan token bukan token
+// impersonation.
+func (c *JWTClaims) HasAdminAccess(adminRoles []string) bool {
+    return slices.Contains(adminRoles, c.Role) && c.HasScope(ScopeAdmin) && !c.IsImpersonated()
+}
+
+// HasRequiredRole memeriksa persyaratan role (cukup salah satu dari roles).
+// Role admin hanya memenuhi persyaratan jika principal juga memiliki hak
+// admin, sehingga API key admin yang dibatasi scope-nya tidak lolos.
+func (c *JWTClaims) HasRequiredRole(roles, adminRoles []string) bool {
+    if !slices.Contains(roles, c.Role) {
+        return false
+    }
+    return !slices.Contains(adminRoles, c.Role) || c.HasAdminAccess(adminRoles)
+}
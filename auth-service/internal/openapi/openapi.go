@@ -0,0 +1,45 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// specYAML spesifikasi OpenAPI yang dipelihara manual
+//
+//go:embed openapi.yaml
+var specYAML []byte
+
+// docsHTML halaman dokumentasi yang merender /openapi.json tanpa aset eksternal
+//
+//go:embed docs.html
+var docsHTML []byte
+
+var (
+    specOnce sync.Once
+    specJSON []byte
+    specErr  error
+)
+
+// JSON mengembalikan spesifikasi OpenAPI dalam format JSON. Konversi dari
+// YAML hanya dilakukan sekali.
+func JSON() ([]byte, error) {
+    specOnce.Do(func() {
+        var doc map[string]interface{}
+        if err := yaml.Unmarshal(specYAML, &doc); err != nil {
+            specErr = fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+            return
+        }
+        specJSON, specErr = json.Marshal(doc)
+    })
+    return specJSON, specErr
+}
+
+// DocsHTML mengembalikan halaman dokumentasi API
+func DocsHTML() []byte {
+    return docsHTML
+}
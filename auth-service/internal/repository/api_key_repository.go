@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"auth-service/internal/database"
+	"auth-service/internal/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// APIKeyRepository interface untuk penyimpanan API key milik pengguna atau
+// service account
+type APIKeyRepository interface {
+    Create(ctx context.Context, key *model.APIKey) error
+    FindByKeyID(ctx context.Context, keyID string) (*model.APIKey, error)
+    ListByOwner(ctx context.Context, owner model.Principal) ([]model.APIKey, error)
+    Delete(ctx context.Context, owner model.Principal, id int64) error
+    UpdateLastUsed(ctx context.Context, id int64, lastUsedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+    db           *sql.DB
+    dialect      database.Dialect
+    queryTimeout time.Duration
+}
+
+// NewAPIKeyRepository membuat instance baru APIKeyRepository untuk dialect
+// database tertentu; setiap query dibatasi queryTimeout
+func NewAPIKeyRepository(db *sql.DB, dialect database.Dialect, queryTimeout time.Duration) APIKeyRepository {
+    return &apiKeyRepository{db: db, dialect: dialect, queryTimeout: queryTimeout}
+}
+
+// Create menyimpan API key baru
+func (r *apiKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `INSERT INTO api_keys (user_id, service_account_id, name, key_id, key_hash, scopes, expires_at, created_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+    userID, serviceAccountID := ownerColumns(key.Owner)
+    keyID, err := r.dialect.InsertReturningID(ctx, r.db, query,
+        userID, serviceAccountID, key.Name, key.KeyID, key.KeyHash, strings.Join(key.Scopes, " "), key.ExpiresAt, key.CreatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to insert api key: %w", err)
+    }
+
+    key.ID = keyID
+    return nil
+}
+
+// FindByKeyID mencari API key berdasarkan bagian publiknya
+func (r *apiKeyRepository) FindByKeyID(ctx context.Context, keyID string) (*model.APIKey, error) {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `SELECT id, user_id, service_account_id, name, key_id, key_hash, scopes, expires_at, last_used_at, created_at
+              FROM api_keys WHERE key_id = ?`
+
+    key, err := scanAPIKey(r.db.QueryRowContext(ctx, r.dialect.Rebind(query), keyID))
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return nil, model.ErrAPIKeyNotFound
+        }
+        return nil, fmt.Errorf("failed to query api key: %w", err)
+    }
+    return key, nil
+}
+
+// ListByOwner mengambil semua API key milik principal, terbaru lebih dulu
+func (r *apiKeyRepository) ListByOwner(ctx context.Context, owner model.Principal) ([]model.APIKey, error) {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `SELECT id, user_id, service_account_id, name, key_id, key_hash, scopes, expires_at, last_used_at, created_at
+              FROM api_keys WHERE ` + ownerColumn(owner) + ` = ? ORDER BY created_at DESC, id DESC`
+
+    rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), owner.ID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to query api keys: %w", err)
+    }
+    defer rows.Close()
+
+    keys := []model.APIKey{}
+    for rows.Next() {
+        key, err := scanAPIKey(rows)
+        if err != nil {
+            return nil, fmt.Errorf("failed to scan api key: %w", err)
+        }
+        keys = append(keys, *key)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to iterate api keys: %w", err)
+    }
+    return keys, nil
+}
+
+// Delete menghapus API key milik principal. Key milik principal lain
+// diperlakukan sama dengan key yang tidak ada.
+func (r *apiKeyRepository) Delete(ctx context.Context, owner model.Principal, id int64) error {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `DELETE FROM api_keys WHERE id = ? AND ` + ownerColumn(owner) + ` = ?`
+
+    result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), id, owner.ID)
+    if err != nil {
+        return fmt.Errorf("failed to delete api key: %w", err)
+    }
+
+    affected, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to get affected rows: %w", err)
+    }
+    if affected == 0 {
+        return model.ErrAPIKeyNotFound
+    }
+    return nil
+}
+
+// UpdateLastUsed mencatat waktu terakhir API key dipakai
+func (r *apiKeyRepository) UpdateLastUsed(ctx context.Context, id int64, lastUsedAt time.Time) error {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `UPDATE api_keys SET last_used_at = ? WHERE id = ?`
+
+    if _, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), lastUsedAt, id); err != nil {
+        return fmt.Errorf("failed to update api key last used: %w", err)
+    }
+    return nil
+}
+
+// rowScanner kolom yang dapat dibaca dari *sql.Row maupun *sql.Rows
+type rowScanner interface {
+    Scan(dest ...interface{}) error
+}
+
+// ownerColumn kolom api_keys yang menyimpan ID pemilik jenis principal owner
+func ownerColumn(owner model.Principal) string {
+    if owner.IsService() {
+        return "service_account_id"
+    }
+    return "user_id"
+}
+
+// ownerColumns nilai kolom user_id dan service_account_id untuk owner;
+// tepat satu yang terisi
+func ownerColumns(owner model.Principal) (userID, serviceAccountID sql.NullInt64) {
+    if owner.IsService() {
+        return userID, sql.NullInt64{Int64: owner.ID, Valid: true}
+    }
+    return sql.NullInt64{Int64: owner.ID, Valid: true}, serviceAccountID
+}
+
+// scanAPIKey membaca satu baris api_keys
+func scanAPIKey(row rowScanner) (*model.APIKey, error) {
+    key := &model.APIKey{}
+    var scopes string
+    var userID, serviceAccountID sql.NullInt64
+    var expiresAt, lastUsedAt sql.NullTime
+    err := row.Scan(&key.ID, &userID, &serviceAccountID, &key.Name, &key.KeyID, &key.KeyHash, &scopes, &expiresAt, &lastUsedAt, &key.CreatedAt)
+    if err != nil {
+        return nil, err
+    }
+
+    if serviceAccountID.Valid {
+        key.Owner = model.ServicePrincipal(serviceAccountID.Int64)
+    } else {
+        key.Owner = model.UserPrincipal(userID.Int64)
+    }
+
+    key.Scopes = strings.Fields(scopes)
+    if expiresAt.Valid {
+        key.ExpiresAt = &expiresAt.Time
+    }
+    if lastUsedAt.Valid {
+        key.LastUsedAt = &lastUsedAt.Time
+    }
+    return key, nil
+}
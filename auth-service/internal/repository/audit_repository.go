@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"auth-service/internal/database"
+	"auth-service/internal/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AuditRepository interface untuk penyimpanan event audit. Sengaja tidak
+// menyediakan operasi update atau delete karena log audit bersifat append-only.
+type AuditRepository interface {
+    Insert(ctx context.Context, event *model.AuditEvent) error
+    Find(ctx context.Context, filter model.AuditEventFilter) ([]model.AuditEvent, error)
+    ForEach(ctx context.Context, filter model.AuditEventFilter, fn func(event *model.AuditEvent) error) error
+}
+
+type auditRepository struct {
+    db           *sql.DB
+    dialect      database.Dialect
+    queryTimeout time.Duration
+}
+
+// NewAuditRepository membuat instance baru AuditRepository untuk dialect database
+// tertentu. Insert dan Find dibatasi queryTimeout; ForEach (export) hanya
+// dibatasi context pemanggil karena dapat berjalan lama.
+func NewAuditRepository(db *sql.DB, dialect database.Dialect, queryTimeout time.Duration) AuditRepository {
+    return &auditRepository{db: db, dialect: dialect, queryTimeout: queryTimeout}
+}
+
+// Insert menyimpan satu event audit
+func (r *auditRepository) Insert(ctx context.Context, event *model.AuditEvent) error {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `INSERT INTO audit_events
+              (event_type, actor_id, actor_type, actor_subject, subject_id, subject_type, subject_email, ip, user_agent, outcome, reason, created_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+    eventID, err := r.dialect.InsertReturningID(ctx, r.db, query,
+        event.EventType, event.ActorID, event.ActorType, truncate(event.ActorSubject, 255), event.SubjectID, event.SubjectType, event.SubjectEmail,
+        truncate(event.IP, 45), truncate(event.UserAgent, 512), event.Outcome, truncate(event.Reason, 255), event.CreatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to insert audit event: %w", err)
+    }
+
+    event.ID = eventID
+    return nil
+}
+
+// Find mengambil event audit sesuai filter, terbaru lebih dulu
+func (r *auditRepository) Find(ctx context.Context, filter model.AuditEventFilter) ([]model.AuditEvent, error) {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    events := []model.AuditEvent{}
+    err := r.ForEach(ctx, filter, func(event *model.AuditEvent) error {
+        events = append(events, *event)
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return events, nil
+}
+
+// ForEach mengalirkan event audit sesuai filter satu per satu tanpa memuat
+// semuanya ke memori, digunakan untuk export
+func (r *auditRepository) ForEach(ctx context.Context, filter model.AuditEventFilter, fn func(event *model.AuditEvent) error) error {
+    query, args := buildAuditQuery(filter)
+
+    rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+    if err != nil {
+        return fmt.Errorf("failed to query audit events: %w", err)
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        event := &model.AuditEvent{}
+        var actorID, subjectID sql.NullInt64
+        err := rows.Scan(&event.ID, &event.EventType, &actorID, &event.ActorType, &event.ActorSubject, &subjectID, &event.SubjectType, &event.SubjectEmail,
+            &event.IP, &event.UserAgent, &event.Outcome, &event.Reason, &event.CreatedAt)
+        if err != nil {
+            return fmt.Errorf("failed to scan audit event: %w", err)
+        }
+        if actorID.Valid {
+            event.ActorID = &actorID.Int64
+        }
+        if subjectID.Valid {
+            event.SubjectID = &subjectID.Int64
+        }
+
+        if err := fn(event); err != nil {
+            return err
+        }
+    }
+
+    if err := rows.Err(); err != nil {
+        return fmt.Errorf("failed to iterate audit events: %w", err)
+    }
+    return nil
+}
+
+// buildAuditQuery menyusun query SELECT beserta argumennya dari filter
+func buildAuditQuery(filter model.AuditEventFilter) (string, []interface{}) {
+    var conditions []string
+    var args []interface{}
+
+    // ID service account dapat sama dengan ID pengguna, sehingga jenis
+    // principal ikut dicocokkan
+    if filter.UserID != nil {
+        conditions = append(conditions, "((actor_id = ? AND actor_type = ?) OR (subject_id = ? AND subject_type = ?))")
+        args = append(args, *filter.UserID, model.PrincipalTypeUser, *filter.UserID, model.PrincipalTypeUser)
+    }
+    if filter.ActorType != "" {
+        conditions = append(conditions, "actor_type = ?")
+        args = append(args, filter.ActorType)
+    }
+    if filter.EventType != "" {
+        conditions = append(conditions, "event_type = ?")
+        args = append(args, filter.EventType)
+    }
+    if filter.From != nil {
+        conditions = append(conditions, "created_at >= ?")
+        args = append(args, *filter.From)
+    }
+    if filter.To != nil {
+        conditions = append(conditions, "created_at < ?")
+        args = append(args, *filter.To)
+    }
+
+    query := `SELECT id, event_type, actor_id, actor_type, actor_subject, subject_id, subject_type, subject_email, ip, user_agent, outcome, reason, created_at
+              FROM audit_events`
+    if len(conditions) > 0 {
+        query += " WHERE " + strings.Join(conditions, " AND ")
+    }
+    query += " ORDER BY created_at DESC, id DESC"
+
+    if filter.Limit > 0 {
+        query += " LIMIT ? OFFSET ?"
+        args = append(args, filter.Limit, filter.Offset)
+    }
+
+    return query, args
+}
+
+// truncate memotong string agar muat di kolom database
+func truncate(s string, max int) string {
+    if len(s) > max {
+        return s[:max]
+    }
+    return s
+}
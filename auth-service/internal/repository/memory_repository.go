@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryUserRepository implementasi UserRepository di memori, untuk test
+// dan development tanpa database
+type memoryUserRepository struct {
+    mu      sync.RWMutex
+    nextID  int64
+    users   map[int64]model.User
+    byEmail map[string]int64
+}
+
+// NewMemoryUserRepository membuat instance baru UserRepository di memori
+func NewMemoryUserRepository() UserRepository {
+    return &memoryUserRepository{
+        users:   make(map[int64]model.User),
+        byEmail: make(map[string]int64),
+    }
+}
+
+// CreateUser menyimpan pengguna baru; email dibandingkan tanpa membedakan
+// huruf besar/kecil seperti collation default MySQL
+func (r *memoryUserRepository) CreateUser(ctx context.Context, user *model.User) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    key := strings.ToLower(user.Email)
+    if _, exists := r.byEmail[key]; exists {
+        return model.ErrEmailTaken
+    }
+
+    r.nextID++
+    user.ID = r.nextID
+    r.users[user.ID] = *user
+    r.byEmail[key] = user.ID
+    return nil
+}
+
+// FindByEmail mencari pengguna berdasarkan email
+func (r *memoryUserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    id, ok := r.byEmail[strings.ToLower(email)]
+    if !ok {
+        return nil, model.ErrUserNotFound
+    }
+    user := r.users[id]
+    return &user, nil
+}
+
+// FindByID mencari pengguna berdasarkan ID
+func (r *memoryUserRepository) FindByID(ctx context.Context, id int64) (*model.User, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    user, ok := r.users[id]
+    if !ok {
+        return nil, model.ErrUserNotFound
+    }
+    return &user, nil
+}
+
+// UpdateRole mengubah role pengguna
+func (r *memoryUserRepository) UpdateRole(ctx context.Context, id int64, role string) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    user, ok := r.users[id]
+    if !ok {
+        return model.ErrUserNotFound
+    }
+    user.Role = role
+    r.users[id] = user
+    return nil
+}
+
+// UpdateLocale mengubah preferensi bahasa pengguna
+func (r *memoryUserRepository) UpdateLocale(ctx context.Context, id int64, locale string) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    user, ok := r.users[id]
+    if !ok {
+        return model.ErrUserNotFound
+    }
+    user.Locale = locale
+    r.users[id] = user
+    return nil
+}
+
+// memoryAuditRepository implementasi AuditRepository di memori
+type memoryAuditRepository struct {
+    mu     sync.RWMutex
+    nextID int64
+    events []model.AuditEvent
+}
+
+// NewMemoryAuditRepository membuat instance baru AuditRepository di memori
+func NewMemoryAuditRepository() AuditRepository {
+    return &memoryAuditRepository{}
+}
+
+// Insert menyimpan satu event audit
+func (r *memoryAuditRepository) Insert(ctx context.Context, event *model.AuditEvent) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    r.nextID++
+    event.ID = r.nextID
+    r.events = append(r.events, *event)
+    return nil
+}
+
+// Find mengambil event audit sesuai filter, terbaru lebih dulu
+func (r *memoryAuditRepository) Find(ctx context.Context, filter model.AuditEventFilter) ([]model.AuditEvent, error) {
+    events := []model.AuditEvent{}
+    err := r.ForEach(ctx, filter, func(event *model.AuditEvent) error {
+        events = append(events, *event)
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return events, nil
+}
+
+// ForEach mengalirkan event audit sesuai filter dengan urutan yang sama
+// seperti implementasi SQL
+func (r *memoryAuditRepository) ForEach(ctx context.Context, filter model.AuditEventFilter, fn func(event *model.AuditEvent) error) error {
+    r.mu.RLock()
+    var matched []model.AuditEvent
+    for _, event := range r.events {
+        if matchesAuditFilter(event, filter) {
+            matched = append(matched, event)
+        }
+    }
+    r.mu.RUnlock()
+
+    sort.Slice(matched, func(i, j int) bool {
+        if !matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+            return matched[i].CreatedAt.After(matched[j].CreatedAt)
+        }
+        return matched[i].ID > matched[j].ID
+    })
+
+    if filter.Limit > 0 {
+        if filter.Offset >= len(matched) {
+            matched = nil
+        } else {
+            matched = matched[filter.Offset:]
+        }
+        if len(matched) > filter.Limit {
+            matched = matched[:filter.Limit]
+        }
+    }
+
+    for i := range matched {
+        if err := fn(&matched[i]); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// matchesAuditFilter memeriksa apakah event cocok dengan filter
+func matchesAuditFilter(event model.AuditEvent, filter model.AuditEventFilter) bool {
+    if filter.UserID != nil {
+        actor := event.ActorID != nil && *event.ActorID == *filter.UserID && event.ActorType == model.PrincipalTypeUser
+        subject := event.SubjectID != nil && *event.SubjectID == *filter.UserID && event.SubjectType == model.PrincipalTypeUser
+        if !actor && !subject {
+            return false
+        }
+    }
+    if filter.ActorType != "" && event.ActorType != filter.ActorType {
+        return false
+    }
+    if filter.EventType != "" && event.EventType != filter.EventType {
+        return false
+    }
+    if filter.From != nil && event.CreatedAt.Before(*filter.From) {
+        return false
+    }
+    if filter.To != nil && !event.CreatedAt.Before(*filter.To) {
+        return false
+    }
+    return true
+}
+
+// memoryAPIKeyRepository implementasi APIKeyRepository di memori
+type memoryAPIKeyRepository struct {
+    mu     sync.RWMutex
+    nextID int64
+    keys   map[int64]model.APIKey
+}
+
+// NewMemoryAPIKeyRepository membuat instance baru APIKeyRepository di memori
+func NewMemoryAPIKeyRepository() APIKeyRepository {
+    return &memoryAPIKeyRepository{keys: make(map[int64]model.APIKey)}
+}
+
+// Create menyimpan API key baru
+func (r *memoryAPIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    r.nextID++
+    key.ID = r.nextID
+    r.keys[key.ID] = cloneAPIKey(*key)
+    return nil
+}
+
+// FindByKeyID mencari API key berdasarkan bagian publiknya
+func (r *memoryAPIKeyRepository) FindByKeyID(ctx context.Context, keyID string) (*model.APIKey, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    for _, key := range r.keys {
+        if key.KeyID == keyID {
+            key = cloneAPIKey(key)
+            return &key, nil
+        }
+    }
+    return nil, model.ErrAPIKeyNotFound
+}
+
+// ListByOwner mengambil semua API key milik principal dengan urutan yang
+// sama seperti implementasi SQL
+func (r *memoryAPIKeyRepository) ListByOwner(ctx context.Context, owner model.Principal) ([]model.APIKey, error) {
+    r.mu.RLock()
+    keys := []model.APIKey{}
+    for _, key := range r.keys {
+        if key.Owner == owner {
+            keys = append(keys, cloneAPIKey(key))
+        }
+    }
+    r.mu.RUnlock()
+
+    sort.Slice(keys, func(i, j int) bool {
+        if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+            return keys[i].CreatedAt.After(keys[j].CreatedAt)
+        }
+        return keys[i].ID > keys[j].ID
+    })
+    return keys, nil
+}
+
+// Delete menghapus API key milik principal
+func (r *memoryAPIKeyRepository) Delete(ctx context.Context, owner model.Principal, id int64) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    key, ok := r.keys[id]
+    if !ok || key.Owner != owner {
+        return model.ErrAPIKeyNotFound
+    }
+    delete(r.keys, id)
+    return nil
+}
+
+// UpdateLastUsed mencatat waktu terakhir API key dipakai
+func (r *memoryAPIKeyRepository) UpdateLastUsed(ctx context.Context, id int64, lastUsedAt time.Time) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    if key, ok := r.keys[id]; ok {
+        key.LastUsedAt = &lastUsedAt
+        r.keys[id] = key
+    }
+    return nil
+}
+
+// cloneAPIKey menyalin key agar pemanggil tidak berbagi slice scope dengan store
+func cloneAPIKey(key model.APIKey) model.APIKey {
+    key.Scopes = slices.Clone(key.Scopes)
+    return key
+}
+
+// memoryServiceAccountRepository implementasi ServiceAccountRepository di memori
+type memoryServiceAccountRepository struct {
+    mu       sync.RWMutex
+    nextID   int64
+    accounts map[int64]model.ServiceAccount
+}
+
+// NewMemoryServiceAccountRepository membuat instance baru
+// ServiceAccountRepository di memori
+func NewMemoryServiceAccountRepository() ServiceAccountRepository {
+    return &memoryServiceAccountRepository{accounts: make(map[int64]model.ServiceAccount)}
+}
+
+// Create menyimpan service account baru; nama harus unik
+func (r *memoryServiceAccountRepository) Create(ctx context.Context, account *model.ServiceAccount) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    for _, existing := range r.accounts {
+        if existing.Name == account.Name {
+            return model.ErrServiceAccountNameTaken
+        }
+    }
+
+    r.nextID++
+    account.ID = r.nextID
+    r.accounts[account.ID] = *account
+    return nil
+}
+
+// FindByID mencari service account berdasarkan ID
+func (r *memoryServiceAccountRepository) FindByID(ctx context.Context, id int64) (*model.ServiceAccount, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    account, ok := r.accounts[id]
+    if !ok {
+        return nil, model.ErrServiceAccountNotFound
+    }
+    return &account, nil
+}
+
+// FindByClientID mencari service account berdasarkan client ID
+func (r *memoryServiceAccountRepository) FindByClientID(ctx context.Context, clientID string) (*model.ServiceAccount, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    for _, account := range r.accounts {
+        if account.ClientID == clientID {
+            return &account, nil
+        }
+    }
+    return nil, model.ErrServiceAccountNotFound
+}
+
+// List mengambil semua service account, diurutkan berdasarkan nama
+func (r *memoryServiceAccountRepository) List(ctx context.Context) ([]model.ServiceAccount, error) {
+    r.mu.RLock()
+    accounts := []model.ServiceAccount{}
+    for _, account := range r.accounts {
+        accounts = append(accounts, account)
+    }
+    r.mu.RUnlock()
+
+    sort.Slice(accounts, func(i, j int) bool {
+        return accounts[i].Name < accounts[j].Name
+    })
+    return accounts, nil
+}
+
+// Update menyimpan perubahan service account
+func (r *memoryServiceAccountRepository) Update(ctx context.Context, account *model.ServiceAccount) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    existing, ok := r.accounts[account.ID]
+    if !ok {
+        return model.ErrServiceAccountNotFound
+    }
+    existing.Description = account.Description
+    existing.Role = account.Role
+    existing.Disabled = account.Disabled
+    existing.ClientSecretHash = account.ClientSecretHash
+    r.accounts[account.ID] = existing
+    return nil
+}
+
+// Delete menghapus service account. Berbeda dengan implementasi SQL, API key
+// miliknya tidak ikut terhapus tetapi tidak lagi dapat dipakai.
+func (r *memoryServiceAccountRepository) Delete(ctx context.Context, id int64) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    if _, ok := r.accounts[id]; !ok {
+        return model.ErrServiceAccountNotFound
+    }
+    delete(r.accounts, id)
+    return nil
+}
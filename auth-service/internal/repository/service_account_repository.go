@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"auth-service/internal/database"
+	"auth-service/internal/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ServiceAccountRepository interface untuk penyimpanan service account
+type ServiceAccountRepository interface {
+    Create(ctx context.Context, account *model.ServiceAccount) error
+    FindByID(ctx context.Context, id int64) (*model.ServiceAccount, error)
+    FindByClientID(ctx context.Context, clientID string) (*model.ServiceAccount, error)
+    List(ctx context.Context) ([]model.ServiceAccount, error)
+    Update(ctx context.Context, account *model.ServiceAccount) error
+    Delete(ctx context.Context, id int64) error
+}
+
+type serviceAccountRepository struct {
+    db           *sql.DB
+    dialect      database.Dialect
+    queryTimeout time.Duration
+}
+
+// NewServiceAccountRepository membuat instance baru ServiceAccountRepository
+// untuk dialect database tertentu; setiap query dibatasi queryTimeout
+func NewServiceAccountRepository(db *sql.DB, dialect database.Dialect, queryTimeout time.Duration) ServiceAccountRepository {
+    return &serviceAccountRepository{db: db, dialect: dialect, queryTimeout: queryTimeout}
+}
+
+// serviceAccountColumns kolom yang dibaca scanServiceAccount
+const serviceAccountColumns = `id, name, description, role, client_id, client_secret_hash, disabled, created_at`
+
+// Create menyimpan service account baru; nama harus unik
+func (r *serviceAccountRepository) Create(ctx context.Context, account *model.ServiceAccount) error {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `INSERT INTO service_accounts (name, description, role, client_id, client_secret_hash, disabled, created_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+    accountID, err := r.dialect.InsertReturningID(ctx, r.db, query,
+        account.Name, account.Description, account.Role, account.ClientID, account.ClientSecretHash, account.Disabled, account.CreatedAt,
+    )
+    if err != nil {
+        if r.dialect.IsUniqueViolation(err) {
+            return model.ErrServiceAccountNameTaken
+        }
+        return fmt.Errorf("failed to insert service account: %w", err)
+    }
+
+    account.ID = accountID
+    return nil
+}
+
+// FindByID mencari service account berdasarkan ID
+func (r *serviceAccountRepository) FindByID(ctx context.Context, id int64) (*model.ServiceAccount, error) {
+    return r.findOne(ctx, `SELECT `+serviceAccountColumns+` FROM service_accounts WHERE id = ?`, id)
+}
+
+// FindByClientID mencari service account berdasarkan client ID
+func (r *serviceAccountRepository) FindByClientID(ctx context.Context, clientID string) (*model.ServiceAccount, error) {
+    return r.findOne(ctx, `SELECT `+serviceAccountColumns+` FROM service_accounts WHERE client_id = ?`, clientID)
+}
+
+// findOne menjalankan query yang menghasilkan paling banyak satu service account
+func (r *serviceAccountRepository) findOne(ctx context.Context, query string, arg interface{}) (*model.ServiceAccount, error) {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    account, err := scanServiceAccount(r.db.QueryRowContext(ctx, r.dialect.Rebind(query), arg))
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return nil, model.ErrServiceAccountNotFound
+        }
+        return nil, fmt.Errorf("failed to query service account: %w", err)
+    }
+    return account, nil
+}
+
+// List mengambil semua service account, diurutkan berdasarkan nama
+func (r *serviceAccountRepository) List(ctx context.Context) ([]model.ServiceAccount, error) {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `SELECT ` + serviceAccountColumns + ` FROM service_accounts ORDER BY name`
+
+    rows, err := r.db.QueryContext(ctx, query)
+    if err != nil {
+        return nil, fmt.Errorf("failed to query service accounts: %w", err)
+    }
+    defer rows.Close()
+
+    accounts := []model.ServiceAccount{}
+    for rows.Next() {
+        account, err := scanServiceAccount(rows)
+        if err != nil {
+            return nil, fmt.Errorf("failed to scan service account: %w", err)
+        }
+        accounts = append(accounts, *account)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to iterate service accounts: %w", err)
+    }
+    return accounts, nil
+}
+
+// Update menyimpan description, role, status disabled dan hash secret.
+// Jumlah baris terpengaruh tidak diperiksa karena MySQL mengembalikan 0 untuk
+// update tanpa perubahan nilai; pemanggil memastikan service account ada.
+func (r *serviceAccountRepository) Update(ctx context.Context, account *model.ServiceAccount) error {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `UPDATE service_accounts SET description = ?, role = ?, disabled = ?, client_secret_hash = ? WHERE id = ?`
+
+    _, err := r.db.ExecContext(ctx, r.dialect.Rebind(query),
+        account.Description, account.Role, account.Disabled, account.ClientSecretHash, account.ID,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to update service account: %w", err)
+    }
+    return nil
+}
+
+// Delete menghapus service account beserta API key miliknya (ON DELETE CASCADE)
+func (r *serviceAccountRepository) Delete(ctx context.Context, id int64) error {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `DELETE FROM service_accounts WHERE id = ?`
+
+    result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), id)
+    if err != nil {
+        return fmt.Errorf("failed to delete service account: %w", err)
+    }
+
+    affected, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to get affected rows: %w", err)
+    }
+    if affected == 0 {
+        return model.ErrServiceAccountNotFound
+    }
+    return nil
+}
+
+// scanServiceAccount membaca satu baris service_accounts
+func scanServiceAccount(row rowScanner) (*model.ServiceAccount, error) {
+    account := &model.ServiceAccount{}
+    err := row.Scan(&account.ID, &account.Name, &account.Description, &account.Role,
+        &account.ClientID, &account.ClientSecretHash, &account.Disabled, &account.CreatedAt)
+    if err != nil {
+        return nil, err
+    }
+    return account, nil
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// withTimeout membatasi durasi satu query. Timeout 0 berarti query hanya
+// dibatasi oleh context pemanggil (misalnya request yang dibatalkan klien).
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+    if timeout <= 0 {
+        return context.WithCancel(ctx)
+    }
+    return context.WithTimeout(ctx, timeout)
+}
@@ -1,66 +1,54 @@
 package repository
 
 import (
+	"auth-service/internal/database"
+	"auth-service/internal/metrics"
 	"auth-service/internal/model"
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 // UserRepository interface untuk operasi database pengguna
 type UserRepository interface {
-    Migrate() error
-    CreateUser(user *model.User) error
-    FindByEmail(email string) (*model.User, error)
-    FindByID(id int64) (*model.User, error)
+    CreateUser(ctx context.Context, user *model.User) error
+    FindByEmail(ctx context.Context, email string) (*model.User, error)
+    FindByID(ctx context.Context, id int64) (*model.User, error)
+    UpdateRole(ctx context.Context, id int64, role string) error
+    UpdateLocale(ctx context.Context, id int64, locale string) error
 }
 
 type userRepository struct {
-    db *sql.DB
+    db           *sql.DB
+    dialect      database.Dialect
+    queryTimeout time.Duration
 }
 
-// NewUserRepository membuat instance baru UserRepository
-func NewUserRepository(db *sql.DB) UserRepository {
-    return &userRepository{db: db}
-}
-
-// Migrate membuat tabel users jika belum ada
-func (r *userRepository) Migrate() error {
-    query := `
-    CREATE TABLE IF NOT EXISTS users (
-        id INT AUTO_INCREMENT PRIMARY KEY,
-        name VARCHAR(255) NOT NULL,
-        email VARCHAR(255) UNIQUE NOT NULL,
-        password_hash TEXT NOT NULL,
-        role VARCHAR(50) DEFAULT 'user',
-        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-    )
-    `
-
-    _, err := r.db.Exec(query)
-    if err != nil {
-        return fmt.Errorf("failed to create users table: %v", err)
-    }
-
-    log.Println("Users table migrated successfully")
-    return nil
+// NewUserRepository membuat instance baru UserRepository untuk dialect database
+// tertentu; setiap query dibatasi queryTimeout
+func NewUserRepository(db *sql.DB, dialect database.Dialect, queryTimeout time.Duration) UserRepository {
+    return &userRepository{db: db, dialect: dialect, queryTimeout: queryTimeout}
 }
 
 // CreateUser menyimpan pengguna baru ke database
-func (r *userRepository) CreateUser(user *model.User) error {
-    query := `INSERT INTO users (name, email, password_hash, role, created_at) 
-              VALUES (?, ?, ?, ?, ?)`
+func (r *userRepository) CreateUser(ctx context.Context, user *model.User) error {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
 
-    result, err := r.db.Exec(query, user.Name, user.Email, user.PasswordHash, user.Role, user.CreatedAt)
-    if err != nil {
-        return fmt.Errorf("failed to create user: %v", err)
-    }
+    query := `INSERT INTO users (name, email, password_hash, role, locale, created_at) 
+              VALUES (?, ?, ?, ?, ?, ?)`
 
-    userID, err := result.LastInsertId()
+    userID, err := r.dialect.InsertReturningID(ctx, r.db, query, user.Name, user.Email, user.PasswordHash, user.Role, user.Locale, user.CreatedAt)
     if err != nil {
-        return fmt.Errorf("failed to get last insert ID: %v", err)
+        if r.dialect.IsUniqueViolation(err) {
+            return model.ErrEmailTaken
+        }
+        slog.ErrorContext(ctx, "Failed to insert user", "error", err)
+        return fmt.Errorf("failed to create user: %w", err)
     }
 
     user.ID = userID
@@ -68,53 +56,117 @@ func (r *userRepository) CreateUser(user *model.User) error {
 }
 
 // FindByEmail mencari pengguna berdasarkan email
-func (r *userRepository) FindByEmail(email string) (*model.User, error) {
-    query := `SELECT id, name, email, password_hash, role, created_at 
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `SELECT id, name, email, password_hash, role, locale, created_at 
               FROM users WHERE email = ?`
 
-    row := r.db.QueryRow(query, email)
+    row := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), email)
     user := &model.User{}
 
-    err := row.Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.Role, &user.CreatedAt)
+    err := row.Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.Role, &user.Locale, &user.CreatedAt)
     if err != nil {
         if err == sql.ErrNoRows {
-            return nil, fmt.Errorf("user not found")
+            return nil, model.ErrUserNotFound
         }
-        return nil, fmt.Errorf("failed to query user: %v", err)
+        slog.ErrorContext(ctx, "Failed to query user", "error", err)
+        return nil, fmt.Errorf("failed to query user: %w", err)
     }
 
     return user, nil
 }
 
 // FindByID mencari pengguna berdasarkan ID
-func (r *userRepository) FindByID(id int64) (*model.User, error) {
-    query := `SELECT id, name, email, password_hash, role, created_at 
+func (r *userRepository) FindByID(ctx context.Context, id int64) (*model.User, error) {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `SELECT id, name, email, password_hash, role, locale, created_at 
               FROM users WHERE id = ?`
 
-    row := r.db.QueryRow(query, id)
+    row := r.db.QueryRowContext(ctx, r.dialect.Rebind(query), id)
     user := &model.User{}
 
-    err := row.Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.Role, &user.CreatedAt)
+    err := row.Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.Role, &user.Locale, &user.CreatedAt)
     if err != nil {
         if err == sql.ErrNoRows {
-            return nil, fmt.Errorf("user not found")
+            return nil, model.ErrUserNotFound
         }
-        return nil, fmt.Errorf("failed to query user: %v", err)
+        slog.ErrorContext(ctx, "Failed to query user", "error", err)
+        return nil, fmt.Errorf("failed to query user: %w", err)
     }
 
     return user, nil
 }
 
+// UpdateRole mengubah role pengguna
+func (r *userRepository) UpdateRole(ctx context.Context, id int64, role string) error {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `UPDATE users SET role = ? WHERE id = ?`
+
+    result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), role, id)
+    if err != nil {
+        slog.ErrorContext(ctx, "Failed to update user role", "error", err)
+        return fmt.Errorf("failed to update user role: %w", err)
+    }
+
+    affected, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to get affected rows: %w", err)
+    }
+    if affected == 0 {
+        return model.ErrUserNotFound
+    }
+    return nil
+}
+
+// UpdateLocale mengubah preferensi bahasa pengguna
+func (r *userRepository) UpdateLocale(ctx context.Context, id int64, locale string) error {
+    ctx, cancel := withTimeout(ctx, r.queryTimeout)
+    defer cancel()
+
+    query := `UPDATE users SET locale = ? WHERE id = ?`
+
+    result, err := r.db.ExecContext(ctx, r.dialect.Rebind(query), locale, id)
+    if err != nil {
+        slog.ErrorContext(ctx, "Failed to update user locale", "error", err)
+        return fmt.Errorf("failed to update user locale: %w", err)
+    }
+
+    affected, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to get affected rows: %w", err)
+    }
+    if affected == 0 {
+        return model.ErrUserNotFound
+    }
+    return nil
+}
+
 // HashPassword menghasilkan hash dari password menggunakan bcrypt
 func HashPassword(password string) (string, error) {
+    start := time.Now()
+    defer func() {
+        metrics.PasswordHashDuration.WithLabelValues("hash").Observe(time.Since(start).Seconds())
+    }()
+
     hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
     if err != nil {
-        return "", fmt.Errorf("failed to hash password: %v", err)
+        return "", fmt.Errorf("failed to hash password: %w", err)
     }
     return string(hashedBytes), nil
 }
 
 // CheckPassword memverifikasi password dengan hash
 func CheckPassword(password, hash string) error {
+    start := time.Now()
+    defer func() {
+        metrics.PasswordHashDuration.WithLabelValues("compare").Observe(time.Since(start).Seconds())
+    }()
+
     return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 }
\ No newline at end of file
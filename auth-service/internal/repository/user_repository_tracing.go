@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/telemetry"
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("auth-service/internal/repository")
+
+// tracedUserRepository membungkus UserRepository dan membuat span untuk setiap query
+type tracedUserRepository struct {
+    next     UserRepository
+    dbSystem string
+}
+
+// NewTracedUserRepository membungkus UserRepository dengan tracing OpenTelemetry
+func NewTracedUserRepository(next UserRepository, dbSystem string) UserRepository {
+    return &tracedUserRepository{next: next, dbSystem: dbSystem}
+}
+
+// startSpan memulai span query dengan atribut database standar
+func (r *tracedUserRepository) startSpan(ctx context.Context, name, operation string) (context.Context, trace.Span) {
+    return tracer.Start(ctx, name,
+        trace.WithSpanKind(trace.SpanKindClient),
+        trace.WithAttributes(
+            attribute.String("db.system", r.dbSystem),
+            attribute.String("db.operation", operation),
+            attribute.String("db.sql.table", "users"),
+        ),
+    )
+}
+
+func (r *tracedUserRepository) CreateUser(ctx context.Context, user *model.User) error {
+    ctx, span := r.startSpan(ctx, "UserRepository.CreateUser", "INSERT")
+    err := r.next.CreateUser(ctx, user)
+    telemetry.EndSpan(span, err)
+    return err
+}
+
+func (r *tracedUserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+    ctx, span := r.startSpan(ctx, "UserRepository.FindByEmail", "SELECT")
+    user, err := r.next.FindByEmail(ctx, email)
+    telemetry.EndSpan(span, err)
+    return user, err
+}
+
+func (r *tracedUserRepository) FindByID(ctx context.Context, id int64) (*model.User, error) {
+    ctx, span := r.startSpan(ctx, "UserRepository.FindByID", "SELECT")
+    user, err := r.next.FindByID(ctx, id)
+    telemetry.EndSpan(span, err)
+    return user, err
+}
+
+func (r *tracedUserRepository) UpdateRole(ctx context.Context, id int64, role string) error {
+    ctx, span := r.startSpan(ctx, "UserRepository.UpdateRole", "UPDATE")
+    err := r.next.UpdateRole(ctx, id, role)
+    telemetry.EndSpan(span, err)
+    return err
+}
+
+func (r *tracedUserRepository) UpdateLocale(ctx context.Context, id int64, locale string) error {
+    ctx, span := r.startSpan(ctx, "UserRepository.UpdateLocale", "UPDATE")
+    err := r.next.UpdateLocale(ctx, id, locale)
+    telemetry.EndSpan(span, err)
+    return err
+}
@@ -0,0 +1,107 @@
+package router
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// ginParam parameter path Gin (":id") yang di OpenAPI ditulis "{id}"
+var ginParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// openAPISpec mengambil /openapi.json dari router
+func openAPISpec(t *testing.T, s *testServer) map[string]interface{} {
+	t.Helper()
+	w := s.do(http.MethodGet, "/openapi.json", nil, "")
+	expectStatus(t, w, http.StatusOK)
+	return decode(t, w)
+}
+
+func TestSpecMatchesRoutes(t *testing.T) {
+	s := newTestServer(t)
+	spec := openAPISpec(t, s)
+
+	registered := map[string]bool{}
+	for _, route := range s.router.Routes() {
+		path := ginParam.ReplaceAllString(route.Path, "{$1}")
+		registered[route.Method+" "+path] = true
+	}
+
+	documented := map[string]bool{}
+	for path, item := range spec["paths"].(map[string]interface{}) {
+		for method := range item.(map[string]interface{}) {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for _, route := range sortedKeys(registered) {
+		if !documented[route] {
+			t.Errorf("route %s is registered but missing from openapi.yaml", route)
+		}
+	}
+	for _, route := range sortedKeys(documented) {
+		if !registered[route] {
+			t.Errorf("route %s is documented in openapi.yaml but not registered", route)
+		}
+	}
+}
+
+func TestSpecReferencesResolve(t *testing.T) {
+	s := newTestServer(t)
+	spec := openAPISpec(t, s)
+
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch node := node.(type) {
+		case map[string]interface{}:
+			if ref, ok := node["$ref"].(string); ok && resolveRef(spec, ref) == nil {
+				t.Errorf("unresolved $ref %q", ref)
+			}
+			for _, child := range node {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range node {
+				walk(child)
+			}
+		}
+	}
+	walk(spec)
+
+	if version, _ := spec["openapi"].(string); !strings.HasPrefix(version, "3.1") {
+		t.Errorf("openapi = %q, want 3.1.x", version)
+	}
+
+	w := s.do(http.MethodGet, "/docs", nil, "")
+	expectStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("docs Content-Type = %q, want text/html", ct)
+	}
+}
+
+// resolveRef mengikuti $ref lokal seperti "#/components/schemas/User"
+func resolveRef(spec map[string]interface{}, ref string) interface{} {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	var node interface{} = spec
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		node = object[part]
+	}
+	return node
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,61 +1,228 @@
 package router
 
 import (
+	"auth-service/internal/config"
+	"auth-service/internal/database"
 	"auth-service/internal/handler"
+	"auth-service/internal/health"
+	"auth-service/internal/i18n"
 	"auth-service/internal/middleware"
+	"auth-service/internal/model"
 	"auth-service/internal/repository"
 	"auth-service/internal/service"
+	"auth-service/internal/signing"
+	"auth-service/internal/tlsauth"
 	"database/sql"
-	"time"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-// SetupRouter mengkonfigurasi semua route aplikasi
-func SetupRouter(db *sql.DB, jwtSecret, jwtIssuer string, jwtExpiry time.Duration) *gin.Engine {
-	// Inisialisasi repository
-	userRepo := repository.NewUserRepository(db)
+// Dependencies berisi semua dependency yang dibutuhkan router. Dengan
+// mengisinya secara manual (misalnya repository di memori), router dapat
+// dibuat tanpa database. AuditLogger, AuthService, APIKeyService,
+// ServiceAccountService, ImpersonationService dan TokenExchangeService dibuat
+// dari repository jika kosong; tanpa APIKeyRepo atau ServiceAccountRepo,
+// fitur yang bersangkutan tidak tersedia.
+type Dependencies struct {
+	UserRepo              repository.UserRepository
+	AuditRepo             repository.AuditRepository
+	APIKeyRepo            repository.APIKeyRepository
+	ServiceAccountRepo    repository.ServiceAccountRepository
+	TokenBlacklist        *service.TokenBlacklist
+	HealthChecker         *health.Checker
+	SigningKeys           *signing.Keys
+	AuditLogger           *service.AuditLogger
+	AuthService           service.AuthService
+	APIKeyService         service.APIKeyService
+	ServiceAccountService service.ServiceAccountService
+	ImpersonationService  service.ImpersonationService
+	TokenExchangeService  service.TokenExchangeService
+}
+
+// SetupRouter mengkonfigurasi semua route aplikasi dengan repository SQL
+func SetupRouter(db *sql.DB, cfg *config.Config, tokenBlacklist *service.TokenBlacklist, healthChecker *health.Checker, signingKeys *signing.Keys) *gin.Engine {
+	return New(cfg, NewDependencies(db, cfg, tokenBlacklist, healthChecker, signingKeys))
+}
 
-	// Jalankan migration
-	err := userRepo.Migrate()
+// NewDependencies membuat repository SQL beserta service di atasnya. Service
+// yang sama dapat dipakai bersama oleh router dan server gRPC.
+func NewDependencies(db *sql.DB, cfg *config.Config, tokenBlacklist *service.TokenBlacklist, healthChecker *health.Checker, signingKeys *signing.Keys) Dependencies {
+	// Inisialisasi repository
+	dialect, err := database.DialectFor(cfg.Database.Driver)
 	if err != nil {
-		panic("Failed to migrate database: " + err.Error())
+		panic("Failed to setup repositories: " + err.Error())
 	}
 
-	// Inisialisasi service
-	authService := service.NewAuthService(userRepo, jwtSecret, jwtIssuer, jwtExpiry)
+	return withServices(cfg, Dependencies{
+		UserRepo:           repository.NewTracedUserRepository(repository.NewUserRepository(db, dialect, cfg.Database.QueryTimeout), dialect.System),
+		AuditRepo:          repository.NewAuditRepository(db, dialect, cfg.Database.QueryTimeout),
+		APIKeyRepo:         repository.NewAPIKeyRepository(db, dialect, cfg.Database.QueryTimeout),
+		ServiceAccountRepo: repository.NewServiceAccountRepository(db, dialect, cfg.Database.QueryTimeout),
+		TokenBlacklist:     tokenBlacklist,
+		HealthChecker:      healthChecker,
+		SigningKeys:        signingKeys,
+	})
+}
+
+// withServices melengkapi AuditLogger, AuthService, APIKeyService,
+// ServiceAccountService, ImpersonationService dan TokenExchangeService yang
+// belum diisi
+func withServices(cfg *config.Config, deps Dependencies) Dependencies {
+	if deps.AuditLogger == nil {
+		deps.AuditLogger = service.NewAuditLogger(deps.AuditRepo)
+	}
+	if deps.AuthService == nil {
+		deps.AuthService = service.NewTracedAuthService(service.NewAuthService(
+			deps.UserRepo,
+			deps.SigningKeys,
+			cfg.JWT.Issuer,
+			cfg.JWT.TTL,
+			cfg.Security.Roles,
+			cfg.Security.DefaultRole,
+			deps.TokenBlacklist,
+			deps.AuditLogger,
+		))
+	}
+	if deps.ImpersonationService == nil && cfg.Impersonation.Enabled {
+		deps.ImpersonationService = service.NewImpersonationService(
+			deps.UserRepo,
+			deps.AuthService,
+			deps.SigningKeys,
+			cfg.JWT.Issuer,
+			cfg.Impersonation.TokenTTL,
+			cfg.Security.AdminRoles,
+			deps.AuditLogger,
+		)
+	}
+	if deps.ServiceAccountService == nil && deps.ServiceAccountRepo != nil && cfg.ServiceAccounts.Enabled {
+		deps.ServiceAccountService = service.NewServiceAccountService(
+			deps.ServiceAccountRepo,
+			deps.SigningKeys,
+			cfg.JWT.Issuer,
+			cfg.ServiceAccounts.TokenTTL,
+			cfg.Security,
+			deps.AuditLogger,
+		)
+	}
+	// Client token exchange adalah service account
+	if deps.TokenExchangeService == nil && deps.ServiceAccountService != nil && cfg.TokenExchange.Enabled {
+		deps.TokenExchangeService = service.NewTokenExchangeService(
+			deps.AuthService,
+			deps.ServiceAccountService,
+			deps.SigningKeys,
+			cfg.JWT.Issuer,
+			cfg.TokenExchange,
+			cfg.Security,
+			deps.AuditLogger,
+		)
+	}
+	if deps.APIKeyService == nil && deps.APIKeyRepo != nil && cfg.APIKeys.Enabled {
+		// API key milik service account hanya dikenali jika service account aktif
+		var serviceAccountRepo repository.ServiceAccountRepository
+		if deps.ServiceAccountService != nil {
+			serviceAccountRepo = deps.ServiceAccountRepo
+		}
+		deps.APIKeyService = service.NewAPIKeyService(
+			deps.APIKeyRepo,
+			deps.UserRepo,
+			serviceAccountRepo,
+			cfg.APIKeys,
+			cfg.Security,
+			cfg.JWT.Issuer,
+			deps.AuditLogger,
+		)
+	}
+	return deps
+}
+
+// New membuat router dari konfigurasi dan dependency yang diberikan
+func New(cfg *config.Config, deps Dependencies) *gin.Engine {
+	deps = withServices(cfg, deps)
+	authService, auditLogger := deps.AuthService, deps.AuditLogger
 
 	// Inisialisasi handler
-	authHandler := handler.NewAuthHandler(authService, jwtSecret, jwtExpiry)
+	authHandler := handler.NewAuthHandler(authService, cfg.Cookies, cfg.JWT.TTL)
+	adminHandler := handler.NewAdminHandler(authService, auditLogger)
+	healthHandler := handler.NewHealthHandler(deps.HealthChecker)
+	jwksHandler := handler.NewJWKSHandler(deps.SigningKeys)
+	forwardAuthHandler := handler.NewForwardAuthHandler(authService, deps.APIKeyService, cfg.Cookies.Name, cfg.Security, cfg.ForwardAuth)
+	docsHandler, err := handler.NewDocsHandler()
+	if err != nil {
+		panic("Failed to load OpenAPI spec: " + err.Error())
+	}
 
 	// Inisialisasi middleware
-	jwtAuthMiddleware := middleware.NewJWTAuthMiddleware(authService)
+	jwtAuthMiddleware := middleware.NewJWTAuthMiddleware(authService, cfg.Cookies.Name)
+	adminRoleMiddleware := middleware.NewRoleAuthMiddleware(cfg.Security.AdminRoles)
+	humanAdminMiddleware := middleware.NewRoleAuthMiddleware(cfg.Security.AdminRoles, model.PrincipalTypeUser)
+	clientCertAuthMiddleware := middleware.NewClientCertAuthMiddleware(tlsauth.NewPrincipalMapper(cfg.TLS.ServicePrincipals))
+	// Aksi sensitif (kredensial, session baru, admin) tidak boleh dilakukan
+	// dengan token impersonation
+	denyImpersonation := middleware.DenyImpersonation()
+
+	// Pesan error validasi diterjemahkan dan memakai nama field JSON
+	if err := i18n.RegisterBindingValidator(); err != nil {
+		panic("Failed to register validator translations: " + err.Error())
+	}
 
-	// Buat router
-	router := gin.Default()
+	// Buat router; logging dan recovery memakai slog (bukan logger bawaan Gin)
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
 	if err := router.SetTrustedProxies(nil); err != nil {
 		panic("Failed to set trusted proxies: " + err.Error())
 	}
 
 	// Setup CORS middleware - IMPORTANT!
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:3001"}, // Port Next.js
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Accept"},
-		AllowCredentials: true, // Penting untuk cookie HttpOnly
-		MaxAge:           12 * time.Hour,
-	}))
-
-	// Route untuk health check
+	// Dipasang di level engine agar preflight OPTIONS ditangani oleh middleware
+	// CORS sendiri (termasuk untuk route yang tidak mendaftarkan OPTIONS)
+	corsMiddleware, err := middleware.NewCORSMiddleware(cfg.CORS)
+	if err != nil {
+		panic("Failed to setup CORS: " + err.Error())
+	}
+	// Recovery dipasang pertama agar panic di middleware mana pun tetap
+	// menjadi response 500
+	router.Use(middleware.Recovery())
+	// Tracing dipasang sebelum middleware lain agar span request mencakup
+	// semuanya; trace context dari header traceparent upstream ikut diteruskan
+	router.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+	router.Use(middleware.RequestID())
+	router.Use(middleware.ClientInfo())
+	// Bahasa pesan response (Accept-Language atau preferensi pengguna)
+	router.Use(middleware.Locale(cfg.I18n.DefaultLocale))
+	router.Use(middleware.AccessLog())
+	router.Use(middleware.Metrics())
+	// Error yang dicatat lewat c.Error diubah menjadi application/problem+json
+	router.Use(middleware.ErrorHandler())
+	router.Use(corsMiddleware.Middleware())
+	router.Use(middleware.BodyLimit(cfg.Server.MaxBodyBytes))
+
+	router.NoRoute(func(c *gin.Context) {
+		_ = c.Error(model.ErrRouteNotFound)
+	})
+	router.NoMethod(func(c *gin.Context) {
+		_ = c.Error(model.ErrMethodNotAllowed)
+	})
+
+	// Route untuk health check. /health dipertahankan untuk kompatibilitas
+	// dan berperilaku seperti /livez.
+	router.GET("/livez", healthHandler.Livez)
+	router.GET("/readyz", healthHandler.Readyz)
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "OK"})
 	})
 
-	// Handle OPTIONS requests untuk semua route (fallback)
-	router.OPTIONS("/*any", func(c *gin.Context) {
-		c.Status(200)
-	})
+	// Dokumentasi API; setiap route baru juga harus ditambahkan ke
+	// internal/openapi/openapi.yaml (diperiksa oleh TestSpecMatchesRoutes)
+	router.GET("/openapi.json", docsHandler.Spec)
+	router.GET("/docs", docsHandler.UI)
+
+	// Public key untuk verifikasi token RS256/EdDSA di layanan lain
+	router.GET("/.well-known/jwks.json", jwksHandler.JWKS)
+
+	// Subrequest autentikasi dari reverse proxy (nginx auth_request, Traefik ForwardAuth)
+	router.GET("/auth/forward", forwardAuthHandler.Forward)
+	router.HEAD("/auth/forward", forwardAuthHandler.Forward)
 
 	// Grup route API
 	api := router.Group("/api")
@@ -64,12 +231,69 @@ func SetupRouter(db *sql.DB, jwtSecret, jwtIssuer string, jwtExpiry time.Duratio
 		api.POST("/register", authHandler.Register)
 		api.POST("/login", authHandler.Login)
 
-		// Protected routes (memerlukan JWT)
+		// Token endpoint OAuth 2.0 untuk service account (client_credentials
+		// dan token exchange)
+		if deps.ServiceAccountService != nil {
+			tokenHandler := handler.NewTokenHandler(deps.ServiceAccountService, deps.TokenExchangeService)
+			api.POST("/oauth/token", middleware.OAuthErrorHandler(), tokenHandler.Token)
+		}
+
+		// Protected routes (memerlukan JWT, API key atau sertifikat klien mTLS)
 		protected := api.Group("")
+		protected.Use(clientCertAuthMiddleware.Middleware())
+		if deps.APIKeyService != nil {
+			protected.Use(middleware.NewAPIKeyAuthMiddleware(deps.APIKeyService).Middleware())
+		}
 		protected.Use(jwtAuthMiddleware.Middleware())
 		{
 			protected.POST("/logout", authHandler.Logout)
 			protected.GET("/validate", authHandler.Validate)
+			protected.PUT("/locale", denyImpersonation, authHandler.UpdateLocale)
+
+			// API key milik pengguna; hanya dapat dikelola dengan sesi login
+			if deps.APIKeyService != nil {
+				apiKeyHandler := handler.NewAPIKeyHandler(deps.APIKeyService)
+				protected.POST("/api-keys", denyImpersonation, apiKeyHandler.Create)
+				protected.GET("/api-keys", apiKeyHandler.List)
+				protected.DELETE("/api-keys/:id", denyImpersonation, apiKeyHandler.Delete)
+			}
+
+			var impersonationHandler *handler.ImpersonationHandler
+			if deps.ImpersonationService != nil {
+				impersonationHandler = handler.NewImpersonationHandler(deps.ImpersonationService, cfg.Cookies, cfg.Impersonation.TokenTTL, protected.BasePath()+"/impersonation/stop")
+				protected.POST("/impersonation/stop", impersonationHandler.Stop)
+			}
+
+			// Admin routes (memerlukan role admin; API key juga memerlukan scope admin)
+			admin := protected.Group("/admin")
+			admin.Use(denyImpersonation, middleware.DenyAudienceRestricted(), adminRoleMiddleware.Middleware(), middleware.RequireScope(model.ScopeAdmin))
+			{
+				admin.PATCH("/users/:id/role", adminHandler.UpdateUserRole)
+				if impersonationHandler != nil {
+					admin.POST("/users/:id/impersonate", humanAdminMiddleware.Middleware(), impersonationHandler.Start)
+				}
+				admin.GET("/audit-events", adminHandler.ListAuditEvents)
+				admin.GET("/audit-events/export", adminHandler.ExportAuditEvents)
+
+				// Service account hanya dapat dikelola admin manusia, agar
+				// principal mesin tidak dapat membuat kredensial mesin lain
+				if deps.ServiceAccountService != nil {
+					serviceAccountHandler := handler.NewServiceAccountHandler(deps.ServiceAccountService, deps.APIKeyService)
+					serviceAccounts := admin.Group("/service-accounts")
+					serviceAccounts.Use(humanAdminMiddleware.Middleware())
+					serviceAccounts.POST("", serviceAccountHandler.Create)
+					serviceAccounts.GET("", serviceAccountHandler.List)
+					serviceAccounts.GET("/:id", serviceAccountHandler.Get)
+					serviceAccounts.PATCH("/:id", serviceAccountHandler.Update)
+					serviceAccounts.DELETE("/:id", serviceAccountHandler.Delete)
+					serviceAccounts.POST("/:id/secret", serviceAccountHandler.RotateSecret)
+					if deps.APIKeyService != nil {
+						serviceAccounts.POST("/:id/api-keys", serviceAccountHandler.CreateAPIKey)
+						serviceAccounts.GET("/:id/api-keys", serviceAccountHandler.ListAPIKeys)
+						serviceAccounts.DELETE("/:id/api-keys/:keyId", serviceAccountHandler.DeleteAPIKey)
+					}
+				}
+			}
 		}
 	}
 
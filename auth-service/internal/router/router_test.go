@@ -0,0 +1,1012 @@
+package router
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/health"
+	"auth-service/internal/repository"
+	"auth-service/internal/service"
+	"auth-service/internal/signing"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	os.Exit(m.Run())
+}
+
+// testServer router dengan repository di memori
+type testServer struct {
+	t       *testing.T
+	cfg     *config.Config
+	router  *gin.Engine
+	checker *health.Checker
+}
+
+// newTestServer membuat testServer; configure (opsional) mengubah konfigurasi
+// sebelum router dibuat
+func newTestServer(t *testing.T, configure ...func(*config.Config)) *testServer {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Env = "test"
+	cfg.JWT.Secret = "test-secret-that-is-long-enough-for-hs256"
+	for _, fn := range configure {
+		fn(cfg)
+	}
+
+	keys, err := signing.NewHMAC(cfg.JWT.Secret)
+	if err != nil {
+		t.Fatalf("signing keys: %v", err)
+	}
+
+	checker := health.NewChecker(time.Second)
+	return &testServer{
+		t:   t,
+		cfg: cfg,
+		router: New(cfg, Dependencies{
+			UserRepo:           repository.NewMemoryUserRepository(),
+			AuditRepo:          repository.NewMemoryAuditRepository(),
+			APIKeyRepo:         repository.NewMemoryAPIKeyRepository(),
+			ServiceAccountRepo: repository.NewMemoryServiceAccountRepository(),
+			TokenBlacklist:     service.NewTokenBlacklist(),
+			HealthChecker:      checker,
+			SigningKeys:        keys,
+		}),
+		checker: checker,
+	}
+}
+
+// do mengirim request JSON; token (jika ada) dikirim lewat cookie JWT
+func (s *testServer) do(method, path string, body interface{}, token string) *httptest.ResponseRecorder {
+	s.t.Helper()
+	return s.doWithHeaders(method, path, body, token, nil)
+}
+
+// doWithHeaders seperti do dengan header request tambahan
+func (s *testServer) doWithHeaders(method, path string, body interface{}, token string, headers map[string]string) *httptest.ResponseRecorder {
+	s.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			s.t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	if token != "" {
+		req.AddCookie(&http.Cookie{Name: s.cfg.Cookies.Name, Value: token})
+	}
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// tokenFrom mengambil token dari cookie JWT pada response
+func (s *testServer) tokenFrom(w *httptest.ResponseRecorder) string {
+	s.t.Helper()
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == s.cfg.Cookies.Name && cookie.Value != "" {
+			return cookie.Value
+		}
+	}
+	s.t.Fatalf("response has no %q cookie", s.cfg.Cookies.Name)
+	return ""
+}
+
+// register mendaftarkan pengguna dan mengembalikan token-nya
+func (s *testServer) register(name, email, password, role string) string {
+	s.t.Helper()
+	w := s.do(http.MethodPost, "/api/register", map[string]string{
+		"name": name, "email": email, "password": password, "role": role,
+	}, "")
+	expectStatus(s.t, w, http.StatusCreated)
+	return s.tokenFrom(w)
+}
+
+func expectStatus(t *testing.T, w *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if w.Code != want {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, want, w.Body.String())
+	}
+}
+
+// expectProblem memeriksa response application/problem+json dengan kode tertentu
+func expectProblem(t *testing.T, w *httptest.ResponseRecorder, code string) map[string]interface{} {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/problem+json") {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+	body := decode(t, w)
+	if body["code"] != code {
+		t.Errorf("code = %v, want %s", body["code"], code)
+	}
+	if int(body["status"].(float64)) != w.Code {
+		t.Errorf("status field = %v, want %d", body["status"], w.Code)
+	}
+	return body
+}
+
+// expectOAuthError memeriksa response error token endpoint (RFC 6749 5.2)
+func expectOAuthError(t *testing.T, w *httptest.ResponseRecorder, code string) {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	body := decode(t, w)
+	if body["error"] != code || body["error_description"] == "" || body["code"] != nil {
+		t.Errorf("OAuth error = %v, want error %s with description", body, code)
+	}
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body %q: %v", w.Body.String(), err)
+	}
+	return body
+}
+
+func TestRegister(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(http.MethodPost, "/api/register", map[string]string{
+		"name": "Alice", "email": "alice@example.com", "password": "secret123",
+	}, "")
+	expectStatus(t, w, http.StatusCreated)
+	s.tokenFrom(w)
+
+	user := decode(t, w)["user"].(map[string]interface{})
+	if user["email"] != "alice@example.com" || user["role"] != "user" {
+		t.Errorf("user = %v, want alice@example.com with default role", user)
+	}
+
+	t.Run("duplicate email", func(t *testing.T) {
+		w := s.do(http.MethodPost, "/api/register", map[string]string{
+			"name": "Alice", "email": "alice@example.com", "password": "secret123",
+		}, "")
+		expectStatus(t, w, http.StatusConflict)
+		expectProblem(t, w, "email_taken")
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		w := s.do(http.MethodPost, "/api/register", map[string]string{"email": "not-an-email"}, "")
+		expectStatus(t, w, http.StatusBadRequest)
+		if fieldErrors := expectProblem(t, w, "validation_failed")["errors"].([]interface{}); len(fieldErrors) != 3 {
+			t.Errorf("got %d field errors, want 3 (name, email, password)", len(fieldErrors))
+		}
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		w := s.do(http.MethodPost, "/api/register", map[string]string{
+			"name": "Mallory", "email": "mallory@example.com", "password": "secret123", "role": "superuser",
+		}, "")
+		expectStatus(t, w, http.StatusBadRequest)
+		expectProblem(t, w, "invalid_role")
+	})
+}
+
+func TestLogin(t *testing.T) {
+	s := newTestServer(t)
+	s.register("Bob", "bob@example.com", "secret123", "")
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		want     int
+	}{
+		{"valid credentials", "bob@example.com", "secret123", http.StatusOK},
+		{"wrong password", "bob@example.com", "wrong-password", http.StatusUnauthorized},
+		{"unknown email", "nobody@example.com", "secret123", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodPost, "/api/login", map[string]string{"email": tt.email, "password": tt.password}, "")
+			expectStatus(t, w, tt.want)
+			if tt.want == http.StatusOK {
+				s.tokenFrom(w)
+			} else {
+				expectProblem(t, w, "invalid_credentials")
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := newTestServer(t)
+	token := s.register("Carol", "carol@example.com", "secret123", "")
+
+	t.Run("cookie", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/api/validate", nil, token)
+		expectStatus(t, w, http.StatusOK)
+		body := decode(t, w)
+		if body["valid"] != true {
+			t.Errorf("valid = %v, want true", body["valid"])
+		}
+		if email := body["user"].(map[string]interface{})["email"]; email != "carol@example.com" {
+			t.Errorf("email = %v, want carol@example.com", email)
+		}
+	})
+
+	t.Run("bearer header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/validate", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		expectStatus(t, w, http.StatusOK)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/api/validate", nil, "")
+		expectStatus(t, w, http.StatusUnauthorized)
+		expectProblem(t, w, "authentication_required")
+	})
+
+	t.Run("tampered token", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/api/validate", nil, token+"x")
+		expectStatus(t, w, http.StatusUnauthorized)
+		expectProblem(t, w, "token_invalid")
+	})
+}
+
+func TestLogout(t *testing.T) {
+	s := newTestServer(t)
+	token := s.register("Dave", "dave@example.com", "secret123", "")
+
+	w := s.do(http.MethodPost, "/api/logout", nil, token)
+	expectStatus(t, w, http.StatusOK)
+
+	// Token yang sudah di-logout tidak boleh dipakai lagi
+	w = s.do(http.MethodGet, "/api/validate", nil, token)
+	expectStatus(t, w, http.StatusUnauthorized)
+	expectProblem(t, w, "token_revoked")
+}
+
+func TestAdminRoleChecks(t *testing.T) {
+	s := newTestServer(t)
+	userToken := s.register("Erin", "erin@example.com", "secret123", "")
+	adminToken := s.register("Frank", "frank@example.com", "secret123", "admin")
+
+	t.Run("user is forbidden", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/api/admin/audit-events", nil, userToken)
+		expectStatus(t, w, http.StatusForbidden)
+		expectProblem(t, w, "forbidden")
+	})
+
+	t.Run("anonymous is unauthorized", func(t *testing.T) {
+		expectStatus(t, s.do(http.MethodGet, "/api/admin/audit-events", nil, ""), http.StatusUnauthorized)
+	})
+
+	t.Run("admin lists audit events", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/api/admin/audit-events?type=register", nil, adminToken)
+		expectStatus(t, w, http.StatusOK)
+		if events := decode(t, w)["events"].([]interface{}); len(events) != 2 {
+			t.Errorf("got %d register events, want 2", len(events))
+		}
+	})
+
+	t.Run("admin exports audit events", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/api/admin/audit-events/export", nil, adminToken)
+		expectStatus(t, w, http.StatusOK)
+		if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Errorf("Content-Type = %q, want application/x-ndjson", ct)
+		}
+		if lines := strings.Count(w.Body.String(), "\n"); lines < 2 {
+			t.Errorf("export has %d lines, want at least 2", lines)
+		}
+	})
+
+	t.Run("admin promotes user", func(t *testing.T) {
+		validate := decode(t, s.do(http.MethodGet, "/api/validate", nil, userToken))
+		userID := int64(validate["user"].(map[string]interface{})["id"].(float64))
+
+		w := s.do(http.MethodPatch, "/api/admin/users/"+strconv.FormatInt(userID, 10)+"/role", map[string]string{"role": "admin"}, adminToken)
+		expectStatus(t, w, http.StatusOK)
+
+		// Role baru berlaku pada token berikutnya
+		w = s.do(http.MethodPost, "/api/login", map[string]string{"email": "erin@example.com", "password": "secret123"}, "")
+		expectStatus(t, w, http.StatusOK)
+		expectStatus(t, s.do(http.MethodGet, "/api/admin/audit-events", nil, s.tokenFrom(w)), http.StatusOK)
+	})
+}
+
+func TestClientCertAdminAudit(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.TLS.ServicePrincipals = []config.ServicePrincipalConfig{{Name: "billing", Role: "admin", CommonNames: []string{"billing.internal"}}}
+	})
+	userToken := s.register("Gita", "gita@example.com", "secret123", "")
+	adminToken := s.register("Hadi", "hadi@example.com", "secret123", "admin")
+	validate := decode(t, s.do(http.MethodGet, "/api/validate", nil, userToken))
+	userID := strconv.FormatInt(int64(validate["user"].(map[string]interface{})["id"].(float64)), 10)
+
+	// Sertifikat klien yang sudah diverifikasi server TLS
+	req := httptest.NewRequest(http.MethodPatch, "/api/admin/users/"+userID+"/role", strings.NewReader(`{"role":"admin"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{
+		Subject: pkix.Name{CommonName: "billing.internal", Organization: []string{"Example"}},
+	}}}}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	expectStatus(t, w, http.StatusOK)
+
+	w = s.do(http.MethodGet, "/api/admin/audit-events?type=role_change", nil, adminToken)
+	expectStatus(t, w, http.StatusOK)
+	events := decode(t, w)["events"].([]interface{})
+	if len(events) != 1 {
+		t.Fatalf("got %d role_change events, want 1", len(events))
+	}
+	event := events[0].(map[string]interface{})
+	if event["actor_id"] != nil || event["actor_type"] != "service" || event["actor_subject"] != "CN=billing.internal,O=Example" {
+		t.Errorf("actor = %v/%v/%v, want no ID, service, certificate subject", event["actor_id"], event["actor_type"], event["actor_subject"])
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.Security.Permissions = map[string][]string{"audit:read": {"admin"}}
+	})
+	userToken := s.register("Ivy", "ivy@example.com", "secret123", "")
+	adminToken := s.register("Jack", "jack@example.com", "secret123", "admin")
+
+	// createKey membuat API key dan mengembalikan key lengkap beserta ID-nya
+	createKey := func(t *testing.T, token string, body map[string]interface{}) (string, string) {
+		t.Helper()
+		w := s.do(http.MethodPost, "/api/api-keys", body, token)
+		expectStatus(t, w, http.StatusCreated)
+		apiKey := decode(t, w)["api_key"].(map[string]interface{})
+		key := apiKey["key"].(string)
+		if !strings.HasPrefix(key, apiKey["prefix"].(string)+"_") || !strings.HasPrefix(key, "ask_") {
+			t.Errorf("key %q does not start with prefix %v", key, apiKey["prefix"])
+		}
+		return key, strconv.FormatInt(int64(apiKey["id"].(float64)), 10)
+	}
+	withKey := func(key string) map[string]string {
+		return map[string]string{"Authorization": "ApiKey " + key}
+	}
+
+	userKey, userKeyID := createKey(t, userToken, map[string]interface{}{"name": "ci"})
+	adminKey, _ := createKey(t, adminToken, map[string]interface{}{"name": "reports", "scopes": []string{"audit:read"}})
+	scopedAdminKey, _ := createKey(t, adminToken, map[string]interface{}{"name": "ops", "scopes": []string{"admin"}})
+
+	t.Run("key authenticates as owner", func(t *testing.T) {
+		w := s.doWithHeaders(http.MethodGet, "/api/validate", nil, "", withKey(userKey))
+		expectStatus(t, w, http.StatusOK)
+		body := decode(t, w)
+		if body["authMethod"] != "api_key" || body["user"].(map[string]interface{})["email"] != "ivy@example.com" {
+			t.Errorf("validate = %v", body)
+		}
+	})
+
+	t.Run("list hides secret and records last use", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/api/api-keys", nil, userToken)
+		expectStatus(t, w, http.StatusOK)
+		keys := decode(t, w)["api_keys"].([]interface{})
+		if len(keys) != 1 {
+			t.Fatalf("got %d keys, want 1", len(keys))
+		}
+		key := keys[0].(map[string]interface{})
+		if _, ok := key["key"]; ok || key["last_used_at"] == nil {
+			t.Errorf("listed key = %v", key)
+		}
+	})
+
+	t.Run("scopes restrict admin access", func(t *testing.T) {
+		w := s.doWithHeaders(http.MethodGet, "/api/admin/audit-events", nil, "", withKey(adminKey))
+		expectStatus(t, w, http.StatusForbidden)
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/api/admin/audit-events", nil, "", withKey(scopedAdminKey)), http.StatusOK)
+
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/auth/forward?permission=audit:read", nil, "", withKey(adminKey)), http.StatusOK)
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/auth/forward?permission=audit:read", nil, "", withKey(scopedAdminKey)), http.StatusForbidden)
+
+		// Role admin lewat forward auth juga membutuhkan scope admin
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/auth/forward?role=admin", nil, "", withKey(adminKey)), http.StatusForbidden)
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/auth/forward?role=admin,user", nil, "", withKey(adminKey)), http.StatusForbidden)
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/auth/forward?role=admin", nil, "", withKey(scopedAdminKey)), http.StatusOK)
+	})
+
+	t.Run("scope must be held by owner", func(t *testing.T) {
+		w := s.do(http.MethodPost, "/api/api-keys", map[string]interface{}{"name": "x", "scopes": []string{"admin"}}, userToken)
+		expectStatus(t, w, http.StatusBadRequest)
+		expectProblem(t, w, "invalid_scope")
+	})
+
+	t.Run("key cannot manage keys or issue tokens", func(t *testing.T) {
+		w := s.doWithHeaders(http.MethodPost, "/api/api-keys", map[string]interface{}{"name": "x"}, "", withKey(userKey))
+		expectStatus(t, w, http.StatusForbidden)
+		expectStatus(t, s.doWithHeaders(http.MethodPut, "/api/locale", map[string]string{"locale": "id"}, "", withKey(userKey)), http.StatusForbidden)
+	})
+
+	t.Run("invalid keys are rejected", func(t *testing.T) {
+		for _, key := range []string{"ask_0000000000000000_secret", userKey + "x", "garbage"} {
+			w := s.doWithHeaders(http.MethodGet, "/api/validate", nil, "", withKey(key))
+			expectStatus(t, w, http.StatusUnauthorized)
+			expectProblem(t, w, "token_invalid")
+		}
+	})
+
+	t.Run("deleted key stops working", func(t *testing.T) {
+		expectProblem(t, s.do(http.MethodDelete, "/api/api-keys/"+userKeyID, nil, adminToken), "api_key_not_found")
+		expectStatus(t, s.do(http.MethodDelete, "/api/api-keys/"+userKeyID, nil, userToken), http.StatusOK)
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/api/validate", nil, "", withKey(userKey)), http.StatusUnauthorized)
+	})
+}
+
+func TestServiceAccounts(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.Security.Permissions = map[string][]string{"orders:read": {"user", "admin"}}
+	})
+	userToken := s.register("Kim", "kim@example.com", "secret123", "")
+	adminToken := s.register("Lee", "lee@example.com", "secret123", "admin")
+
+	w := s.do(http.MethodPost, "/api/admin/service-accounts", map[string]string{"name": "billing", "role": "user"}, adminToken)
+	expectStatus(t, w, http.StatusCreated)
+	account := decode(t, w)["service_account"].(map[string]interface{})
+	accountID := strconv.FormatInt(int64(account["id"].(float64)), 10)
+	clientID, clientSecret := account["client_id"].(string), account["client_secret"].(string)
+
+	// token meminta access token client_credentials dengan HTTP Basic
+	token := func(id, secret, scope string) *httptest.ResponseRecorder {
+		form := url.Values{"grant_type": {"client_credentials"}}
+		if scope != "" {
+			form.Set("scope", scope)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/api/oauth/token", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(id, secret)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		return w
+	}
+	bearer := func(token string) map[string]string {
+		return map[string]string{"Authorization": "Bearer " + token}
+	}
+
+	t.Run("only human admins manage service accounts", func(t *testing.T) {
+		expectStatus(t, s.do(http.MethodGet, "/api/admin/service-accounts", nil, userToken), http.StatusForbidden)
+		expectStatus(t, s.do(http.MethodGet, "/api/admin/service-accounts", nil, adminToken), http.StatusOK)
+		w := s.do(http.MethodPost, "/api/admin/service-accounts", map[string]string{"name": "billing", "role": "user"}, adminToken)
+		expectStatus(t, w, http.StatusConflict)
+		expectProblem(t, w, "service_account_name_taken")
+	})
+
+	t.Run("client credentials issue a service token", func(t *testing.T) {
+		w := token(clientID, clientSecret, "orders:read")
+		expectStatus(t, w, http.StatusOK)
+		if w.Header().Get("Cache-Control") != "no-store" {
+			t.Errorf("Cache-Control = %q, want no-store", w.Header().Get("Cache-Control"))
+		}
+		issued := decode(t, w)
+		if issued["token_type"] != "Bearer" || issued["scope"] != "orders:read" {
+			t.Errorf("token response = %v", issued)
+		}
+
+		w = s.doWithHeaders(http.MethodGet, "/api/validate", nil, "", bearer(issued["access_token"].(string)))
+		expectStatus(t, w, http.StatusOK)
+		body := decode(t, w)
+		if body["principalType"] != "service" || body["principal"] != "billing" || body["user"] != nil {
+			t.Errorf("validate = %v", body)
+		}
+
+		w = s.doWithHeaders(http.MethodGet, "/auth/forward?permission=orders:read", nil, "", bearer(issued["access_token"].(string)))
+		expectStatus(t, w, http.StatusOK)
+		if w.Header().Get("X-Principal-Type") != "service" {
+			t.Errorf("X-Principal-Type = %q, want service", w.Header().Get("X-Principal-Type"))
+		}
+	})
+
+	t.Run("token without scope gets the account scopes", func(t *testing.T) {
+		w := token(clientID, clientSecret, "")
+		expectStatus(t, w, http.StatusOK)
+		if issued := decode(t, w); issued["scope"] != "orders:read" {
+			t.Errorf("scope = %v, want orders:read", issued["scope"])
+		}
+	})
+
+	t.Run("service principals cannot use user endpoints", func(t *testing.T) {
+		issued := decode(t, token(clientID, clientSecret, ""))
+		expectStatus(t, s.doWithHeaders(http.MethodPost, "/api/api-keys", map[string]string{"name": "x"}, "", bearer(issued["access_token"].(string))), http.StatusForbidden)
+	})
+
+	t.Run("invalid client and grant", func(t *testing.T) {
+		w := token(clientID, "wrong", "")
+		expectStatus(t, w, http.StatusUnauthorized)
+		expectOAuthError(t, w, "invalid_client")
+		expectOAuthError(t, token(clientID, clientSecret, "admin"), "invalid_scope")
+
+		for body, code := range map[string]string{"grant_type=password": "unsupported_grant_type", "scope=x": "invalid_request"} {
+			req := httptest.NewRequest(http.MethodPost, "/api/oauth/token", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w = httptest.NewRecorder()
+			s.router.ServeHTTP(w, req)
+			expectStatus(t, w, http.StatusBadRequest)
+			expectOAuthError(t, w, code)
+		}
+	})
+
+	t.Run("service account API key", func(t *testing.T) {
+		w := s.do(http.MethodPost, "/api/admin/service-accounts/"+accountID+"/api-keys", map[string]interface{}{"name": "deploy"}, adminToken)
+		expectStatus(t, w, http.StatusCreated)
+		key := decode(t, w)["api_key"].(map[string]interface{})["key"].(string)
+
+		w = s.doWithHeaders(http.MethodGet, "/api/validate", nil, "", map[string]string{"Authorization": "ApiKey " + key})
+		expectStatus(t, w, http.StatusOK)
+		if body := decode(t, w); body["principalType"] != "service" || body["authMethod"] != "api_key" {
+			t.Errorf("validate = %v", body)
+		}
+
+		// Menonaktifkan service account menolak key maupun client credentials
+		expectStatus(t, s.do(http.MethodPatch, "/api/admin/service-accounts/"+accountID, map[string]bool{"disabled": true}, adminToken), http.StatusOK)
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/api/validate", nil, "", map[string]string{"Authorization": "ApiKey " + key}), http.StatusUnauthorized)
+		expectOAuthError(t, token(clientID, clientSecret, ""), "invalid_client")
+	})
+
+	t.Run("audit distinguishes service principals", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/api/admin/audit-events?actor_type=service&type=service_token", nil, adminToken)
+		expectStatus(t, w, http.StatusOK)
+		events := decode(t, w)["events"].([]interface{})
+		if len(events) == 0 {
+			t.Fatal("no service_token events with actor_type=service")
+		}
+		for _, e := range events {
+			if event := e.(map[string]interface{}); event["actor_type"] != "service" || event["subject_type"] != "service" {
+				t.Errorf("event = %v", event)
+			}
+		}
+	})
+
+	t.Run("user_id filter ignores service accounts with the same ID", func(t *testing.T) {
+		// Service account pertama dan pengguna pertama sama-sama ber-ID 1
+		w := s.do(http.MethodGet, "/api/admin/audit-events?user_id="+accountID, nil, adminToken)
+		expectStatus(t, w, http.StatusOK)
+		events := decode(t, w)["events"].([]interface{})
+		if len(events) == 0 {
+			t.Fatal("no events for the user with ID " + accountID)
+		}
+		for _, e := range events {
+			event := e.(map[string]interface{})
+			actorIsUser := event["actor_type"] == "user" && strconv.FormatFloat(event["actor_id"].(float64), 'f', -1, 64) == accountID
+			subjectIsUser := event["subject_type"] == "user" && event["subject_id"] != nil && strconv.FormatFloat(event["subject_id"].(float64), 'f', -1, 64) == accountID
+			if !actorIsUser && !subjectIsUser {
+				t.Errorf("event for service account leaked into user history: %v", event)
+			}
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		expectStatus(t, s.do(http.MethodDelete, "/api/admin/service-accounts/"+accountID, nil, adminToken), http.StatusOK)
+		expectProblem(t, s.do(http.MethodGet, "/api/admin/service-accounts/"+accountID, nil, adminToken), "service_account_not_found")
+	})
+}
+
+func TestImpersonation(t *testing.T) {
+	s := newTestServer(t)
+	userToken := s.register("Mia", "mia@example.com", "secret123", "")
+	adminToken := s.register("Ned", "ned@example.com", "secret123", "admin")
+	otherAdminToken := s.register("Oli", "oli@example.com", "secret123", "admin")
+
+	userID := func(token string) string {
+		w := s.do(http.MethodGet, "/api/validate", nil, token)
+		expectStatus(t, w, http.StatusOK)
+		return strconv.FormatInt(int64(decode(t, w)["user"].(map[string]interface{})["id"].(float64)), 10)
+	}
+	targetID, adminID := userID(userToken), userID(adminToken)
+
+	t.Run("only non-admin users can be impersonated", func(t *testing.T) {
+		expectStatus(t, s.do(http.MethodPost, "/api/admin/users/"+targetID+"/impersonate", nil, userToken), http.StatusForbidden)
+		expectProblem(t, s.do(http.MethodPost, "/api/admin/users/"+adminID+"/impersonate", nil, adminToken), "impersonation_not_allowed")
+		expectProblem(t, s.do(http.MethodPost, "/api/admin/users/"+adminID+"/impersonate", nil, otherAdminToken), "impersonation_not_allowed")
+		expectProblem(t, s.do(http.MethodPost, "/api/admin/users/999/impersonate", nil, adminToken), "user_not_found")
+	})
+
+	w := s.do(http.MethodPost, "/api/admin/users/"+targetID+"/impersonate", nil, adminToken)
+	expectStatus(t, w, http.StatusOK)
+	impersonationToken := s.tokenFrom(w)
+	if body := decode(t, w); body["user"].(map[string]interface{})["email"] != "mia@example.com" {
+		t.Errorf("impersonate response = %v", body)
+	}
+
+	t.Run("validate marks impersonated session", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/api/validate", nil, impersonationToken)
+		expectStatus(t, w, http.StatusOK)
+		body := decode(t, w)
+		actor, _ := body["actor"].(map[string]interface{})
+		if body["impersonated"] != true || actor["email"] != "ned@example.com" || body["user"].(map[string]interface{})["email"] != "mia@example.com" {
+			t.Errorf("validate = %v", body)
+		}
+		if body := decode(t, s.do(http.MethodGet, "/api/validate", nil, userToken)); body["impersonated"] != nil {
+			t.Errorf("regular token marked as impersonated: %v", body)
+		}
+	})
+
+	t.Run("forward auth exposes the impersonator", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/auth/forward", nil, impersonationToken)
+		expectStatus(t, w, http.StatusOK)
+		if got := w.Header().Get("X-Impersonator-Id"); got != adminID {
+			t.Errorf("X-Impersonator-Id = %q, want %q", got, adminID)
+		}
+	})
+
+	t.Run("sensitive actions are denied", func(t *testing.T) {
+		expectProblem(t, s.do(http.MethodPut, "/api/locale", map[string]string{"locale": "id"}, impersonationToken), "impersonation_restricted")
+		expectProblem(t, s.do(http.MethodPost, "/api/api-keys", map[string]string{"name": "x"}, impersonationToken), "impersonation_restricted")
+		expectProblem(t, s.do(http.MethodGet, "/api/admin/audit-events", nil, impersonationToken), "impersonation_restricted")
+		expectStatus(t, s.do(http.MethodGet, "/api/api-keys", nil, impersonationToken), http.StatusOK)
+	})
+
+	t.Run("bearer admin gets the token in the body", func(t *testing.T) {
+		w := s.doWithHeaders(http.MethodPost, "/api/admin/users/"+targetID+"/impersonate", nil, "", map[string]string{"Authorization": "Bearer " + adminToken})
+		expectStatus(t, w, http.StatusOK)
+		if cookies := w.Result().Cookies(); len(cookies) != 0 {
+			t.Errorf("cookies = %v, want none for bearer clients", cookies)
+		}
+		token, _ := decode(t, w)["token"].(string)
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/api/validate", nil, "", map[string]string{"Authorization": "Bearer " + token}), http.StatusOK)
+		expectStatus(t, s.doWithHeaders(http.MethodPost, "/api/impersonation/stop", nil, "", map[string]string{"Authorization": "Bearer " + token}), http.StatusOK)
+	})
+
+	t.Run("stop restores the admin cookie session", func(t *testing.T) {
+		w := s.do(http.MethodPost, "/api/admin/users/"+targetID+"/impersonate", nil, adminToken)
+		expectStatus(t, w, http.StatusOK)
+		var saved string
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == s.cfg.Cookies.Name+"_impersonator" {
+				saved = cookie.Value
+				if cookie.Path != "/api/impersonation/stop" || cookie.MaxAge != int(s.cfg.Impersonation.TokenTTL.Seconds()) {
+					t.Errorf("impersonator cookie path = %q, max-age = %d, want stop endpoint and impersonation TTL", cookie.Path, cookie.MaxAge)
+				}
+			}
+		}
+		if saved != adminToken {
+			t.Fatalf("impersonator cookie = %q, want admin token", saved)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/api/impersonation/stop", nil)
+		req.AddCookie(&http.Cookie{Name: s.cfg.Cookies.Name, Value: s.tokenFrom(w)})
+		req.AddCookie(&http.Cookie{Name: s.cfg.Cookies.Name + "_impersonator", Value: saved})
+		w = httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		expectStatus(t, w, http.StatusOK)
+		if restored := s.tokenFrom(w); restored != adminToken {
+			t.Errorf("session cookie after stop = %q, want admin token", restored)
+		}
+		expectStatus(t, s.do(http.MethodGet, "/api/admin/audit-events", nil, adminToken), http.StatusOK)
+	})
+
+	t.Run("stop revokes the token", func(t *testing.T) {
+		expectProblem(t, s.do(http.MethodPost, "/api/impersonation/stop", nil, userToken), "not_impersonating")
+
+		w := s.do(http.MethodPost, "/api/impersonation/stop", nil, impersonationToken)
+		expectStatus(t, w, http.StatusOK)
+		if cookie := w.Result().Cookies(); len(cookie) != 1 || cookie[0].MaxAge >= 0 {
+			t.Errorf("cookies = %v, want cleared cookie", cookie)
+		}
+		expectProblem(t, s.do(http.MethodGet, "/api/validate", nil, impersonationToken), "token_revoked")
+	})
+
+	t.Run("start and stop are audited", func(t *testing.T) {
+		for _, eventType := range []string{"impersonation_start", "impersonation_stop"} {
+			w := s.do(http.MethodGet, "/api/admin/audit-events?type="+eventType+"&user_id="+targetID, nil, adminToken)
+			expectStatus(t, w, http.StatusOK)
+			events := decode(t, w)["events"].([]interface{})
+			if len(events) != 3 {
+				t.Fatalf("%s events = %v, want 3", eventType, events)
+			}
+			for _, e := range events {
+				event := e.(map[string]interface{})
+				if strconv.FormatFloat(event["actor_id"].(float64), 'f', -1, 64) != adminID || event["outcome"] != "success" {
+					t.Errorf("%s event = %v", eventType, event)
+				}
+			}
+		}
+	})
+}
+
+func TestTokenExchange(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.Security.Permissions = map[string][]string{
+			"orders:read":  {"user", "admin"},
+			"orders:write": {"user", "admin"},
+		}
+		cfg.TokenExchange.Policies = []config.TokenExchangePolicy{{Client: "gateway", Audiences: []string{"orders-api", "inventory-api"}}}
+	})
+	userToken := s.register("Pat", "pat@example.com", "secret123", "")
+	adminToken := s.register("Quinn", "quinn@example.com", "secret123", "admin")
+
+	client := func(name string) (string, string) {
+		w := s.do(http.MethodPost, "/api/admin/service-accounts", map[string]string{"name": name, "role": "user"}, adminToken)
+		expectStatus(t, w, http.StatusCreated)
+		account := decode(t, w)["service_account"].(map[string]interface{})
+		return account["client_id"].(string), account["client_secret"].(string)
+	}
+	gatewayID, gatewaySecret := client("gateway")
+	otherID, otherSecret := client("reports")
+
+	// exchange menukar subject token lewat token endpoint dengan HTTP Basic
+	exchange := func(id, secret string, form url.Values) *httptest.ResponseRecorder {
+		form.Set("grant_type", "urn:ietf:params:oauth:grant-type:token-exchange")
+		if form.Get("subject_token_type") == "" {
+			form.Set("subject_token_type", "urn:ietf:params:oauth:token-type:access_token")
+		}
+		req := httptest.NewRequest(http.MethodPost, "/api/oauth/token", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(id, secret)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		return w
+	}
+	bearer := func(token string) map[string]string {
+		return map[string]string{"Authorization": "Bearer " + token}
+	}
+
+	w := exchange(gatewayID, gatewaySecret, url.Values{"subject_token": {userToken}, "audience": {"orders-api"}, "scope": {"orders:read"}})
+	expectStatus(t, w, http.StatusOK)
+	issued := decode(t, w)
+	if issued["issued_token_type"] != "urn:ietf:params:oauth:token-type:access_token" || issued["scope"] != "orders:read" {
+		t.Errorf("token response = %v", issued)
+	}
+	exchanged := issued["access_token"].(string)
+
+	t.Run("exchanged token is narrowed", func(t *testing.T) {
+		w := s.doWithHeaders(http.MethodGet, "/api/validate", nil, "", bearer(exchanged))
+		expectStatus(t, w, http.StatusOK)
+		body := decode(t, w)
+		if aud, _ := body["audience"].([]interface{}); len(aud) != 1 || aud[0] != "orders-api" || body["user"].(map[string]interface{})["email"] != "pat@example.com" {
+			t.Errorf("validate = %v", body)
+		}
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/auth/forward?audience=orders-api&permission=orders:read", nil, "", bearer(exchanged)), http.StatusOK)
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/auth/forward?audience=orders-api&permission=orders:write", nil, "", bearer(exchanged)), http.StatusForbidden)
+
+		// Token hasil exchange tidak dapat diperluas lagi
+		expectOAuthError(t, exchange(gatewayID, gatewaySecret, url.Values{"subject_token": {exchanged}, "audience": {"orders-api"}, "scope": {"orders:write"}}), "invalid_scope")
+	})
+
+	t.Run("forward auth enforces audience", func(t *testing.T) {
+		withAudience := func(audience string) map[string]string {
+			return map[string]string{"Authorization": "Bearer " + exchanged, "X-Auth-Required-Audience": audience}
+		}
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/auth/forward", nil, "", bearer(exchanged)), http.StatusForbidden)
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/auth/forward?audience=billing-api", nil, "", bearer(exchanged)), http.StatusForbidden)
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/auth/forward", nil, "", withAudience("orders-api")), http.StatusOK)
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/auth/forward?audience=orders-api", nil, "", withAudience("billing-api")), http.StatusForbidden)
+		// Token tanpa aud tetap berlaku untuk semua audience
+		expectStatus(t, s.doWithHeaders(http.MethodGet, "/auth/forward?audience=orders-api", nil, "", bearer(userToken)), http.StatusOK)
+	})
+
+	t.Run("exchanged token cannot use auth-service endpoints", func(t *testing.T) {
+		expectStatus(t, s.doWithHeaders(http.MethodPost, "/api/api-keys", map[string]string{"name": "x"}, "", bearer(exchanged)), http.StatusForbidden)
+		w := s.do(http.MethodPost, "/api/register", map[string]string{"name": "Rae", "email": "rae@example.com", "password": "secret123", "role": "admin"}, "")
+		expectStatus(t, w, http.StatusCreated)
+		adminExchanged := decode(t, exchange(gatewayID, gatewaySecret, url.Values{"subject_token": {s.tokenFrom(w)}, "audience": {"orders-api"}}))["access_token"].(string)
+		expectProblem(t, s.doWithHeaders(http.MethodGet, "/api/admin/audit-events", nil, "", bearer(adminExchanged)), "forbidden")
+	})
+
+	t.Run("actor token is recorded as act", func(t *testing.T) {
+		actorToken := decode(t, exchange(gatewayID, gatewaySecret, url.Values{"subject_token": {adminToken}, "audience": {"orders-api"}}))["access_token"].(string)
+		w := exchange(gatewayID, gatewaySecret, url.Values{
+			"subject_token":    {userToken},
+			"actor_token":      {actorToken},
+			"actor_token_type": {"urn:ietf:params:oauth:token-type:jwt"},
+			"audience":         {"orders-api"},
+		})
+		expectStatus(t, w, http.StatusOK)
+		delegated := decode(t, w)["access_token"].(string)
+		w = s.doWithHeaders(http.MethodGet, "/api/validate", nil, "", bearer(delegated))
+		expectStatus(t, w, http.StatusOK)
+		body := decode(t, w)
+		if actor, _ := body["actor"].(map[string]interface{}); body["delegated"] != true || body["impersonated"] != nil || actor["email"] != "quinn@example.com" {
+			t.Errorf("validate = %v", body)
+		}
+
+		// Delegasi bukan impersonation: tanpa X-Impersonator-Id, tidak dapat
+		// dipakai mengakhiri impersonation, dan logout dicatat sebagai logout
+		w = s.doWithHeaders(http.MethodGet, "/auth/forward?audience=orders-api", nil, "", bearer(delegated))
+		expectStatus(t, w, http.StatusOK)
+		if got := w.Header().Get("X-Impersonator-Id"); got != "" {
+			t.Errorf("X-Impersonator-Id = %q, want none for delegation", got)
+		}
+		expectProblem(t, s.doWithHeaders(http.MethodPost, "/api/impersonation/stop", nil, "", bearer(delegated)), "not_impersonating")
+		expectStatus(t, s.do(http.MethodPost, "/api/logout", nil, delegated), http.StatusOK)
+		if events := decode(t, s.do(http.MethodGet, "/api/admin/audit-events?type=impersonation_stop", nil, adminToken))["events"].([]interface{}); len(events) != 0 {
+			t.Errorf("impersonation_stop events = %v, want none", events)
+		}
+	})
+
+	t.Run("policy and token errors", func(t *testing.T) {
+		expectOAuthError(t, exchange(gatewayID, gatewaySecret, url.Values{"subject_token": {userToken}, "audience": {"billing-api"}}), "invalid_target")
+		expectOAuthError(t, exchange(otherID, otherSecret, url.Values{"subject_token": {userToken}, "audience": {"orders-api"}}), "unauthorized_client")
+		expectOAuthError(t, exchange(gatewayID, gatewaySecret, url.Values{"subject_token": {"not-a-token"}, "audience": {"orders-api"}}), "invalid_grant")
+		expectOAuthError(t, exchange(gatewayID, "wrong", url.Values{"subject_token": {userToken}, "audience": {"orders-api"}}), "invalid_client")
+		expectOAuthError(t, exchange(gatewayID, gatewaySecret, url.Values{"subject_token": {userToken}}), "invalid_request")
+		expectOAuthError(t, exchange(gatewayID, gatewaySecret, url.Values{"subject_token": {userToken}, "audience": {"orders-api"}, "subject_token_type": {"urn:ietf:params:oauth:token-type:saml2"}}), "invalid_request")
+		// aud subject token dan actor token tidak dapat diperluas ke audience lain
+		expectOAuthError(t, exchange(gatewayID, gatewaySecret, url.Values{"subject_token": {exchanged}, "audience": {"inventory-api"}}), "invalid_target")
+		expectOAuthError(t, exchange(gatewayID, gatewaySecret, url.Values{"subject_token": {userToken}, "actor_token": {exchanged}, "actor_token_type": {"urn:ietf:params:oauth:token-type:access_token"}, "audience": {"inventory-api"}}), "invalid_grant")
+	})
+
+	t.Run("exchanges are audited", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/api/admin/audit-events?type=token_exchange&actor_type=service", nil, adminToken)
+		expectStatus(t, w, http.StatusOK)
+		var succeeded, failed int
+		for _, e := range decode(t, w)["events"].([]interface{}) {
+			event := e.(map[string]interface{})
+			switch event["outcome"] {
+			case "success":
+				succeeded++
+				if event["subject_type"] != "user" || !strings.Contains(event["reason"].(string), "aud=orders-api") {
+					t.Errorf("successful event = %v", event)
+				}
+			case "failure":
+				failed++
+			default:
+				t.Errorf("event outcome = %v", event["outcome"])
+			}
+		}
+		if succeeded == 0 || failed == 0 {
+			t.Errorf("token_exchange events: %d successful, %d failed; want both", succeeded, failed)
+		}
+	})
+}
+
+func TestForwardAuth(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.ForwardAuth.LoginURL = "https://login.example.com/signin"
+		cfg.Security.Permissions = map[string][]string{"audit:read": {"admin"}}
+	})
+	userToken := s.register("Gina", "gina@example.com", "secret123", "")
+	adminToken := s.register("Hank", "hank@example.com", "secret123", "admin")
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		token   string
+		headers map[string]string
+		want    int
+	}{
+		{"user allowed", http.MethodGet, "/auth/forward", userToken, nil, http.StatusOK},
+		{"head allowed", http.MethodHead, "/auth/forward", userToken, nil, http.StatusOK},
+		{"bearer token", http.MethodGet, "/auth/forward", "", map[string]string{"Authorization": "Bearer " + userToken}, http.StatusOK},
+		{"anonymous", http.MethodGet, "/auth/forward", "", nil, http.StatusUnauthorized},
+		{"invalid token", http.MethodGet, "/auth/forward", "not-a-jwt", nil, http.StatusUnauthorized},
+		{"role required by query", http.MethodGet, "/auth/forward?role=admin", userToken, nil, http.StatusForbidden},
+		{"role list by query", http.MethodGet, "/auth/forward?role=admin,user", userToken, nil, http.StatusOK},
+		{"role required by header", http.MethodGet, "/auth/forward", userToken, map[string]string{"X-Auth-Required-Role": "admin"}, http.StatusForbidden},
+		{"header cannot widen query", http.MethodGet, "/auth/forward?role=admin", userToken, map[string]string{"X-Auth-Required-Role": "user"}, http.StatusForbidden},
+		{"permission granted", http.MethodGet, "/auth/forward?permission=audit:read", adminToken, nil, http.StatusOK},
+		{"permission denied", http.MethodGet, "/auth/forward?permission=audit:read", userToken, nil, http.StatusForbidden},
+		{"unknown permission", http.MethodGet, "/auth/forward?permission=unknown", adminToken, nil, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.doWithHeaders(tt.method, tt.path, nil, tt.token, tt.headers)
+			expectStatus(t, w, tt.want)
+			if cc := w.Header().Get("Cache-Control"); cc != "no-store" {
+				t.Errorf("Cache-Control = %q, want no-store", cc)
+			}
+		})
+	}
+
+	t.Run("identity headers", func(t *testing.T) {
+		w := s.do(http.MethodGet, "/auth/forward", nil, adminToken)
+		expectStatus(t, w, http.StatusOK)
+		if w.Header().Get("X-User-Email") != "hank@example.com" || w.Header().Get("X-User-Role") != "admin" || w.Header().Get("X-User-Id") == "" {
+			t.Errorf("identity headers = %v", w.Header())
+		}
+	})
+
+	t.Run("redirect to login", func(t *testing.T) {
+		w := s.doWithHeaders(http.MethodGet, "/auth/forward?redirect=true", nil, "", map[string]string{
+			"X-Forwarded-Proto": "https",
+			"X-Forwarded-Host":  "app.example.com",
+			"X-Forwarded-Uri":   "/reports?page=2",
+		})
+		expectStatus(t, w, http.StatusFound)
+		want := "https://login.example.com/signin?rd=" + url.QueryEscape("https://app.example.com/reports?page=2")
+		if location := w.Header().Get("Location"); location != want {
+			t.Errorf("Location = %q, want %q", location, want)
+		}
+	})
+}
+
+func TestUnknownRoutes(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(http.MethodGet, "/api/does-not-exist", nil, "")
+	expectStatus(t, w, http.StatusNotFound)
+	expectProblem(t, w, "not_found")
+
+	w = s.do(http.MethodGet, "/api/login", nil, "")
+	expectStatus(t, w, http.StatusMethodNotAllowed)
+	expectProblem(t, w, "method_not_allowed")
+
+	// Preflight CORS tetap ditangani walaupun route tidak mendaftarkan OPTIONS
+	req := httptest.NewRequest(http.MethodOptions, "/api/login", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	expectStatus(t, w, http.StatusNoContent)
+}
+
+func TestLocalizedMessages(t *testing.T) {
+	s := newTestServer(t)
+	indonesian := map[string]string{"Accept-Language": "id-ID,id;q=0.9,en;q=0.8"}
+
+	t.Run("accept-language selects catalog", func(t *testing.T) {
+		w := s.doWithHeaders(http.MethodPost, "/api/register", map[string]string{"email": "not-an-email"}, "", indonesian)
+		expectStatus(t, w, http.StatusBadRequest)
+		if lang := w.Header().Get("Content-Language"); lang != "id" {
+			t.Errorf("Content-Language = %q, want id", lang)
+		}
+		body := expectProblem(t, w, "validation_failed")
+		if body["title"] != "Validasi gagal" {
+			t.Errorf("title = %v, want Indonesian title", body["title"])
+		}
+		fieldErr := body["errors"].([]interface{})[0].(map[string]interface{})
+		if fieldErr["field"] != "name" || fieldErr["message"] != "name wajib diisi" {
+			t.Errorf("field error = %v, want translated error for json field name", fieldErr)
+		}
+	})
+
+	t.Run("unsupported language falls back to default", func(t *testing.T) {
+		w := s.doWithHeaders(http.MethodGet, "/api/validate", nil, "", map[string]string{"Accept-Language": "fr"})
+		expectStatus(t, w, http.StatusUnauthorized)
+		if title := expectProblem(t, w, "authentication_required")["title"]; title != "Authentication required" {
+			t.Errorf("title = %v, want English title", title)
+		}
+	})
+
+	t.Run("user preference overrides accept-language", func(t *testing.T) {
+		token := s.register("Gita", "gita@example.com", "secret123", "")
+		w := s.doWithHeaders(http.MethodPut, "/api/locale", map[string]string{"locale": "id"}, token, map[string]string{"Accept-Language": "en"})
+		expectStatus(t, w, http.StatusOK)
+		if msg := decode(t, w)["message"]; msg != "Preferensi bahasa berhasil diubah" {
+			t.Errorf("message = %v, want Indonesian message", msg)
+		}
+
+		// Token baru membawa preferensi locale pengguna
+		w = s.doWithHeaders(http.MethodGet, "/api/admin/audit-events", nil, s.tokenFrom(w), map[string]string{"Accept-Language": "en"})
+		expectStatus(t, w, http.StatusForbidden)
+		if title := expectProblem(t, w, "forbidden")["title"]; title != "Akses ditolak" {
+			t.Errorf("title = %v, want Indonesian title", title)
+		}
+	})
+}
+
+func TestHealthProbes(t *testing.T) {
+	s := newTestServer(t)
+
+	expectStatus(t, s.do(http.MethodGet, "/livez", nil, ""), http.StatusOK)
+	expectStatus(t, s.do(http.MethodGet, "/readyz", nil, ""), http.StatusOK)
+
+	// Readiness gagal selama graceful shutdown, liveness tetap OK
+	s.checker.SetShuttingDown()
+	expectStatus(t, s.do(http.MethodGet, "/readyz", nil, ""), http.StatusServiceUnavailable)
+	expectStatus(t, s.do(http.MethodGet, "/livez", nil, ""), http.StatusOK)
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"auth-service/internal/config"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Server membungkus http.Server dengan timeout dan graceful shutdown
+type Server struct {
+    httpServer      *http.Server
+    drainPeriod     time.Duration
+    shutdownTimeout time.Duration
+}
+
+// New membuat instance baru Server dari konfigurasi. Jika tlsConfig tidak nil,
+// server melayani HTTPS dengan sertifikat dari tlsConfig.
+func New(cfg config.ServerConfig, handler http.Handler, tlsConfig *tls.Config) *Server {
+    return &Server{
+        httpServer: &http.Server{
+            Addr:              ":" + cfg.Port,
+            Handler:           handler,
+            TLSConfig:         tlsConfig,
+            ReadTimeout:       cfg.ReadTimeout,
+            ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+            WriteTimeout:      cfg.WriteTimeout,
+            IdleTimeout:       cfg.IdleTimeout,
+            MaxHeaderBytes:    cfg.MaxHeaderBytes,
+        },
+        drainPeriod:     cfg.DrainPeriod,
+        shutdownTimeout: cfg.ShutdownTimeout,
+    }
+}
+
+// Run menjalankan server sampai ctx dibatalkan (misalnya karena SIGTERM),
+// lalu menunggu drain period dan mematikan server secara graceful sehingga
+// request yang sedang berjalan tetap diselesaikan
+func (s *Server) Run(ctx context.Context) error {
+    serveErr := make(chan error, 1)
+    go func() {
+        if s.httpServer.TLSConfig != nil {
+            slog.Info("Server starting", "addr", s.httpServer.Addr, "tls", true)
+            // Sertifikat diambil dari TLSConfig.GetCertificate
+            serveErr <- s.httpServer.ListenAndServeTLS("", "")
+            return
+        }
+        slog.Info("Server starting", "addr", s.httpServer.Addr, "tls", false)
+        serveErr <- s.httpServer.ListenAndServe()
+    }()
+
+    select {
+    case err := <-serveErr:
+        return fmt.Errorf("server stopped unexpectedly: %v", err)
+    case <-ctx.Done():
+    }
+
+    if s.drainPeriod > 0 {
+        slog.Info("Shutdown signal received, draining", "addr", s.httpServer.Addr, "drain_period", s.drainPeriod)
+        time.Sleep(s.drainPeriod)
+    }
+
+    slog.Info("Shutting down server", "addr", s.httpServer.Addr)
+    shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+    defer cancel()
+
+    if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+        return fmt.Errorf("failed to shutdown server gracefully: %v", err)
+    }
+    if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+        return fmt.Errorf("server error: %v", err)
+    }
+
+    slog.Info("Server stopped", "addr", s.httpServer.Addr)
+    return nil
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/metrics"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// lastUsedInterval jeda minimum antar penulisan last_used_at agar request
+// beruntun dengan key yang sama tidak selalu menulis ke database
+const lastUsedInterval = time.Minute
+
+// APIKeyService interface untuk pengelolaan dan autentikasi API key milik
+// pengguna atau service account. actorID adalah pengguna yang melakukan
+// perubahan: pemilik key sendiri, atau admin untuk key service account.
+type APIKeyService interface {
+    Create(ctx context.Context, actorID int64, owner model.Principal, req *model.CreateAPIKeyRequest) (*model.CreatedAPIKeyResponse, error)
+    List(ctx context.Context, owner model.Principal) ([]model.APIKeyResponse, error)
+    Delete(ctx context.Context, actorID int64, owner model.Principal, id int64) error
+    Authenticate(ctx context.Context, key string) (*model.JWTClaims, error)
+}
+
+type apiKeyService struct {
+    repo               repository.APIKeyRepository
+    userRepo           repository.UserRepository
+    serviceAccountRepo repository.ServiceAccountRepository
+    cfg                config.APIKeysConfig
+    security           config.SecurityConfig
+    issuer             string
+    audit              *AuditLogger
+}
+
+// NewAPIKeyService membuat instance baru APIKeyService. serviceAccountRepo
+// boleh nil jika service account tidak diaktifkan.
+func NewAPIKeyService(
+    repo repository.APIKeyRepository,
+    userRepo repository.UserRepository,
+    serviceAccountRepo repository.ServiceAccountRepository,
+    cfg config.APIKeysConfig,
+    security config.SecurityConfig,
+    issuer string,
+    audit *AuditLogger,
+) APIKeyService {
+    return &apiKeyService{
+        repo:               repo,
+        userRepo:           userRepo,
+        serviceAccountRepo: serviceAccountRepo,
+        cfg:                cfg,
+        security:           security,
+        issuer:             issuer,
+        audit:              audit,
+    }
+}
+
+// Create membuat API key baru untuk owner. Key lengkap berformat
+// <prefix>_<key id>_<secret> dan hanya dikembalikan sekali; yang disimpan
+// hanya hash SHA-256-nya. Scope harus dimiliki pemilik key saat ini.
+func (s *apiKeyService) Create(ctx context.Context, actorID int64, owner model.Principal, req *model.CreateAPIKeyRequest) (*model.CreatedAPIKeyResponse, error) {
+    event := model.AuditEvent{
+        EventType:   model.AuditEventAPIKeyCreate,
+        ActorID:     int64Ptr(actorID),
+        SubjectID:   int64Ptr(owner.ID),
+        SubjectType: owner.Type,
+        Outcome:     model.AuditOutcomeFailure,
+    }
+
+    profile, err := s.ownerClaims(ctx, owner)
+    if err != nil {
+        return nil, err
+    }
+    event.SubjectEmail = profile.Email
+
+    scopes := slices.Compact(slices.Sorted(slices.Values(req.Scopes)))
+    for _, scope := range scopes {
+        if !roleHasScope(s.security, profile.Role, scope) {
+            event.Reason = "invalid_scope"
+            s.audit.Record(ctx, event)
+            return nil, fmt.Errorf("%w: %q", model.ErrInvalidScope, scope)
+        }
+    }
+
+    now := time.Now().UTC()
+    expiresAt, err := s.expiry(now, req.ExpiresAt)
+    if err != nil {
+        return nil, err
+    }
+
+    if s.cfg.MaxPerUser > 0 {
+        existing, err := s.repo.ListByOwner(ctx, owner)
+        if err != nil {
+            return nil, fmt.Errorf("failed to list api keys: %w", err)
+        }
+        if len(existing) >= s.cfg.MaxPerUser {
+            event.Reason = "limit_reached"
+            s.audit.Record(ctx, event)
+            return nil, fmt.Errorf("%w (maximum %d)", model.ErrAPIKeyLimitReached, s.cfg.MaxPerUser)
+        }
+    }
+
+    keyID, err := randomString(8, hex.EncodeToString)
+    if err != nil {
+        return nil, err
+    }
+    secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+    if err != nil {
+        return nil, err
+    }
+    rawKey := s.cfg.Prefix + "_" + keyID + "_" + secret
+
+    key := &model.APIKey{
+        Owner:     owner,
+        Name:      req.Name,
+        KeyID:     keyID,
+        KeyHash:   hashSecret(rawKey),
+        Scopes:    scopes,
+        ExpiresAt: expiresAt,
+        CreatedAt: now,
+    }
+    if err := s.repo.Create(ctx, key); err != nil {
+        event.Reason = "create_failed"
+        s.audit.Record(ctx, event)
+        return nil, fmt.Errorf("failed to create api key: %w", err)
+    }
+
+    event.Outcome = model.AuditOutcomeSuccess
+    event.Reason = "key " + strconv.FormatInt(key.ID, 10)
+    s.audit.Record(ctx, event)
+    slog.InfoContext(ctx, "API key created", "api_key_id", key.ID, "owner_type", owner.Type, "scopes", scopes)
+
+    return &model.CreatedAPIKeyResponse{APIKeyResponse: s.toResponse(key), Key: rawKey}, nil
+}
+
+// List mengambil API key milik owner tanpa secret
+func (s *apiKeyService) List(ctx context.Context, owner model.Principal) ([]model.APIKeyResponse, error) {
+    keys, err := s.repo.ListByOwner(ctx, owner)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list api keys: %w", err)
+    }
+
+    responses := make([]model.APIKeyResponse, 0, len(keys))
+    for i := range keys {
+        responses = append(responses, s.toResponse(&keys[i]))
+    }
+    return responses, nil
+}
+
+// Delete mencabut API key milik owner; key langsung tidak dapat dipakai
+func (s *apiKeyService) Delete(ctx context.Context, actorID int64, owner model.Principal, id int64) error {
+    event := model.AuditEvent{
+        EventType:   model.AuditEventAPIKeyDelete,
+        ActorID:     int64Ptr(actorID),
+        SubjectID:   int64Ptr(owner.ID),
+        SubjectType: owner.Type,
+        Outcome:     model.AuditOutcomeFailure,
+        Reason:      "key " + strconv.FormatInt(id, 10),
+    }
+
+    if err := s.repo.Delete(ctx, owner, id); err != nil {
+        s.audit.Record(ctx, event)
+        if errors.Is(err, model.ErrAPIKeyNotFound) {
+            return err
+        }
+        return fmt.Errorf("failed to delete api key: %w", err)
+    }
+
+    event.Outcome = model.AuditOutcomeSuccess
+    s.audit.Record(ctx, event)
+    slog.InfoContext(ctx, "API key deleted", "api_key_id", id, "owner_type", owner.Type)
+    return nil
+}
+
+// Authenticate memverifikasi API key dan mengembalikan claims yang setara
+// dengan pemilik key (role dan profil terkini) dibatasi scope key. Key milik
+// service account yang dinonaktifkan atau dihapus ditolak.
+func (s *apiKeyService) Authenticate(ctx context.Context, rawKey string) (*model.JWTClaims, error) {
+    // Format: <prefix>_<key id>_<secret>
+    parts := strings.SplitN(rawKey, "_", 3)
+    if len(parts) != 3 || parts[0] != s.cfg.Prefix || parts[1] == "" || parts[2] == "" {
+        metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenInvalid).Inc()
+        return nil, fmt.Errorf("%w: malformed api key", model.ErrTokenInvalid)
+    }
+
+    key, err := s.repo.FindByKeyID(ctx, parts[1])
+    if err != nil {
+        metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenInvalid).Inc()
+        if errors.Is(err, model.ErrAPIKeyNotFound) {
+            return nil, fmt.Errorf("%w: unknown api key", model.ErrTokenInvalid)
+        }
+        return nil, fmt.Errorf("failed to find api key: %w", err)
+    }
+    if subtle.ConstantTimeCompare([]byte(hashSecret(rawKey)), []byte(key.KeyHash)) != 1 {
+        metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenInvalid).Inc()
+        return nil, fmt.Errorf("%w: unknown api key", model.ErrTokenInvalid)
+    }
+
+    now := time.Now()
+    if key.ExpiresAt != nil && !now.Before(*key.ExpiresAt) {
+        metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenInvalid).Inc()
+        return nil, model.ErrTokenExpired
+    }
+
+    claims, err := s.ownerClaims(ctx, key.Owner)
+    if err != nil {
+        metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenInvalid).Inc()
+        if errors.Is(err, model.ErrUserNotFound) || errors.Is(err, model.ErrServiceAccountNotFound) || errors.Is(err, model.ErrInvalidClient) {
+            return nil, fmt.Errorf("%w: api key owner no longer exists or is disabled", model.ErrTokenInvalid)
+        }
+        return nil, err
+    }
+
+    if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedInterval {
+        // Kegagalan mencatat last_used_at tidak menggagalkan autentikasi
+        if err := s.repo.UpdateLastUsed(context.WithoutCancel(ctx), key.ID, now.UTC()); err != nil {
+            slog.WarnContext(ctx, "Failed to update API key last used", "api_key_id", key.ID, "error", err)
+        }
+    }
+
+    scopes := key.Scopes
+    if scopes == nil {
+        scopes = []string{}
+    }
+
+    metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenValid).Inc()
+    claims.Scopes = scopes
+    claims.APIKeyID = key.ID
+    claims.IssuedAt = jwt.NewNumericDate(key.CreatedAt)
+    claims.Issuer = s.issuer
+    if key.ExpiresAt != nil {
+        claims.ExpiresAt = jwt.NewNumericDate(*key.ExpiresAt)
+    }
+    return claims, nil
+}
+
+// ownerClaims membuat claims dari profil terkini pemilik key. Service account
+// yang dinonaktifkan menghasilkan ErrInvalidClient.
+func (s *apiKeyService) ownerClaims(ctx context.Context, owner model.Principal) (*model.JWTClaims, error) {
+    if owner.IsService() {
+        if s.serviceAccountRepo == nil {
+            return nil, model.ErrServiceAccountNotFound
+        }
+        account, err := s.serviceAccountRepo.FindByID(ctx, owner.ID)
+        if err != nil {
+            if errors.Is(err, model.ErrServiceAccountNotFound) {
+                return nil, err
+            }
+            return nil, fmt.Errorf("failed to get service account: %w", err)
+        }
+        if account.Disabled {
+            return nil, fmt.Errorf("%w: service account is disabled", model.ErrInvalidClient)
+        }
+        return serviceAccountClaims(account), nil
+    }
+
+    user, err := s.userRepo.FindByID(ctx, owner.ID)
+    if err != nil {
+        if errors.Is(err, model.ErrUserNotFound) {
+            return nil, err
+        }
+        return nil, fmt.Errorf("failed to get user: %w", err)
+    }
+    return &model.JWTClaims{
+        UserID:        user.ID,
+        Email:         user.Email,
+        Name:          user.Name,
+        Role:          user.Role,
+        Locale:        user.Locale,
+        PrincipalType: model.PrincipalTypeUser,
+        RegisteredClaims: jwt.RegisteredClaims{
+            Subject: strconv.FormatInt(user.ID, 10),
+        },
+    }, nil
+}
+
+// roleHasScope memeriksa apakah role boleh memberikan scope ke API key atau
+// token: scope admin untuk admin_roles, selain itu permission dari
+// security.permissions
+func roleHasScope(security config.SecurityConfig, role, scope string) bool {
+    if scope == model.ScopeAdmin {
+        return slices.Contains(security.AdminRoles, role)
+    }
+    return security.HasPermission(role, scope)
+}
+
+// expiry menentukan waktu kedaluwarsa key dari request dan api_keys.max_ttl
+func (s *apiKeyService) expiry(now time.Time, requested *time.Time) (*time.Time, error) {
+    if requested == nil {
+        if s.cfg.MaxTTL == 0 {
+            return nil, nil
+        }
+        expiresAt := now.Add(s.cfg.MaxTTL)
+        return &expiresAt, nil
+    }
+
+    if !requested.After(now) {
+        return nil, model.NewValidationError(errors.New("expires_at must be in the future"))
+    }
+    if s.cfg.MaxTTL > 0 && requested.Sub(now) > s.cfg.MaxTTL {
+        return nil, model.NewValidationError(fmt.Errorf("expires_at must be within %s", s.cfg.MaxTTL))
+    }
+    expiresAt := requested.UTC()
+    return &expiresAt, nil
+}
+
+// toResponse mengubah APIKey menjadi response tanpa hash
+func (s *apiKeyService) toResponse(key *model.APIKey) model.APIKeyResponse {
+    scopes := key.Scopes
+    if scopes == nil {
+        scopes = []string{}
+    }
+    return model.APIKeyResponse{
+        ID:         key.ID,
+        Name:       key.Name,
+        Prefix:     s.cfg.Prefix + "_" + key.KeyID,
+        Scopes:     scopes,
+        ExpiresAt:  key.ExpiresAt,
+        LastUsedAt: key.LastUsedAt,
+        CreatedAt:  key.CreatedAt,
+    }
+}
+
+// hashSecret hash SHA-256 (hex) dari API key lengkap atau client secret.
+// Secret berentropi tinggi sehingga hash cepat tanpa salt sudah cukup, dan
+// lookup tetap murah.
+func hashSecret(secret string) string {
+    sum := sha256.Sum256([]byte(secret))
+    return hex.EncodeToString(sum[:])
+}
+
+// randomString menghasilkan n byte acak yang di-encode dengan encode
+func randomString(n int, encode func([]byte) string) (string, error) {
+    b := make([]byte, n)
+    if _, err := rand.Read(b); err != nil {
+        return "", fmt.Errorf("failed to generate secret: %w", err)
+    }
+    return encode(b), nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// AuditLogger mencatat event keamanan ke log audit append-only
+type AuditLogger struct {
+    repo repository.AuditRepository
+}
+
+// NewAuditLogger membuat instance baru AuditLogger
+func NewAuditLogger(repo repository.AuditRepository) *AuditLogger {
+    return &AuditLogger{repo: repo}
+}
+
+// Record menyimpan event audit. IP dan user agent diambil dari context
+// (diisi oleh middleware ClientInfo). Jenis actor yang kosong diambil dari
+// principal yang mengautentikasi request (lihat WithActorType), dan jenis
+// subject yang kosong dianggap pengguna. Kegagalan menulis audit dicatat ke
+// log aplikasi tetapi tidak menggagalkan operasi yang diaudit.
+func (a *AuditLogger) Record(ctx context.Context, event model.AuditEvent) {
+    // Event tetap dicatat walaupun klien sudah memutus koneksi
+    ctx = context.WithoutCancel(ctx)
+
+    if info, ok := ClientInfoFromContext(ctx); ok {
+        event.IP = info.IP
+        event.UserAgent = info.UserAgent
+    }
+    if event.ActorID != nil && event.ActorType == "" {
+        event.ActorType = actorTypeFromContext(ctx)
+    }
+    // Actor tanpa ID pada request mTLS dicatat dengan subject sertifikatnya
+    if subject, ok := ctx.Value(actorSubjectKey{}).(string); ok && event.ActorID == nil && event.ActorSubject == "" {
+        event.ActorSubject = subject
+        event.ActorType = model.PrincipalTypeService
+    }
+    if event.SubjectID != nil && event.SubjectType == "" {
+        event.SubjectType = model.PrincipalTypeUser
+    }
+    if event.CreatedAt.IsZero() {
+        event.CreatedAt = time.Now().UTC()
+    }
+
+    if err := a.repo.Insert(ctx, &event); err != nil {
+        slog.ErrorContext(ctx, "Failed to record audit event",
+            "event_type", event.EventType, "outcome", event.Outcome, "error", err)
+    }
+}
+
+// Find mengambil event audit sesuai filter
+func (a *AuditLogger) Find(ctx context.Context, filter model.AuditEventFilter) ([]model.AuditEvent, error) {
+    return a.repo.Find(ctx, filter)
+}
+
+// Export mengalirkan event audit sesuai filter ke fn
+func (a *AuditLogger) Export(ctx context.Context, filter model.AuditEventFilter, fn func(event *model.AuditEvent) error) error {
+    return a.repo.ForEach(ctx, filter, fn)
+}
+
+// ClientInfo informasi klien HTTP yang dicatat pada event audit
+type ClientInfo struct {
+    IP        string
+    UserAgent string
+}
+
+type clientInfoKey struct{}
+
+// WithClientInfo menyimpan informasi klien ke context
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+    return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext mengambil informasi klien dari context
+func ClientInfoFromContext(ctx context.Context) (ClientInfo, bool) {
+    info, ok := ctx.Value(clientInfoKey{}).(ClientInfo)
+    return info, ok
+}
+
+type actorTypeKey struct{}
+
+// WithActorType menyimpan jenis principal yang mengautentikasi request ke
+// context; dipanggil middleware autentikasi
+func WithActorType(ctx context.Context, principalType string) context.Context {
+    return context.WithValue(ctx, actorTypeKey{}, principalType)
+}
+
+// actorTypeFromContext mengambil jenis actor dari context; default pengguna
+func actorTypeFromContext(ctx context.Context) string {
+    if principalType, ok := ctx.Value(actorTypeKey{}).(string); ok && principalType != "" {
+        return principalType
+    }
+    return model.PrincipalTypeUser
+}
+
+type actorSubjectKey struct{}
+
+// WithActorSubject menyimpan subject sertifikat klien principal mTLS ke
+// context; dicatat sebagai actor event audit karena principal tersebut
+// tidak memiliki ID
+func WithActorSubject(ctx context.Context, subject string) context.Context {
+    return context.WithValue(ctx, actorSubjectKey{}, subject)
+}
+
+// int64Ptr helper untuk field audit opsional
+func int64Ptr(v int64) *int64 {
+    return &v
+}
@@ -1,63 +1,91 @@
 package service
 
 import (
+	"auth-service/internal/metrics"
 	"auth-service/internal/model"
 	"auth-service/internal/repository"
+	"auth-service/internal/signing"
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("auth-service/internal/service")
+
 // AuthService interface untuk layanan autentikasi
 type AuthService interface {
-    Register(userReq *model.UserRegisterRequest) (*model.UserResponse, string, error)
-    Login(email, password string) (string, *model.UserResponse, error)
-    ValidateToken(tokenString string) (*model.JWTClaims, error)
-    Logout(tokenString string) error
-    GetUserProfile(userID int64) (*model.UserResponse, error)
+    Register(ctx context.Context, userReq *model.UserRegisterRequest) (*model.UserResponse, string, error)
+    Login(ctx context.Context, email, password string) (string, *model.UserResponse, error)
+    ValidateToken(ctx context.Context, tokenString string) (*model.JWTClaims, error)
+    Logout(ctx context.Context, tokenString string) error
+    GetUserProfile(ctx context.Context, userID int64) (*model.UserResponse, error)
+    UpdateUserRole(ctx context.Context, actorID *int64, userID int64, role string) (*model.UserResponse, error)
+    UpdateLocale(ctx context.Context, userID int64, locale string) (*model.UserResponse, string, error)
 }
 
 type authService struct {
     userRepo       repository.UserRepository
-    jwtSecret      string
+    keys           *signing.Keys
     jwtIssuer      string
     jwtExpiry      time.Duration
-    tokenBlacklist map[string]time.Time
+    roles          []string
+    defaultRole    string
+    tokenBlacklist *TokenBlacklist
+    audit          *AuditLogger
 }
 
 // NewAuthService membuat instance baru AuthService
 func NewAuthService(
     userRepo repository.UserRepository,
-    jwtSecret string,
+    keys *signing.Keys,
     jwtIssuer string,
     jwtExpiry time.Duration,
+    roles []string,
+    defaultRole string,
+    tokenBlacklist *TokenBlacklist,
+    audit *AuditLogger,
 ) AuthService {
     return &authService{
         userRepo:       userRepo,
-        jwtSecret:      jwtSecret,
+        keys:           keys,
         jwtIssuer:      jwtIssuer,
         jwtExpiry:      jwtExpiry,
-        tokenBlacklist: make(map[string]time.Time),
+        roles:          roles,
+        defaultRole:    defaultRole,
+        tokenBlacklist: tokenBlacklist,
+        audit:          audit,
     }
 }
 
 // Register mendaftarkan pengguna baru dan langsung membuat JWT
-func (s *authService) Register(userReq *model.UserRegisterRequest) (*model.UserResponse, string, error) {
+func (s *authService) Register(ctx context.Context, userReq *model.UserRegisterRequest) (*model.UserResponse, string, error) {
     // Set default role
     role := userReq.Role
     if role == "" {
-        role = "user"
+        role = s.defaultRole
     }
-    if role != "user" && role != "admin" {
-        return nil, "", errors.New("invalid role, must be 'user' or 'admin'")
+    if !s.isAllowedRole(role) {
+        s.audit.Record(ctx, model.AuditEvent{
+            EventType:    model.AuditEventRegister,
+            SubjectEmail: userReq.Email,
+            Outcome:      model.AuditOutcomeFailure,
+            Reason:       "invalid_role",
+        })
+        return nil, "", fmt.Errorf("%w, must be one of: %s", model.ErrInvalidRole, strings.Join(s.roles, ", "))
     }
 
     // Hash password
+    _, hashSpan := tracer.Start(ctx, "bcrypt.Hash")
     hashedPassword, err := repository.HashPassword(userReq.Password)
+    hashSpan.End()
     if err != nil {
-        return nil, "", fmt.Errorf("failed to hash password: %v", err)
+        return nil, "", fmt.Errorf("failed to hash password: %w", err)
     }
 
     // Buat user object
@@ -66,33 +94,44 @@ func (s *authService) Register(userReq *model.UserRegisterRequest) (*model.UserR
         Email:        userReq.Email,
         PasswordHash: hashedPassword,
         Role:         role,
+        Locale:       userReq.Locale,
         CreatedAt:    time.Now(),
     }
 
     // Simpan ke database
-    if err := s.userRepo.CreateUser(user); err != nil {
-        return nil, "", fmt.Errorf("failed to create user: %v", err)
+    if err := s.userRepo.CreateUser(ctx, user); err != nil {
+        metrics.RegistrationsTotal.WithLabelValues("failure").Inc()
+        reason := "create_failed"
+        if errors.Is(err, model.ErrEmailTaken) {
+            reason = "email_taken"
+        }
+        s.audit.Record(ctx, model.AuditEvent{
+            EventType:    model.AuditEventRegister,
+            SubjectEmail: userReq.Email,
+            Outcome:      model.AuditOutcomeFailure,
+            Reason:       reason,
+        })
+        if errors.Is(err, model.ErrEmailTaken) {
+            return nil, "", err
+        }
+        return nil, "", fmt.Errorf("failed to create user: %w", err)
     }
 
     // Buat JWT token
-    claims := &model.JWTClaims{
-        UserID: user.ID,
-        Email:  user.Email,
-        Name:   user.Name,
-        Role:   user.Role,
-        RegisteredClaims: jwt.RegisteredClaims{
-            ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.jwtExpiry)),
-            IssuedAt:  jwt.NewNumericDate(time.Now()),
-            Issuer:    s.jwtIssuer,
-            Subject:   fmt.Sprintf("%d", user.ID),
-        },
-    }
-
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-    tokenString, err := token.SignedString([]byte(s.jwtSecret))
+    tokenString, err := s.generateToken(user)
     if err != nil {
-        return nil, "", fmt.Errorf("failed to generate token: %v", err)
+        metrics.RegistrationsTotal.WithLabelValues("failure").Inc()
+        return nil, "", fmt.Errorf("failed to generate token: %w", err)
     }
+    metrics.RegistrationsTotal.WithLabelValues("success").Inc()
+    slog.InfoContext(ctx, "User registered", "user_id", user.ID, "role", user.Role)
+    s.audit.Record(ctx, model.AuditEvent{
+        EventType:    model.AuditEventRegister,
+        ActorID:      int64Ptr(user.ID),
+        SubjectID:    int64Ptr(user.ID),
+        SubjectEmail: user.Email,
+        Outcome:      model.AuditOutcomeSuccess,
+    })
 
     // Return user response + token
     userResp := &model.UserResponse{
@@ -100,6 +139,7 @@ func (s *authService) Register(userReq *model.UserRegisterRequest) (*model.UserR
         Name:      user.Name,
         Email:     user.Email,
         Role:      user.Role,
+        Locale:    user.Locale,
         CreatedAt: user.CreatedAt,
     }
 
@@ -107,42 +147,63 @@ func (s *authService) Register(userReq *model.UserRegisterRequest) (*model.UserR
 }
 
 // Login melakukan autentikasi pengguna dan menghasilkan JWT token
-func (s *authService) Login(email, password string) (string, *model.UserResponse, error) {
-    user, err := s.userRepo.FindByEmail(email)
+func (s *authService) Login(ctx context.Context, email, password string) (string, *model.UserResponse, error) {
+    user, err := s.userRepo.FindByEmail(ctx, email)
+    if err != nil && !errors.Is(err, model.ErrUserNotFound) {
+        return "", nil, fmt.Errorf("failed to find user: %w", err)
+    }
     if err != nil {
-        return "", nil, errors.New("invalid email or password")
+        metrics.LoginFailureTotal.WithLabelValues(metrics.LoginReasonUnknownEmail).Inc()
+        slog.InfoContext(ctx, "Login failed", "reason", metrics.LoginReasonUnknownEmail)
+        s.audit.Record(ctx, model.AuditEvent{
+            EventType:    model.AuditEventLogin,
+            SubjectEmail: email,
+            Outcome:      model.AuditOutcomeFailure,
+            Reason:       metrics.LoginReasonUnknownEmail,
+        })
+        return "", nil, model.ErrInvalidCredentials
     }
 
     // Verifikasi password
-    if err := repository.CheckPassword(password, user.PasswordHash); err != nil {
-        return "", nil, errors.New("invalid email or password")
+    _, compareSpan := tracer.Start(ctx, "bcrypt.Compare")
+    err = repository.CheckPassword(password, user.PasswordHash)
+    compareSpan.End()
+    if err != nil {
+        metrics.LoginFailureTotal.WithLabelValues(metrics.LoginReasonInvalidPassword).Inc()
+        slog.InfoContext(ctx, "Login failed", "reason", metrics.LoginReasonInvalidPassword, "user_id", user.ID)
+        s.audit.Record(ctx, model.AuditEvent{
+            EventType:    model.AuditEventLogin,
+            SubjectID:    int64Ptr(user.ID),
+            SubjectEmail: user.Email,
+            Outcome:      model.AuditOutcomeFailure,
+            Reason:       metrics.LoginReasonInvalidPassword,
+        })
+        return "", nil, model.ErrInvalidCredentials
     }
 
     // Buat JWT token
-    claims := &model.JWTClaims{
-        UserID: user.ID,
-        Email:  user.Email,
-        Name:   user.Name,
-        Role:   user.Role,
-        RegisteredClaims: jwt.RegisteredClaims{
-            ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.jwtExpiry)),
-            IssuedAt:  jwt.NewNumericDate(time.Now()),
-            Issuer:    s.jwtIssuer,
-            Subject:   fmt.Sprintf("%d", user.ID),
-        },
-    }
-
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-    tokenString, err := token.SignedString([]byte(s.jwtSecret))
+    tokenString, err := s.generateToken(user)
     if err != nil {
-        return "", nil, fmt.Errorf("failed to generate token: %v", err)
+        metrics.LoginFailureTotal.WithLabelValues(metrics.LoginReasonTokenError).Inc()
+        slog.ErrorContext(ctx, "Failed to sign token", "error", err)
+        return "", nil, fmt.Errorf("failed to generate token: %w", err)
     }
+    metrics.LoginSuccessTotal.Inc()
+    slog.InfoContext(ctx, "Login succeeded", "user_id", user.ID)
+    s.audit.Record(ctx, model.AuditEvent{
+        EventType:    model.AuditEventLogin,
+        ActorID:      int64Ptr(user.ID),
+        SubjectID:    int64Ptr(user.ID),
+        SubjectEmail: user.Email,
+        Outcome:      model.AuditOutcomeSuccess,
+    })
 
     userResp := &model.UserResponse{
         ID:        user.ID,
         Name:      user.Name,
         Email:     user.Email,
         Role:      user.Role,
+        Locale:    user.Locale,
         CreatedAt: user.CreatedAt,
     }
 
@@ -150,49 +211,71 @@ func (s *authService) Login(email, password string) (string, *model.UserResponse
 }
 
 // ValidateToken memvalidasi JWT token dan mengembalikan claims
-func (s *authService) ValidateToken(tokenString string) (*model.JWTClaims, error) {
+func (s *authService) ValidateToken(ctx context.Context, tokenString string) (*model.JWTClaims, error) {
     // Cek blacklist
-    if expiry, exists := s.tokenBlacklist[tokenString]; exists && time.Now().Before(expiry) {
-        return nil, errors.New("token has been revoked")
+    if s.tokenBlacklist.Contains(tokenString) {
+        metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenRevoked).Inc()
+        return nil, model.ErrTokenRevoked
     }
 
-    token, err := jwt.ParseWithClaims(tokenString, &model.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-        if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-            return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-        }
-        return []byte(s.jwtSecret), nil
-    })
+    // Hanya algoritma yang dikonfigurasi yang diterima (mencegah alg confusion)
+    token, err := jwt.ParseWithClaims(tokenString, &model.JWTClaims{}, s.keys.Keyfunc, jwt.WithValidMethods(s.keys.ValidMethods()))
     if err != nil {
-        return nil, fmt.Errorf("invalid token: %v", err)
+        metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenInvalid).Inc()
+        if errors.Is(err, jwt.ErrTokenExpired) {
+            return nil, model.ErrTokenExpired
+        }
+        return nil, fmt.Errorf("%w: %v", model.ErrTokenInvalid, err)
     }
 
     claims, ok := token.Claims.(*model.JWTClaims)
     if !ok || !token.Valid {
-        return nil, errors.New("invalid token claims")
+        metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenInvalid).Inc()
+        return nil, fmt.Errorf("%w: invalid claims", model.ErrTokenInvalid)
     }
     if claims.Issuer != s.jwtIssuer {
-        return nil, errors.New("invalid token issuer")
+        metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenInvalidIssuer).Inc()
+        return nil, fmt.Errorf("%w: unexpected issuer", model.ErrTokenInvalid)
     }
 
+    metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenValid).Inc()
     return claims, nil
 }
 
-// Logout menambahkan token ke blacklist
-func (s *authService) Logout(tokenString string) error {
-    claims, err := s.ValidateToken(tokenString)
+// Logout menambahkan token ke blacklist. Untuk token impersonation yang
+// dicatat adalah akhir impersonation, bukan logout pengguna.
+func (s *authService) Logout(ctx context.Context, tokenString string) error {
+    claims, err := s.ValidateToken(ctx, tokenString)
     if err != nil {
         return err
     }
 
-    s.tokenBlacklist[tokenString] = claims.ExpiresAt.Time
+    s.tokenBlacklist.Add(tokenString, claims.ExpiresAt.Time)
+    metrics.TokenRevocationsTotal.Inc()
+    slog.InfoContext(ctx, "Token revoked", "user_id", claims.UserID)
+
+    event := model.AuditEvent{
+        EventType:    model.AuditEventLogout,
+        ActorID:      int64Ptr(claims.UserID),
+        SubjectID:    int64Ptr(claims.UserID),
+        SubjectEmail: claims.Email,
+        Outcome:      model.AuditOutcomeSuccess,
+    }
+    // Mencabut token impersonation berarti mengakhiri impersonation oleh
+    // admin; token hasil delegasi dicatat sebagai logout biasa
+    if claims.IsImpersonated() && !claims.IsDelegated() {
+        event.EventType = model.AuditEventImpersonationStop
+        event.ActorID = int64Ptr(claims.Impersonator().UserID)
+    }
+    s.audit.Record(ctx, event)
     return nil
 }
 
 // GetUserProfile mengambil profil pengguna berdasarkan ID
-func (s *authService) GetUserProfile(userID int64) (*model.UserResponse, error) {
-    user, err := s.userRepo.FindByID(userID)
+func (s *authService) GetUserProfile(ctx context.Context, userID int64) (*model.UserResponse, error) {
+    user, err := s.userRepo.FindByID(ctx, userID)
     if err != nil {
-        return nil, fmt.Errorf("user not found: %v", err)
+        return nil, fmt.Errorf("failed to get user: %w", err)
     }
 
     return &model.UserResponse{
@@ -200,6 +283,109 @@ func (s *authService) GetUserProfile(userID int64) (*model.UserResponse, error)
         Name:      user.Name,
         Email:     user.Email,
         Role:      user.Role,
+        Locale:    user.Locale,
+        CreatedAt: user.CreatedAt,
+    }, nil
+}
+
+// UpdateUserRole mengubah role pengguna oleh admin dan mencatatnya di log audit
+func (s *authService) UpdateUserRole(ctx context.Context, actorID *int64, userID int64, role string) (*model.UserResponse, error) {
+    event := model.AuditEvent{
+        EventType: model.AuditEventRoleChange,
+        ActorID:   actorID,
+        SubjectID: int64Ptr(userID),
+        Outcome:   model.AuditOutcomeFailure,
+    }
+
+    if !s.isAllowedRole(role) {
+        event.Reason = "invalid_role"
+        s.audit.Record(ctx, event)
+        return nil, fmt.Errorf("%w, must be one of: %s", model.ErrInvalidRole, strings.Join(s.roles, ", "))
+    }
+
+    user, err := s.userRepo.FindByID(ctx, userID)
+    if err != nil {
+        event.Reason = "user_not_found"
+        s.audit.Record(ctx, event)
+        return nil, fmt.Errorf("failed to get user: %w", err)
+    }
+    event.SubjectEmail = user.Email
+
+    if err := s.userRepo.UpdateRole(ctx, userID, role); err != nil {
+        event.Reason = "update_failed"
+        s.audit.Record(ctx, event)
+        return nil, fmt.Errorf("failed to update role: %w", err)
+    }
+
+    event.Outcome = model.AuditOutcomeSuccess
+    event.Reason = fmt.Sprintf("%s -> %s", user.Role, role)
+    s.audit.Record(ctx, event)
+    slog.InfoContext(ctx, "User role changed", "subject_id", userID, "old_role", user.Role, "new_role", role)
+
+    return &model.UserResponse{
+        ID:        user.ID,
+        Name:      user.Name,
+        Email:     user.Email,
+        Role:      role,
+        Locale:    user.Locale,
         CreatedAt: user.CreatedAt,
     }, nil
 }
+
+// UpdateLocale mengubah preferensi bahasa pengguna dan menerbitkan token baru
+// yang membawa preferensi tersebut
+func (s *authService) UpdateLocale(ctx context.Context, userID int64, locale string) (*model.UserResponse, string, error) {
+    user, err := s.userRepo.FindByID(ctx, userID)
+    if err != nil {
+        return nil, "", fmt.Errorf("failed to get user: %w", err)
+    }
+
+    if err := s.userRepo.UpdateLocale(ctx, userID, locale); err != nil {
+        return nil, "", fmt.Errorf("failed to update locale: %w", err)
+    }
+    user.Locale = locale
+
+    tokenString, err := s.generateToken(user)
+    if err != nil {
+        return nil, "", fmt.Errorf("failed to generate token: %w", err)
+    }
+
+    return &model.UserResponse{
+        ID:        user.ID,
+        Name:      user.Name,
+        Email:     user.Email,
+        Role:      user.Role,
+        Locale:    user.Locale,
+        CreatedAt: user.CreatedAt,
+    }, tokenString, nil
+}
+
+// generateToken membuat dan menandatangani JWT untuk pengguna
+func (s *authService) generateToken(user *model.User) (string, error) {
+    claims := &model.JWTClaims{
+        UserID:        user.ID,
+        Email:         user.Email,
+        Name:          user.Name,
+        Role:          user.Role,
+        Locale:        user.Locale,
+        PrincipalType: model.PrincipalTypeUser,
+        RegisteredClaims: jwt.RegisteredClaims{
+            ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.jwtExpiry)),
+            IssuedAt:  jwt.NewNumericDate(time.Now()),
+            Issuer:    s.jwtIssuer,
+            Subject:   fmt.Sprintf("%d", user.ID),
+        },
+    }
+
+    return s.keys.Sign(claims)
+}
+
+// isAllowedRole memeriksa apakah role terdaftar di konfigurasi
+func (s *authService) isAllowedRole(role string) bool {
+    for _, r := range s.roles {
+        if r == role {
+            return true
+        }
+    }
+    return false
+}
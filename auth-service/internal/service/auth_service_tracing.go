@@ -0,0 +1,84 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/telemetry"
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedAuthService membungkus AuthService dan membuat span untuk setiap method
+type tracedAuthService struct {
+    next AuthService
+}
+
+// NewTracedAuthService membungkus AuthService dengan tracing OpenTelemetry
+func NewTracedAuthService(next AuthService) AuthService {
+    return &tracedAuthService{next: next}
+}
+
+func (s *tracedAuthService) Register(ctx context.Context, userReq *model.UserRegisterRequest) (*model.UserResponse, string, error) {
+    ctx, span := tracer.Start(ctx, "AuthService.Register")
+    user, token, err := s.next.Register(ctx, userReq)
+    if user != nil {
+        span.SetAttributes(attribute.Int64("user.id", user.ID), attribute.String("user.role", user.Role))
+    }
+    telemetry.EndSpan(span, err)
+    return user, token, err
+}
+
+func (s *tracedAuthService) Login(ctx context.Context, email, password string) (string, *model.UserResponse, error) {
+    ctx, span := tracer.Start(ctx, "AuthService.Login")
+    token, user, err := s.next.Login(ctx, email, password)
+    if user != nil {
+        span.SetAttributes(attribute.Int64("user.id", user.ID))
+    }
+    telemetry.EndSpan(span, err)
+    return token, user, err
+}
+
+func (s *tracedAuthService) ValidateToken(ctx context.Context, tokenString string) (*model.JWTClaims, error) {
+    ctx, span := tracer.Start(ctx, "AuthService.ValidateToken")
+    claims, err := s.next.ValidateToken(ctx, tokenString)
+    if claims != nil {
+        span.SetAttributes(attribute.Int64("user.id", claims.UserID))
+    }
+    telemetry.EndSpan(span, err)
+    return claims, err
+}
+
+func (s *tracedAuthService) Logout(ctx context.Context, tokenString string) error {
+    ctx, span := tracer.Start(ctx, "AuthService.Logout")
+    err := s.next.Logout(ctx, tokenString)
+    telemetry.EndSpan(span, err)
+    return err
+}
+
+func (s *tracedAuthService) GetUserProfile(ctx context.Context, userID int64) (*model.UserResponse, error) {
+    ctx, span := tracer.Start(ctx, "AuthService.GetUserProfile", trace.WithAttributes(attribute.Int64("user.id", userID)))
+    user, err := s.next.GetUserProfile(ctx, userID)
+    telemetry.EndSpan(span, err)
+    return user, err
+}
+
+func (s *tracedAuthService) UpdateUserRole(ctx context.Context, actorID *int64, userID int64, role string) (*model.UserResponse, error) {
+    ctx, span := tracer.Start(ctx, "AuthService.UpdateUserRole", trace.WithAttributes(attribute.Int64("user.id", userID)))
+    if actorID != nil {
+        span.SetAttributes(attribute.Int64("actor.id", *actorID))
+    }
+    user, err := s.next.UpdateUserRole(ctx, actorID, userID, role)
+    telemetry.EndSpan(span, err)
+    return user, err
+}
+
+func (s *tracedAuthService) UpdateLocale(ctx context.Context, userID int64, locale string) (*model.UserResponse, string, error) {
+    ctx, span := tracer.Start(ctx, "AuthService.UpdateLocale", trace.WithAttributes(
+        attribute.Int64("user.id", userID),
+        attribute.String("user.locale", locale),
+    ))
+    user, token, err := s.next.UpdateLocale(ctx, userID, locale)
+    telemetry.EndSpan(span, err)
+    return user, token, err
+}
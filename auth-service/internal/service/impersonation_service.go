@@ -0,0 +1,145 @@
+package service
+
+import (
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"auth-service/internal/signing"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ImpersonationService interface untuk impersonation pengguna oleh admin.
+// Token impersonation berisi claims pengguna target ditambah claim "act"
+// yang berisi admin, sehingga setiap request tetap dapat ditelusuri ke admin.
+type ImpersonationService interface {
+    Start(ctx context.Context, actor *model.JWTClaims, userID int64) (*model.UserResponse, string, error)
+    Stop(ctx context.Context, tokenString string) error
+}
+
+type impersonationService struct {
+    userRepo    repository.UserRepository
+    authService AuthService
+    keys        *signing.Keys
+    issuer      string
+    tokenTTL    time.Duration
+    adminRoles  []string
+    audit       *AuditLogger
+}
+
+// NewImpersonationService membuat instance baru ImpersonationService.
+// Pengguna dengan salah satu adminRoles tidak dapat di-impersonate.
+func NewImpersonationService(
+    userRepo repository.UserRepository,
+    authService AuthService,
+    keys *signing.Keys,
+    issuer string,
+    tokenTTL time.Duration,
+    adminRoles []string,
+    audit *AuditLogger,
+) ImpersonationService {
+    return &impersonationService{
+        userRepo:    userRepo,
+        authService: authService,
+        keys:        keys,
+        issuer:      issuer,
+        tokenTTL:    tokenTTL,
+        adminRoles:  adminRoles,
+        audit:       audit,
+    }
+}
+
+// Start menerbitkan token impersonation berumur pendek untuk pengguna userID
+// atas nama admin actor dan mencatat awal impersonation di log audit
+func (s *impersonationService) Start(ctx context.Context, actor *model.JWTClaims, userID int64) (*model.UserResponse, string, error) {
+    event := model.AuditEvent{
+        EventType: model.AuditEventImpersonationStart,
+        ActorID:   int64Ptr(actor.UserID),
+        SubjectID: int64Ptr(userID),
+        Outcome:   model.AuditOutcomeFailure,
+    }
+
+    if userID == actor.UserID {
+        event.Reason = "self"
+        s.audit.Record(ctx, event)
+        return nil, "", fmt.Errorf("%w: cannot impersonate yourself", model.ErrImpersonationNotAllowed)
+    }
+
+    user, err := s.userRepo.FindByID(ctx, userID)
+    if err != nil {
+        event.Reason = "user_not_found"
+        s.audit.Record(ctx, event)
+        return nil, "", fmt.Errorf("failed to get user: %w", err)
+    }
+    event.SubjectEmail = user.Email
+
+    // Impersonation admin lain sama dengan memperoleh hak akses admin tersebut
+    if slices.Contains(s.adminRoles, user.Role) {
+        event.Reason = "admin_target"
+        s.audit.Record(ctx, event)
+        return nil, "", fmt.Errorf("%w: cannot impersonate an admin", model.ErrImpersonationNotAllowed)
+    }
+
+    // jti membuat setiap token impersonation unik sehingga Stop hanya
+    // mencabut impersonation ini, meskipun dimulai pada detik yang sama
+    tokenID, err := randomString(16, hex.EncodeToString)
+    if err != nil {
+        return nil, "", err
+    }
+
+    now := time.Now()
+    claims := &model.JWTClaims{
+        UserID:        user.ID,
+        Email:         user.Email,
+        Name:          user.Name,
+        Role:          user.Role,
+        Locale:        user.Locale,
+        PrincipalType: model.PrincipalTypeUser,
+        Act:           &model.ActorClaim{UserID: actor.UserID, Email: actor.Email},
+        Impersonation: true,
+        RegisteredClaims: jwt.RegisteredClaims{
+            ExpiresAt: jwt.NewNumericDate(now.Add(s.tokenTTL)),
+            IssuedAt:  jwt.NewNumericDate(now),
+            Issuer:    s.issuer,
+            Subject:   fmt.Sprintf("%d", user.ID),
+            ID:        tokenID,
+        },
+    }
+    token, err := s.keys.Sign(claims)
+    if err != nil {
+        return nil, "", fmt.Errorf("failed to generate token: %w", err)
+    }
+
+    event.Outcome = model.AuditOutcomeSuccess
+    event.Reason = "expires " + claims.ExpiresAt.UTC().Format(time.RFC3339)
+    s.audit.Record(ctx, event)
+    slog.InfoContext(ctx, "Impersonation started", "subject_id", user.ID)
+
+    return &model.UserResponse{
+        ID:        user.ID,
+        Name:      user.Name,
+        Email:     user.Email,
+        Role:      user.Role,
+        Locale:    user.Locale,
+        CreatedAt: user.CreatedAt,
+    }, token, nil
+}
+
+// Stop mengakhiri impersonation dengan mencabut token impersonation. Event
+// impersonation_stop dicatat oleh Logout. Token hasil delegasi dari token
+// impersonation bukan milik admin sehingga tidak dapat mengakhirinya.
+func (s *impersonationService) Stop(ctx context.Context, tokenString string) error {
+    claims, err := s.authService.ValidateToken(ctx, tokenString)
+    if err != nil {
+        return err
+    }
+    if !claims.IsImpersonated() || claims.IsDelegated() {
+        return model.ErrNotImpersonating
+    }
+    return s.authService.Logout(ctx, tokenString)
+}
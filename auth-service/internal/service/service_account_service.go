@@ -0,0 +1,365 @@
+package service
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/model"
+	"auth-service/internal/repository"
+	"auth-service/internal/signing"
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ServiceAccountService interface untuk pengelolaan service account oleh
+// admin dan penerbitan token client_credentials. actorID adalah admin yang
+// melakukan perubahan.
+type ServiceAccountService interface {
+    Create(ctx context.Context, actorID int64, req *model.CreateServiceAccountRequest) (*model.ServiceAccountCredentialsResponse, error)
+    List(ctx context.Context) ([]model.ServiceAccountResponse, error)
+    Get(ctx context.Context, id int64) (*model.ServiceAccountResponse, error)
+    Update(ctx context.Context, actorID, id int64, req *model.UpdateServiceAccountRequest) (*model.ServiceAccountResponse, error)
+    Delete(ctx context.Context, actorID, id int64) error
+    RotateSecret(ctx context.Context, actorID, id int64) (*model.ServiceAccountCredentialsResponse, error)
+    IssueToken(ctx context.Context, clientID, clientSecret, scope string) (*model.TokenResponse, error)
+    AuthenticateClient(ctx context.Context, clientID, clientSecret string) (*model.ServiceAccount, error)
+}
+
+type serviceAccountService struct {
+    repo     repository.ServiceAccountRepository
+    keys     *signing.Keys
+    issuer   string
+    tokenTTL time.Duration
+    security config.SecurityConfig
+    audit    *AuditLogger
+}
+
+// NewServiceAccountService membuat instance baru ServiceAccountService
+func NewServiceAccountService(
+    repo repository.ServiceAccountRepository,
+    keys *signing.Keys,
+    issuer string,
+    tokenTTL time.Duration,
+    security config.SecurityConfig,
+    audit *AuditLogger,
+) ServiceAccountService {
+    return &serviceAccountService{
+        repo:     repo,
+        keys:     keys,
+        issuer:   issuer,
+        tokenTTL: tokenTTL,
+        security: security,
+        audit:    audit,
+    }
+}
+
+// Create membuat service account beserta client credentials-nya. Client
+// secret hanya dikembalikan sekali; yang disimpan hanya hash SHA-256-nya.
+func (s *serviceAccountService) Create(ctx context.Context, actorID int64, req *model.CreateServiceAccountRequest) (*model.ServiceAccountCredentialsResponse, error) {
+    event := model.AuditEvent{
+        EventType:   model.AuditEventServiceAccountCreate,
+        ActorID:     int64Ptr(actorID),
+        SubjectType: model.PrincipalTypeService,
+        Outcome:     model.AuditOutcomeFailure,
+        Reason:      req.Name,
+    }
+
+    if !s.security.HasRole(req.Role) {
+        s.audit.Record(ctx, event)
+        return nil, fmt.Errorf("%w, must be one of: %s", model.ErrInvalidRole, strings.Join(s.security.Roles, ", "))
+    }
+
+    clientID, err := randomString(8, hex.EncodeToString)
+    if err != nil {
+        return nil, err
+    }
+    secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+    if err != nil {
+        return nil, err
+    }
+
+    account := &model.ServiceAccount{
+        Name:             req.Name,
+        Description:      req.Description,
+        Role:             req.Role,
+        ClientID:         clientID,
+        ClientSecretHash: hashSecret(secret),
+        CreatedAt:        time.Now().UTC(),
+    }
+    if err := s.repo.Create(ctx, account); err != nil {
+        s.audit.Record(ctx, event)
+        if errors.Is(err, model.ErrServiceAccountNameTaken) {
+            return nil, err
+        }
+        return nil, fmt.Errorf("failed to create service account: %w", err)
+    }
+
+    event.SubjectID = int64Ptr(account.ID)
+    event.Outcome = model.AuditOutcomeSuccess
+    s.audit.Record(ctx, event)
+    slog.InfoContext(ctx, "Service account created", "service_account_id", account.ID, "role", account.Role)
+
+    return &model.ServiceAccountCredentialsResponse{ServiceAccountResponse: toServiceAccountResponse(account), ClientSecret: secret}, nil
+}
+
+// List mengambil semua service account tanpa secret
+func (s *serviceAccountService) List(ctx context.Context) ([]model.ServiceAccountResponse, error) {
+    accounts, err := s.repo.List(ctx)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list service accounts: %w", err)
+    }
+
+    responses := make([]model.ServiceAccountResponse, 0, len(accounts))
+    for i := range accounts {
+        responses = append(responses, toServiceAccountResponse(&accounts[i]))
+    }
+    return responses, nil
+}
+
+// Get mengambil satu service account tanpa secret
+func (s *serviceAccountService) Get(ctx context.Context, id int64) (*model.ServiceAccountResponse, error) {
+    account, err := s.find(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    response := toServiceAccountResponse(account)
+    return &response, nil
+}
+
+// Update mengubah description, role atau status disabled. Menonaktifkan
+// service account langsung menolak API key dan client credentials-nya;
+// access token yang sudah terbit tetap berlaku sampai kedaluwarsa.
+func (s *serviceAccountService) Update(ctx context.Context, actorID, id int64, req *model.UpdateServiceAccountRequest) (*model.ServiceAccountResponse, error) {
+    event := model.AuditEvent{
+        EventType:   model.AuditEventServiceAccountUpdate,
+        ActorID:     int64Ptr(actorID),
+        SubjectID:   int64Ptr(id),
+        SubjectType: model.PrincipalTypeService,
+        Outcome:     model.AuditOutcomeFailure,
+    }
+
+    account, err := s.find(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+
+    var changes []string
+    if req.Role != nil && *req.Role != account.Role {
+        if !s.security.HasRole(*req.Role) {
+            event.Reason = "invalid_role"
+            s.audit.Record(ctx, event)
+            return nil, fmt.Errorf("%w, must be one of: %s", model.ErrInvalidRole, strings.Join(s.security.Roles, ", "))
+        }
+        changes = append(changes, fmt.Sprintf("role %s -> %s", account.Role, *req.Role))
+        account.Role = *req.Role
+    }
+    if req.Description != nil && *req.Description != account.Description {
+        changes = append(changes, "description")
+        account.Description = *req.Description
+    }
+    if req.Disabled != nil && *req.Disabled != account.Disabled {
+        changes = append(changes, fmt.Sprintf("disabled %t", *req.Disabled))
+        account.Disabled = *req.Disabled
+    }
+
+    if len(changes) > 0 {
+        event.Reason = strings.Join(changes, ", ")
+        if err := s.repo.Update(ctx, account); err != nil {
+            s.audit.Record(ctx, event)
+            return nil, fmt.Errorf("failed to update service account: %w", err)
+        }
+        event.Outcome = model.AuditOutcomeSuccess
+        s.audit.Record(ctx, event)
+        slog.InfoContext(ctx, "Service account updated", "service_account_id", account.ID, "changes", event.Reason)
+    }
+
+    response := toServiceAccountResponse(account)
+    return &response, nil
+}
+
+// Delete menghapus service account beserta API key miliknya
+func (s *serviceAccountService) Delete(ctx context.Context, actorID, id int64) error {
+    event := model.AuditEvent{
+        EventType:   model.AuditEventServiceAccountDelete,
+        ActorID:     int64Ptr(actorID),
+        SubjectID:   int64Ptr(id),
+        SubjectType: model.PrincipalTypeService,
+        Outcome:     model.AuditOutcomeFailure,
+    }
+
+    if err := s.repo.Delete(ctx, id); err != nil {
+        s.audit.Record(ctx, event)
+        if errors.Is(err, model.ErrServiceAccountNotFound) {
+            return err
+        }
+        return fmt.Errorf("failed to delete service account: %w", err)
+    }
+
+    event.Outcome = model.AuditOutcomeSuccess
+    s.audit.Record(ctx, event)
+    slog.InfoContext(ctx, "Service account deleted", "service_account_id", id)
+    return nil
+}
+
+// RotateSecret membuat client secret baru; secret lama langsung tidak berlaku
+func (s *serviceAccountService) RotateSecret(ctx context.Context, actorID, id int64) (*model.ServiceAccountCredentialsResponse, error) {
+    event := model.AuditEvent{
+        EventType:   model.AuditEventServiceAccountSecret,
+        ActorID:     int64Ptr(actorID),
+        SubjectID:   int64Ptr(id),
+        SubjectType: model.PrincipalTypeService,
+        Outcome:     model.AuditOutcomeFailure,
+    }
+
+    account, err := s.find(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+
+    secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+    if err != nil {
+        return nil, err
+    }
+    account.ClientSecretHash = hashSecret(secret)
+    if err := s.repo.Update(ctx, account); err != nil {
+        s.audit.Record(ctx, event)
+        return nil, fmt.Errorf("failed to update service account: %w", err)
+    }
+
+    event.Outcome = model.AuditOutcomeSuccess
+    s.audit.Record(ctx, event)
+    slog.InfoContext(ctx, "Service account secret rotated", "service_account_id", account.ID)
+
+    return &model.ServiceAccountCredentialsResponse{ServiceAccountResponse: toServiceAccountResponse(account), ClientSecret: secret}, nil
+}
+
+// IssueToken menerbitkan access token untuk grant client_credentials. scope
+// (dipisahkan spasi) opsional dan membatasi token; setiap scope harus
+// dimiliki role service account. Tanpa scope, token membawa semua scope role
+// sehingga token service account selalu ter-scope.
+func (s *serviceAccountService) IssueToken(ctx context.Context, clientID, clientSecret, scope string) (*model.TokenResponse, error) {
+    event := model.AuditEvent{
+        EventType: model.AuditEventServiceToken,
+        Outcome:   model.AuditOutcomeFailure,
+    }
+
+    account, err := s.AuthenticateClient(ctx, clientID, clientSecret)
+    if account != nil {
+        // Service account sendiri adalah actor sekaligus subject event
+        event.ActorID = int64Ptr(account.ID)
+        event.ActorType = model.PrincipalTypeService
+        event.SubjectID = int64Ptr(account.ID)
+        event.SubjectType = model.PrincipalTypeService
+    }
+    if err != nil {
+        if !errors.Is(err, model.ErrInvalidClient) {
+            return nil, err
+        }
+        event.Reason = "invalid_client"
+        if account != nil {
+            event.Reason = "disabled"
+        }
+        s.audit.Record(ctx, event)
+        return nil, err
+    }
+
+    scopes := slices.Compact(slices.Sorted(slices.Values(strings.Fields(scope))))
+    if len(scopes) == 0 {
+        scopes = roleScopes(s.security, account.Role)
+    }
+    for _, requested := range scopes {
+        if !roleHasScope(s.security, account.Role, requested) {
+            event.Reason = "invalid_scope"
+            s.audit.Record(ctx, event)
+            return nil, fmt.Errorf("%w: %q", model.ErrInvalidScope, requested)
+        }
+    }
+
+    now := time.Now()
+    claims := serviceAccountClaims(account)
+    claims.Scopes = scopes
+    claims.IssuedAt = jwt.NewNumericDate(now)
+    claims.ExpiresAt = jwt.NewNumericDate(now.Add(s.tokenTTL))
+    claims.Issuer = s.issuer
+
+    token, err := s.keys.Sign(claims)
+    if err != nil {
+        return nil, fmt.Errorf("failed to generate token: %w", err)
+    }
+
+    event.Outcome = model.AuditOutcomeSuccess
+    event.Reason = strings.Join(scopes, " ")
+    s.audit.Record(ctx, event)
+    slog.InfoContext(ctx, "Service account token issued", "service_account_id", account.ID)
+
+    return &model.TokenResponse{
+        AccessToken: token,
+        TokenType:   "Bearer",
+        ExpiresIn:   int64(s.tokenTTL.Seconds()),
+        Scope:       strings.Join(scopes, " "),
+    }, nil
+}
+
+// AuthenticateClient memeriksa client credentials dan mengembalikan service
+// account-nya. Untuk service account yang dinonaktifkan, account tetap
+// dikembalikan bersama ErrInvalidClient agar pemanggil dapat mencatatnya.
+func (s *serviceAccountService) AuthenticateClient(ctx context.Context, clientID, clientSecret string) (*model.ServiceAccount, error) {
+    account, err := s.repo.FindByClientID(ctx, clientID)
+    if err != nil && !errors.Is(err, model.ErrServiceAccountNotFound) {
+        return nil, fmt.Errorf("failed to find service account: %w", err)
+    }
+    if err != nil || subtle.ConstantTimeCompare([]byte(hashSecret(clientSecret)), []byte(account.ClientSecretHash)) != 1 {
+        return nil, model.ErrInvalidClient
+    }
+    if account.Disabled {
+        return account, fmt.Errorf("%w: service account is disabled", model.ErrInvalidClient)
+    }
+    return account, nil
+}
+
+// find mengambil service account berdasarkan ID
+func (s *serviceAccountService) find(ctx context.Context, id int64) (*model.ServiceAccount, error) {
+    account, err := s.repo.FindByID(ctx, id)
+    if err != nil {
+        if errors.Is(err, model.ErrServiceAccountNotFound) {
+            return nil, err
+        }
+        return nil, fmt.Errorf("failed to get service account: %w", err)
+    }
+    return account, nil
+}
+
+// serviceAccountClaims membuat claims principal service account. UserID
+// berisi ID service account dan dibedakan dari pengguna lewat principal_type.
+func serviceAccountClaims(account *model.ServiceAccount) *model.JWTClaims {
+    return &model.JWTClaims{
+        UserID:        account.ID,
+        Name:          account.Name,
+        Role:          account.Role,
+        PrincipalType: model.PrincipalTypeService,
+        RegisteredClaims: jwt.RegisteredClaims{
+            Subject: "service:" + account.Name,
+        },
+    }
+}
+
+// toServiceAccountResponse mengubah ServiceAccount menjadi response tanpa hash
+func toServiceAccountResponse(account *model.ServiceAccount) model.ServiceAccountResponse {
+    return model.ServiceAccountResponse{
+        ID:          account.ID,
+        Name:        account.Name,
+        Description: account.Description,
+        Role:        account.Role,
+        ClientID:    account.ClientID,
+        Disabled:    account.Disabled,
+        CreatedAt:   account.CreatedAt,
+    }
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// TokenBlacklist menyimpan token yang sudah di-revoke sampai waktu kedaluwarsanya
+type TokenBlacklist struct {
+    mu     sync.RWMutex
+    tokens map[string]time.Time
+}
+
+// NewTokenBlacklist membuat instance baru TokenBlacklist
+func NewTokenBlacklist() *TokenBlacklist {
+    return &TokenBlacklist{tokens: make(map[string]time.Time)}
+}
+
+// Add memasukkan token ke blacklist sampai waktu expiry
+func (b *TokenBlacklist) Add(token string, expiry time.Time) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    b.tokens[token] = expiry
+}
+
+// Contains mengembalikan true jika token di-revoke dan belum kedaluwarsa
+func (b *TokenBlacklist) Contains(token string) bool {
+    b.mu.RLock()
+    defer b.mu.RUnlock()
+    expiry, exists := b.tokens[token]
+    return exists && time.Now().Before(expiry)
+}
+
+// Purge menghapus token yang sudah kedaluwarsa dan mengembalikan jumlahnya
+func (b *TokenBlacklist) Purge() int {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    now := time.Now()
+    purged := 0
+    for token, expiry := range b.tokens {
+        if !now.Before(expiry) {
+            delete(b.tokens, token)
+            purged++
+        }
+    }
+    return purged
+}
+
+// Run menjalankan pembersihan berkala sampai ctx dibatalkan
+func (b *TokenBlacklist) Run(ctx context.Context, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            slog.Info("Token blacklist cleanup stopped")
+            return
+        case <-ticker.C:
+            if purged := b.Purge(); purged > 0 {
+                slog.Debug("Purged expired tokens from blacklist", "count", purged)
+            }
+        }
+    }
+}
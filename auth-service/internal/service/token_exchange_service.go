@@ -0,0 +1,227 @@
+package service
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/model"
+	"auth-service/internal/signing"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenExchangeService interface untuk grant token exchange (RFC 8693).
+// Client (service account) menukar token pengguna menjadi token untuk
+// audience tertentu dengan scope yang sama atau lebih sempit.
+type TokenExchangeService interface {
+    Exchange(ctx context.Context, clientID, clientSecret string, req *model.TokenExchangeRequest) (*model.TokenResponse, error)
+}
+
+type tokenExchangeService struct {
+    authService     AuthService
+    serviceAccounts ServiceAccountService
+    keys            *signing.Keys
+    issuer          string
+    cfg             config.TokenExchangeConfig
+    security        config.SecurityConfig
+    audit           *AuditLogger
+}
+
+// NewTokenExchangeService membuat instance baru TokenExchangeService.
+// Subject dan actor token divalidasi dengan authService.ValidateToken.
+func NewTokenExchangeService(
+    authService AuthService,
+    serviceAccounts ServiceAccountService,
+    keys *signing.Keys,
+    issuer string,
+    cfg config.TokenExchangeConfig,
+    security config.SecurityConfig,
+    audit *AuditLogger,
+) TokenExchangeService {
+    return &tokenExchangeService{
+        authService:     authService,
+        serviceAccounts: serviceAccounts,
+        keys:            keys,
+        issuer:          issuer,
+        cfg:             cfg,
+        security:        security,
+        audit:           audit,
+    }
+}
+
+// Exchange menerbitkan token untuk subject token dengan audience dan scope
+// yang diminta. Token baru tidak pernah lebih luas dari subject token: scope
+// harus dimiliki subject token, audience harus termasuk aud subject token
+// (jika ada), dan masa berlakunya tidak melebihi subject maupun actor token.
+func (s *tokenExchangeService) Exchange(ctx context.Context, clientID, clientSecret string, req *model.TokenExchangeRequest) (*model.TokenResponse, error) {
+    event := model.AuditEvent{
+        EventType: model.AuditEventTokenExchange,
+        Outcome:   model.AuditOutcomeFailure,
+    }
+    fail := func(reason string, err error) (*model.TokenResponse, error) {
+        event.Reason = reason
+        s.audit.Record(ctx, event)
+        return nil, err
+    }
+
+    client, err := s.serviceAccounts.AuthenticateClient(ctx, clientID, clientSecret)
+    if client != nil {
+        event.ActorID = int64Ptr(client.ID)
+        event.ActorType = model.PrincipalTypeService
+    }
+    if err != nil {
+        if !errors.Is(err, model.ErrInvalidClient) {
+            return nil, err
+        }
+        if client != nil {
+            return fail("disabled", err)
+        }
+        return fail("invalid_client", err)
+    }
+    if !s.cfg.HasClient(client.Name) {
+        return fail("unauthorized_client", fmt.Errorf("%w: no token exchange policy for %q", model.ErrUnauthorizedClient, client.Name))
+    }
+
+    subject, err := s.authService.ValidateToken(ctx, req.SubjectToken)
+    if err != nil {
+        return fail("invalid_subject_token", fmt.Errorf("%w: subject_token: %v", model.ErrInvalidGrant, err))
+    }
+    principal := subject.Principal()
+    event.SubjectID = int64Ptr(principal.ID)
+    event.SubjectType = principal.Type
+    if !subject.IsService() {
+        event.SubjectEmail = subject.Email
+    }
+
+    var actor *model.JWTClaims
+    if req.ActorToken != "" {
+        if actor, err = s.authService.ValidateToken(ctx, req.ActorToken); err != nil {
+            return fail("invalid_actor_token", fmt.Errorf("%w: actor_token: %v", model.ErrInvalidGrant, err))
+        }
+    }
+
+    for _, audience := range req.Audience {
+        if !s.cfg.AllowsAudience(client.Name, audience) {
+            return fail("invalid_target", fmt.Errorf("%w: %q", model.ErrInvalidTarget, audience))
+        }
+        // aud hanya dapat dipersempit, tidak diperluas
+        if !subject.ValidForAudience(audience) {
+            return fail("invalid_target", fmt.Errorf("%w: %q is not an audience of the subject token", model.ErrInvalidTarget, audience))
+        }
+        // Actor token untuk layanan lain tidak boleh dipakai bertindak di sini
+        if actor != nil && !actor.ValidForAudience(audience) {
+            return fail("invalid_actor_token", fmt.Errorf("%w: actor_token is not valid for audience %q", model.ErrInvalidGrant, audience))
+        }
+    }
+
+    scopes, err := s.narrowScopes(subject, req.Scope)
+    if err != nil {
+        return fail("invalid_scope", err)
+    }
+
+    now := time.Now()
+    expiresAt := now.Add(s.cfg.TokenTTL)
+    for _, claims := range []*model.JWTClaims{subject, actor} {
+        if claims != nil && claims.ExpiresAt != nil && claims.ExpiresAt.Before(expiresAt) {
+            expiresAt = claims.ExpiresAt.Time
+        }
+    }
+
+    audience := slices.Compact(slices.Sorted(slices.Values(req.Audience)))
+    claims := &model.JWTClaims{
+        UserID:        subject.UserID,
+        Email:         subject.Email,
+        Name:          subject.Name,
+        Role:          subject.Role,
+        Locale:        subject.Locale,
+        Scopes:        scopes,
+        PrincipalType: principal.Type,
+        Act:           delegationChain(subject, actor),
+        Impersonation: subject.Impersonation,
+        RegisteredClaims: jwt.RegisteredClaims{
+            Audience:  audience,
+            ExpiresAt: jwt.NewNumericDate(expiresAt),
+            IssuedAt:  jwt.NewNumericDate(now),
+            Issuer:    s.issuer,
+            Subject:   subject.Subject,
+        },
+    }
+    token, err := s.keys.Sign(claims)
+    if err != nil {
+        return nil, fmt.Errorf("failed to generate token: %w", err)
+    }
+
+    event.Outcome = model.AuditOutcomeSuccess
+    event.Reason = "aud=" + strings.Join(audience, ",") + " scope=" + strings.Join(scopes, " ")
+    s.audit.Record(ctx, event)
+    slog.InfoContext(ctx, "Token exchanged", "client", client.Name, "audience", audience)
+
+    return &model.TokenResponse{
+        AccessToken:     token,
+        IssuedTokenType: model.TokenTypeAccessToken,
+        TokenType:       "Bearer",
+        ExpiresIn:       int64(time.Until(expiresAt).Seconds()),
+        Scope:           strings.Join(scopes, " "),
+    }, nil
+}
+
+// narrowScopes menentukan scope token baru. Scope yang diminta harus
+// dimiliki role subject dan, jika subject token sudah dibatasi scope, juga
+// termasuk scope subject token. Tanpa scope yang diminta, scope subject token
+// dipertahankan; token login biasa memperoleh semua scope role-nya.
+func (s *tokenExchangeService) narrowScopes(subject *model.JWTClaims, scope string) ([]string, error) {
+    restricted := len(subject.Scopes) > 0 || subject.IsAudienceRestricted()
+
+    requested := slices.Compact(slices.Sorted(slices.Values(strings.Fields(scope))))
+    if len(requested) == 0 {
+        if restricted {
+            return subject.Scopes, nil
+        }
+        return roleScopes(s.security, subject.Role), nil
+    }
+
+    for _, scope := range requested {
+        if !roleHasScope(s.security, subject.Role, scope) || (restricted && !slices.Contains(subject.Scopes, scope)) {
+            return nil, fmt.Errorf("%w: %q", model.ErrInvalidScope, scope)
+        }
+    }
+    return requested, nil
+}
+
+// delegationChain membentuk claim "act" token baru. Dengan actor token,
+// actor menjadi actor terkini dan actor subject token sebelumnya disimpan
+// bersarang (RFC 8693 4.1); tanpa actor token, act subject token dipertahankan.
+func delegationChain(subject, actor *model.JWTClaims) *model.ActorClaim {
+    if actor == nil {
+        return subject.Act
+    }
+
+    act := &model.ActorClaim{UserID: actor.UserID, Act: subject.Act}
+    if actor.IsService() {
+        act.PrincipalType = model.PrincipalTypeService
+    } else {
+        act.Email = actor.Email
+    }
+    return act
+}
+
+// roleScopes semua scope yang dimiliki role: admin (untuk role admin) dan
+// permission dari security.permissions, terurut
+func roleScopes(security config.SecurityConfig, role string) []string {
+    var scopes []string
+    if roleHasScope(security, role, model.ScopeAdmin) {
+        scopes = append(scopes, model.ScopeAdmin)
+    }
+    for permission := range security.Permissions {
+        if security.HasPermission(role, permission) {
+            scopes = append(scopes, permission)
+        }
+    }
+    slices.Sort(scopes)
+    return scopes
+}
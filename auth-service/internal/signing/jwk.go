@@ -0,0 +1,115 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// JWK public key dalam format JSON Web Key (RFC 7517). Hanya RSA dan
+// Ed25519 (OKP) yang didukung.
+type JWK struct {
+    Kty string `json:"kty"`
+    Kid string `json:"kid,omitempty"`
+    Use string `json:"use,omitempty"`
+    Alg string `json:"alg,omitempty"`
+    // Parameter RSA
+    N string `json:"n,omitempty"`
+    E string `json:"e,omitempty"`
+    // Parameter OKP (Ed25519)
+    Crv string `json:"crv,omitempty"`
+    X   string `json:"x,omitempty"`
+}
+
+// JWKSet kumpulan JWK seperti yang disajikan di /.well-known/jwks.json
+type JWKSet struct {
+    Keys []JWK `json:"keys"`
+}
+
+// Find mencari key berdasarkan kid
+func (s JWKSet) Find(kid string) (JWK, bool) {
+    for _, key := range s.Keys {
+        if key.Kid == kid {
+            return key, true
+        }
+    }
+    return JWK{}, false
+}
+
+// NewJWK membuat JWK dari public key dengan kid berupa thumbprint RFC 7638
+func NewJWK(alg string, pub crypto.PublicKey) (JWK, error) {
+    var key JWK
+    switch pub := pub.(type) {
+    case *rsa.PublicKey:
+        key = JWK{
+            Kty: "RSA",
+            N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+            E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+        }
+    case ed25519.PublicKey:
+        key = JWK{
+            Kty: "OKP",
+            Crv: "Ed25519",
+            X:   base64.RawURLEncoding.EncodeToString(pub),
+        }
+    default:
+        return JWK{}, fmt.Errorf("unsupported public key type %T", pub)
+    }
+
+    key.Use = "sig"
+    key.Alg = alg
+    key.Kid = key.Thumbprint()
+    return key, nil
+}
+
+// Thumbprint menghitung JWK thumbprint SHA-256 (RFC 7638)
+func (k JWK) Thumbprint() string {
+    // Member wajib dalam urutan leksikografis, tanpa spasi
+    var canonical string
+    switch k.Kty {
+    case "RSA":
+        canonical = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, k.E, k.N)
+    case "OKP":
+        canonical = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, k.Crv, k.X)
+    default:
+        return ""
+    }
+    sum := sha256.Sum256([]byte(canonical))
+    return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// PublicKey mengubah JWK menjadi public key untuk verifikasi tanda tangan
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+    switch k.Kty {
+    case "RSA":
+        n, err := base64.RawURLEncoding.DecodeString(k.N)
+        if err != nil {
+            return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+        }
+        e, err := base64.RawURLEncoding.DecodeString(k.E)
+        if err != nil {
+            return nil, fmt.Errorf("invalid RSA exponent: %w", err)
+        }
+        exponent := new(big.Int).SetBytes(e)
+        if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+            return nil, errors.New("invalid RSA public key")
+        }
+        return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+    case "OKP":
+        if k.Crv != "Ed25519" {
+            return nil, fmt.Errorf("unsupported OKP curve %q", k.Crv)
+        }
+        x, err := base64.RawURLEncoding.DecodeString(k.X)
+        if err != nil || len(x) != ed25519.PublicKeySize {
+            return nil, errors.New("invalid Ed25519 public key")
+        }
+        return ed25519.PublicKey(x), nil
+    default:
+        return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+    }
+}
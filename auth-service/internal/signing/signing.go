@@ -0,0 +1,139 @@
+package signing
+
+import (
+	"auth-service/internal/config"
+	"crypto"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Keys kunci untuk menandatangani dan memverifikasi JWT. Untuk HS256 kunci
+// berupa secret bersama; untuk RS256/EdDSA hanya public key yang
+// dipublikasikan lewat JWKS.
+type Keys struct {
+    method    jwt.SigningMethod
+    signKey   interface{}
+    verifyKey interface{}
+    jwk       *JWK
+}
+
+// New membuat Keys sesuai konfigurasi jwt.algorithm
+func New(cfg config.JWTConfig) (*Keys, error) {
+    if cfg.UsesHMAC() {
+        return NewHMAC(cfg.Secret)
+    }
+
+    pemBytes, err := os.ReadFile(cfg.PrivateKeyFile)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+    }
+    return NewFromPEM(cfg.Algorithm, pemBytes)
+}
+
+// NewHMAC membuat Keys HS256 dari secret bersama
+func NewHMAC(secret string) (*Keys, error) {
+    if secret == "" {
+        return nil, errors.New("JWT signing secret is empty")
+    }
+    return &Keys{
+        method:    jwt.SigningMethodHS256,
+        signKey:   []byte(secret),
+        verifyKey: []byte(secret),
+    }, nil
+}
+
+// NewFromPEM membuat Keys RS256 atau EdDSA dari private key PEM
+func NewFromPEM(algorithm string, pemBytes []byte) (*Keys, error) {
+    var (
+        method    jwt.SigningMethod
+        signKey   crypto.Signer
+        verifyKey crypto.PublicKey
+    )
+    switch algorithm {
+    case "RS256":
+        key, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+        if err != nil {
+            return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
+        }
+        if key.N.BitLen() < 2048 {
+            return nil, fmt.Errorf("RSA key is %d bits, at least 2048 required", key.N.BitLen())
+        }
+        method, signKey, verifyKey = jwt.SigningMethodRS256, key, &key.PublicKey
+    case "EdDSA":
+        key, err := jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+        if err != nil {
+            return nil, fmt.Errorf("failed to parse Ed25519 private key: %w", err)
+        }
+        edKey, ok := key.(ed25519.PrivateKey)
+        if !ok {
+            return nil, errors.New("private key is not an Ed25519 key")
+        }
+        method, signKey, verifyKey = jwt.SigningMethodEdDSA, edKey, edKey.Public()
+    default:
+        return nil, fmt.Errorf("unsupported JWT algorithm %q", algorithm)
+    }
+
+    jwk, err := NewJWK(method.Alg(), verifyKey)
+    if err != nil {
+        return nil, err
+    }
+    return &Keys{method: method, signKey: signKey, verifyKey: verifyKey, jwk: &jwk}, nil
+}
+
+// Algorithm nama algoritma JWT ("HS256", "RS256" atau "EdDSA")
+func (k *Keys) Algorithm() string {
+    return k.method.Alg()
+}
+
+// Sign membuat token bertanda tangan dari claims. Token asimetris membawa
+// header kid agar verifier dapat memilih key dari JWKS.
+func (k *Keys) Sign(claims jwt.Claims) (string, error) {
+    token := jwt.NewWithClaims(k.method, claims)
+    if k.jwk != nil {
+        token.Header["kid"] = k.jwk.Kid
+    }
+    return token.SignedString(k.signKey)
+}
+
+// Keyfunc memilih key verifikasi untuk jwt.Parse. Gunakan bersama
+// jwt.WithValidMethods(keys.ValidMethods()) agar algoritma lain ditolak.
+func (k *Keys) Keyfunc(token *jwt.Token) (interface{}, error) {
+    if k.jwk != nil {
+        if kid, ok := token.Header["kid"].(string); ok && kid != k.jwk.Kid {
+            return nil, fmt.Errorf("unknown key ID %q", kid)
+        }
+    }
+    return k.verifyKey, nil
+}
+
+// ValidMethods algoritma yang diterima saat verifikasi
+func (k *Keys) ValidMethods() []string {
+    return []string{k.method.Alg()}
+}
+
+// JWKS public key yang boleh dipublikasikan; kosong untuk HS256 karena
+// secret bersama tidak boleh keluar dari layanan
+func (k *Keys) JWKS() JWKSet {
+    set := JWKSet{Keys: []JWK{}}
+    if k.jwk != nil {
+        set.Keys = append(set.Keys, *k.jwk)
+    }
+    return set
+}
+
+// Check memastikan key dapat menandatangani dan memverifikasi token,
+// dipakai oleh pemeriksaan readiness
+func (k *Keys) Check() error {
+    token, err := k.Sign(jwt.RegisteredClaims{Subject: "readiness"})
+    if err != nil {
+        return fmt.Errorf("failed to sign probe token: %w", err)
+    }
+    if _, err := jwt.Parse(token, k.Keyfunc, jwt.WithValidMethods(k.ValidMethods())); err != nil {
+        return fmt.Errorf("failed to verify probe token: %w", err)
+    }
+    return nil
+}
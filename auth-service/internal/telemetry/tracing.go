@@ -0,0 +1,76 @@
+package telemetry
+
+import (
+	"auth-service/internal/config"
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SetupTracing mengonfigurasi TracerProvider global dan propagator W3C
+// trace-context. Exporter OTLP membaca endpoint dan header dari environment
+// variable standar OTEL_EXPORTER_OTLP_* (misalnya OTEL_EXPORTER_OTLP_ENDPOINT).
+// Fungsi shutdown yang dikembalikan harus dipanggil saat aplikasi berhenti
+// agar span yang tersisa terkirim.
+func SetupTracing(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+    // Propagator selalu dipasang agar trace context dari upstream tetap diteruskan
+    otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+        propagation.TraceContext{},
+        propagation.Baggage{},
+    ))
+
+    if !cfg.Enabled {
+        return func(context.Context) error { return nil }, nil
+    }
+
+    var exporter sdktrace.SpanExporter
+    var err error
+    switch cfg.Exporter {
+    case "otlp":
+        exporter, err = otlptracehttp.New(ctx)
+    case "stdout":
+        exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+    default:
+        return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to create %s trace exporter: %v", cfg.Exporter, err)
+    }
+
+    res, err := resource.New(ctx,
+        resource.WithFromEnv(),
+        resource.WithTelemetrySDK(),
+        resource.WithAttributes(semconv.ServiceName(cfg.ServiceName)),
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to create trace resource: %v", err)
+    }
+
+    provider := sdktrace.NewTracerProvider(
+        sdktrace.WithBatcher(exporter),
+        sdktrace.WithResource(res),
+        sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+    )
+    otel.SetTracerProvider(provider)
+
+    return provider.Shutdown, nil
+}
+
+// EndSpan mencatat error (jika ada) ke span lalu mengakhirinya
+func EndSpan(span trace.Span, err error) {
+    if err != nil {
+        span.RecordError(err)
+        span.SetStatus(codes.Error, err.Error())
+    }
+    span.End()
+}
@@ -0,0 +1,57 @@
+package tlsauth
+
+import (
+	"auth-service/internal/config"
+	"crypto/x509"
+)
+
+// Principal identitas layanan internal yang diautentikasi lewat sertifikat klien
+type Principal struct {
+    Name string
+    Role string
+}
+
+// PrincipalMapper memetakan sertifikat klien terverifikasi ke Principal
+type PrincipalMapper struct {
+    principals []config.ServicePrincipalConfig
+}
+
+// NewPrincipalMapper membuat instance baru PrincipalMapper
+func NewPrincipalMapper(principals []config.ServicePrincipalConfig) *PrincipalMapper {
+    return &PrincipalMapper{principals: principals}
+}
+
+// Map mencari principal yang cocok dengan sertifikat. Pemetaan pertama yang
+// cocok (berdasarkan CommonName, DNS SAN atau URI SAN) yang dipakai.
+func (m *PrincipalMapper) Map(cert *x509.Certificate) (*Principal, bool) {
+    for _, p := range m.principals {
+        if matchesCertificate(p, cert) {
+            return &Principal{Name: p.Name, Role: p.Role}, true
+        }
+    }
+    return nil, false
+}
+
+// matchesCertificate memeriksa apakah sertifikat cocok dengan satu pemetaan
+func matchesCertificate(p config.ServicePrincipalConfig, cert *x509.Certificate) bool {
+    for _, cn := range p.CommonNames {
+        if cert.Subject.CommonName == cn {
+            return true
+        }
+    }
+    for _, name := range p.DNSNames {
+        for _, san := range cert.DNSNames {
+            if san == name {
+                return true
+            }
+        }
+    }
+    for _, uri := range p.URIs {
+        for _, san := range cert.URIs {
+            if san.String() == uri {
+                return true
+            }
+        }
+    }
+    return false
+}
@@ -0,0 +1,106 @@
+package tlsauth
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertReloader memuat sertifikat server dari file dan memuat ulang secara
+// otomatis ketika file cert atau key berubah, tanpa perlu restart proses
+type CertReloader struct {
+    certFile string
+    keyFile  string
+
+    mu      sync.RWMutex
+    cert    *tls.Certificate
+    modTime time.Time
+}
+
+// NewCertReloader membuat instance baru CertReloader dan langsung memuat sertifikat
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+    r := &CertReloader{certFile: certFile, keyFile: keyFile}
+    if err := r.Reload(); err != nil {
+        return nil, err
+    }
+    return r, nil
+}
+
+// Reload memuat ulang pasangan sertifikat dan key dari file
+func (r *CertReloader) Reload() error {
+    modTime, err := r.latestModTime()
+    if err != nil {
+        return err
+    }
+
+    cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+    if err != nil {
+        return fmt.Errorf("failed to load TLS key pair: %v", err)
+    }
+
+    r.mu.Lock()
+    r.cert = &cert
+    r.modTime = modTime
+    r.mu.Unlock()
+    return nil
+}
+
+// GetCertificate callback untuk tls.Config.GetCertificate
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    return r.cert, nil
+}
+
+// Run memeriksa perubahan file secara berkala sampai ctx dibatalkan.
+// Jika sertifikat baru gagal dimuat, sertifikat lama tetap dipakai.
+func (r *CertReloader) Run(ctx context.Context, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            slog.Info("TLS certificate reloader stopped")
+            return
+        case <-ticker.C:
+            modTime, err := r.latestModTime()
+            if err != nil {
+                slog.Warn("Failed to stat TLS certificate", "error", err)
+                continue
+            }
+
+            r.mu.RLock()
+            changed := modTime.After(r.modTime)
+            r.mu.RUnlock()
+            if !changed {
+                continue
+            }
+
+            if err := r.Reload(); err != nil {
+                slog.Warn("Failed to reload TLS certificate, keeping previous one", "error", err)
+                continue
+            }
+            slog.Info("TLS certificate reloaded", "cert_file", r.certFile)
+        }
+    }
+}
+
+// latestModTime mengembalikan waktu modifikasi terbaru dari file cert dan key
+func (r *CertReloader) latestModTime() (time.Time, error) {
+    var latest time.Time
+    for _, path := range []string{r.certFile, r.keyFile} {
+        info, err := os.Stat(path)
+        if err != nil {
+            return time.Time{}, fmt.Errorf("failed to stat %s: %v", path, err)
+        }
+        if info.ModTime().After(latest) {
+            latest = info.ModTime()
+        }
+    }
+    return latest, nil
+}
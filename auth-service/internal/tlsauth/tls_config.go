@@ -0,0 +1,43 @@
+package tlsauth
+
+import (
+	"auth-service/internal/config"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// NewServerTLSConfig membuat tls.Config untuk HTTP server dengan sertifikat
+// dari reloader dan mode verifikasi sertifikat klien sesuai konfigurasi
+func NewServerTLSConfig(cfg config.TLSConfig, reloader *CertReloader) (*tls.Config, error) {
+    tlsConfig := &tls.Config{
+        MinVersion:     tls.VersionTLS12,
+        GetCertificate: reloader.GetCertificate,
+    }
+
+    switch cfg.ClientAuth {
+    case "", "none":
+        tlsConfig.ClientAuth = tls.NoClientCert
+        return tlsConfig, nil
+    case "optional":
+        tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+    case "require":
+        tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+    default:
+        return nil, fmt.Errorf("unknown client auth mode %q", cfg.ClientAuth)
+    }
+
+    pem, err := os.ReadFile(cfg.ClientCAFile)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read client CA file: %v", err)
+    }
+    pool := x509.NewCertPool()
+    if !pool.AppendCertsFromPEM(pem) {
+        return nil, errors.New("client CA file contains no valid certificates")
+    }
+    tlsConfig.ClientCAs = pool
+
+    return tlsConfig, nil
+}
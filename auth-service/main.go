@@ -2,41 +2,377 @@ package main
 
 import (
 	"auth-service/internal/config"
+	"auth-service/internal/database"
+	"auth-service/internal/grpcserver"
+	"auth-service/internal/health"
+	"auth-service/internal/logging"
+	"auth-service/internal/metrics"
+	"auth-service/internal/migrations"
 	"auth-service/internal/router"
+	"auth-service/internal/server"
+	"auth-service/internal/service"
+	"auth-service/internal/signing"
+	"auth-service/internal/telemetry"
+	"auth-service/internal/tlsauth"
+	"context"
+	"crypto/tls"
 	"database/sql"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/gin-gonic/gin"
 )
 
+const usage = `Usage:
+  auth-service [serve] [flags]      menjalankan HTTP server dan server gRPC (default)
+  auth-service config print [flags] menampilkan konfigurasi efektif (secret disamarkan)
+  auth-service migrate up [flags]   menerapkan semua migration yang tertunda
+  auth-service migrate down [N] [flags]
+                                    membatalkan N migration terakhir (default 1)
+  auth-service migrate status [flags]
+                                    menampilkan status migration
+
+Jalankan "auth-service serve -h" untuk daftar flag.
+`
+
 func main() {
+    args := os.Args[1:]
+    command := "serve"
+    if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+        command, args = args[0], args[1:]
+    }
+
+    var err error
+    switch command {
+    case "serve":
+        err = runServe(args)
+    case "config":
+        err = runConfig(args)
+    case "migrate":
+        err = runMigrate(args)
+    case "help":
+        fmt.Print(usage)
+    default:
+        fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+        os.Exit(2)
+    }
+
+    if errors.Is(err, flag.ErrHelp) {
+        os.Exit(0)
+    }
+    if err != nil {
+        slog.Error("Fatal error", "error", err)
+        os.Exit(1)
+    }
+}
+
+// runServe menjalankan HTTP server
+func runServe(args []string) error {
     // Load konfigurasi
-    cfg := config.LoadConfig()
+    cfg, err := loadConfig(args)
+    if err != nil {
+        return err
+    }
+
+    // Structured logging; slog.SetDefault juga mengarahkan package log standar ke handler ini
+    logger, err := logging.New(cfg.Logging, os.Stdout)
+    if err != nil {
+        return err
+    }
+    slog.SetDefault(logger)
+    if cfg.IsProduction() {
+        gin.SetMode(gin.ReleaseMode)
+    }
+    slog.Info("Configuration loaded", "config", cfg.String())
+
+    // Tangkap SIGINT/SIGTERM untuk graceful shutdown
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
 
-    // Koneksi ke database MySQL
-    db, err := sql.Open("mysql", cfg.DBDSN)
+    db, dialect, err := openDatabase(ctx, cfg)
     if err != nil {
-        log.Fatal("Failed to connect to database:", err)
+        return err
     }
     defer db.Close()
 
-    // Test koneksi database
-    err = db.Ping()
+    migrator, err := migrations.New(db, dialect)
+    if err != nil {
+        return err
+    }
+    if cfg.Database.AutoMigrate {
+        if _, err := migrator.Up(ctx); err != nil {
+            return fmt.Errorf("failed to migrate database: %v", err)
+        }
+    }
+
+    // Key penandatangan JWT (secret HS256 atau private key RS256/EdDSA)
+    signingKeys, err := signing.New(cfg.JWT)
+    if err != nil {
+        return err
+    }
+    slog.Info("JWT signing keys loaded", "algorithm", signingKeys.Algorithm())
+
+    // Tracing OpenTelemetry; span yang tersisa dikirim saat shutdown
+    shutdownTracing, err := telemetry.SetupTracing(ctx, cfg.Tracing)
+    if err != nil {
+        return err
+    }
+    defer func() {
+        shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        if err := shutdownTracing(shutdownCtx); err != nil {
+            slog.Error("Failed to flush traces", "error", err)
+        }
+    }()
+
+    // Background workers dijalankan dengan context sendiri agar tetap hidup
+    // selama server masih menyelesaikan request yang sedang berjalan
+    workerCtx, stopWorkers := context.WithCancel(context.Background())
+    var workers sync.WaitGroup
+
+    tokenBlacklist := service.NewTokenBlacklist()
+    workers.Add(1)
+    go func() {
+        defer workers.Done()
+        tokenBlacklist.Run(workerCtx, time.Minute)
+    }()
+
+    // TLS in-process dengan hot reload sertifikat
+    var tlsConfig *tls.Config
+    if cfg.TLS.Enabled {
+        reloader, err := tlsauth.NewCertReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+        if err != nil {
+            stopWorkers()
+            return err
+        }
+        tlsConfig, err = tlsauth.NewServerTLSConfig(cfg.TLS, reloader)
+        if err != nil {
+            stopWorkers()
+            return err
+        }
+
+        workers.Add(1)
+        go func() {
+            defer workers.Done()
+            reloader.Run(workerCtx, cfg.TLS.ReloadInterval)
+        }()
+    }
+
+    // Pemeriksaan readiness untuk /readyz
+    healthChecker := health.NewChecker(cfg.Server.HealthCheckTimeout)
+    healthChecker.Add("database", db.PingContext)
+    healthChecker.Add("migrations", migrator.CheckCurrent)
+    healthChecker.Add("signing_keys", func(ctx context.Context) error {
+        return signingKeys.Check()
+    })
+
+    // Readiness langsung gagal begitu sinyal shutdown diterima, sebelum drain period
+    go func() {
+        <-ctx.Done()
+        healthChecker.SetShuttingDown()
+        slog.Info("Readiness set to failing for shutdown")
+    }()
+
+    // Setup router; AuthService yang sama dipakai oleh server gRPC
+    deps := router.NewDependencies(db, cfg, tokenBlacklist, healthChecker, signingKeys)
+    r := router.New(cfg, deps)
+
+    // Endpoint metrik Prometheus, di port API atau di port terpisah
+    var metricsServer *server.Server
+    if cfg.Metrics.Enabled {
+        metricsHandler := metrics.Handler(metrics.NewRegistry(db))
+        if cfg.Metrics.Port == "" {
+            r.GET(cfg.Metrics.Path, gin.WrapH(metricsHandler))
+        } else {
+            mux := http.NewServeMux()
+            mux.Handle(cfg.Metrics.Path, metricsHandler)
+
+            metricsServerConfig := cfg.Server
+            metricsServerConfig.Port = cfg.Metrics.Port
+            metricsServer = server.New(metricsServerConfig, mux, nil)
+        }
+    }
+
+    var servers sync.WaitGroup
+    if cfg.GRPC.Enabled {
+        grpcServer := grpcserver.New(cfg, deps.AuthService, tlsConfig)
+        servers.Add(1)
+        go func() {
+            defer servers.Done()
+            if err := grpcServer.Run(ctx); err != nil {
+                slog.Error("gRPC server error", "error", err)
+            }
+        }()
+    }
+    if metricsServer != nil {
+        servers.Add(1)
+        go func() {
+            defer servers.Done()
+            if err := metricsServer.Run(ctx); err != nil {
+                slog.Error("Metrics server error", "error", err)
+            }
+        }()
+    }
+
+    // Jalankan server sampai menerima sinyal shutdown
+    srv := server.New(cfg.Server, r, tlsConfig)
+    err = srv.Run(ctx)
+    stop()
+    servers.Wait()
+
+    // Hentikan background workers sebelum pool database ditutup
+    stopWorkers()
+    workers.Wait()
+    slog.Info("Background workers stopped, closing database")
+
+    return err
+}
+
+// runMigrate menangani subcommand "migrate"
+func runMigrate(args []string) error {
+    if len(args) == 0 {
+        fmt.Fprint(os.Stderr, usage)
+        os.Exit(2)
+    }
+    action, args := args[0], args[1:]
+
+    steps := 1
+    switch action {
+    case "up", "status":
+    case "down":
+        if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+            n, err := strconv.Atoi(args[0])
+            if err != nil || n < 1 {
+                return fmt.Errorf("invalid number of migrations to roll back: %q", args[0])
+            }
+            steps, args = n, args[1:]
+        }
+    default:
+        fmt.Fprintf(os.Stderr, "unknown migrate command %q\n\n%s", action, usage)
+        os.Exit(2)
+    }
+
+    cfg, err := loadConfig(args)
+    if err != nil {
+        return err
+    }
+    logger, err := logging.New(cfg.Logging, os.Stderr)
+    if err != nil {
+        return err
+    }
+    slog.SetDefault(logger)
+
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+
+    db, dialect, err := openDatabase(ctx, cfg)
+    if err != nil {
+        return err
+    }
+    defer db.Close()
+
+    migrator, err := migrations.New(db, dialect)
     if err != nil {
-        log.Fatal("Failed to ping database:", err)
+        return err
     }
-    log.Println("Connected to database successfully")
+
+    switch action {
+    case "up":
+        n, err := migrator.Up(ctx)
+        if err != nil {
+            return err
+        }
+        fmt.Printf("Applied %d migration(s)\n", n)
+    case "down":
+        n, err := migrator.Down(ctx, steps)
+        if err != nil {
+            return err
+        }
+        fmt.Printf("Rolled back %d migration(s)\n", n)
+    case "status":
+        statuses, err := migrator.Status(ctx)
+        if err != nil {
+            return err
+        }
+        w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+        fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+        for _, s := range statuses {
+            state, appliedAt := "pending", "-"
+            if s.Applied {
+                state, appliedAt = "applied", s.AppliedAt.Format(time.RFC3339)
+            }
+            fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+        }
+        return w.Flush()
+    }
+    return nil
+}
+
+// openDatabase membuka koneksi database sesuai database.driver, memastikan
+// database dapat dijangkau dan mengatur connection pool
+func openDatabase(ctx context.Context, cfg *config.Config) (*sql.DB, database.Dialect, error) {
+    dialect, err := database.DialectFor(cfg.Database.Driver)
+    if err != nil {
+        return nil, dialect, err
+    }
+
+    db, err := sql.Open(dialect.DriverName, cfg.Database.DSN)
+    if err != nil {
+        return nil, dialect, fmt.Errorf("failed to connect to database: %v", err)
+    }
+
+    // Test koneksi database
+    pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+    if err := db.PingContext(pingCtx); err != nil {
+        db.Close()
+        return nil, dialect, fmt.Errorf("failed to ping database: %v", err)
+    }
+    slog.Info("Connected to database successfully", "driver", dialect.Name)
 
     // Set pool connections
-    db.SetMaxOpenConns(25)
-    db.SetMaxIdleConns(25)
-    db.SetConnMaxLifetime(5 * time.Minute)
+    db.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+    db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+    db.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+    return db, dialect, nil
+}
+
+// runConfig menangani subcommand "config"
+func runConfig(args []string) error {
+    if len(args) == 0 || args[0] != "print" {
+        fmt.Fprint(os.Stderr, usage)
+        os.Exit(2)
+    }
 
-    // Setup router
-    r := router.SetupRouter(db, cfg.JWTSecret, cfg.JWTIssuer, cfg.JWTExpiration)
+    cfg, err := loadConfig(args[1:])
+    if err != nil {
+        return err
+    }
 
-    // Jalankan server
-    log.Printf("Server starting on port %s", cfg.AppPort)
-    log.Fatal(r.Run(":" + cfg.AppPort))
-}
\ No newline at end of file
+    out, err := cfg.YAML()
+    if err != nil {
+        return err
+    }
+    fmt.Print(out)
+    return nil
+}
+
+// loadConfig memuat konfigurasi dan membungkus error validasi
+func loadConfig(args []string) (*config.Config, error) {
+    cfg, err := config.LoadConfig(args)
+    if err != nil && !errors.Is(err, flag.ErrHelp) {
+        return nil, fmt.Errorf("invalid configuration:\n%v", err)
+    }
+    return cfg, err
+}
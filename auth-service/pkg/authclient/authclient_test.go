@@ -0,0 +1,416 @@
+package authclient_test
+
+import (
+	"auth-service/internal/config"
+	"auth-service/internal/health"
+	"auth-service/internal/repository"
+	"auth-service/internal/router"
+	"auth-service/internal/service"
+	"auth-service/internal/signing"
+	"auth-service/pkg/authclient"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	os.Exit(m.Run())
+}
+
+// newServer menjalankan auth-service dengan repository di memori
+func newServer(t *testing.T, keys *signing.Keys, configure ...func(*config.Config)) (*httptest.Server, *config.Config) {
+	t.Helper()
+
+	cfg := config.Default()
+	cfg.Env = "test"
+	cfg.JWT.Secret = "test-secret-that-is-long-enough-for-hs256"
+	for _, fn := range configure {
+		fn(cfg)
+	}
+
+	server := httptest.NewServer(router.New(cfg, router.Dependencies{
+		UserRepo:           repository.NewMemoryUserRepository(),
+		AuditRepo:          repository.NewMemoryAuditRepository(),
+		APIKeyRepo:         repository.NewMemoryAPIKeyRepository(),
+		ServiceAccountRepo: repository.NewMemoryServiceAccountRepository(),
+		TokenBlacklist:     service.NewTokenBlacklist(),
+		HealthChecker:      health.NewChecker(time.Second),
+		SigningKeys:        keys,
+	}))
+	t.Cleanup(server.Close)
+	return server, cfg
+}
+
+// ed25519Keys membuat Keys EdDSA dari private key baru
+func ed25519Keys(t *testing.T) *signing.Keys {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := signing.NewFromPEM("EdDSA", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func TestClient(t *testing.T) {
+	keys, err := signing.NewHMAC("test-secret-that-is-long-enough-for-hs256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, cfg := newServer(t, keys)
+	ctx := context.Background()
+
+	client, err := authclient.New(server.URL, authclient.WithLanguage("id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := client.Register(ctx, authclient.RegisterRequest{Name: "Alice", Email: "alice@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if session.Token == "" || session.User.Email != "alice@example.com" || session.Message != "Pengguna berhasil didaftarkan" {
+		t.Errorf("session = %+v", session)
+	}
+
+	_, err = client.Register(ctx, authclient.RegisterRequest{Name: "Alice", Email: "alice@example.com", Password: "secret123"})
+	var apiErr *authclient.APIError
+	if !errors.Is(err, authclient.ErrEmailTaken) || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("duplicate Register error = %v, want ErrEmailTaken (409)", err)
+	}
+
+	if _, err := client.Login(ctx, "alice@example.com", "wrong-password"); !errors.Is(err, authclient.ErrInvalidCredentials) {
+		t.Errorf("Login error = %v, want ErrInvalidCredentials", err)
+	}
+
+	validation, err := client.Validate(ctx, session.Token)
+	if err != nil || !validation.Valid || validation.User.ID != session.User.ID {
+		t.Errorf("Validate = %+v, %v", validation, err)
+	}
+
+	// Verifier lokal HS256 menerima token yang sama
+	claims, err := authclient.NewHMACVerifier(cfg.JWT.Secret, cfg.JWT.Issuer).Verify(ctx, session.Token)
+	if err != nil || claims.UserID != session.User.ID {
+		t.Errorf("Verify = %+v, %v", claims, err)
+	}
+	if _, err := authclient.NewHMACVerifier("other-secret", cfg.JWT.Issuer).Verify(ctx, session.Token); !errors.Is(err, authclient.ErrTokenInvalid) {
+		t.Errorf("Verify with wrong secret error = %v, want ErrTokenInvalid", err)
+	}
+
+	// API key dipakai sebagai pengganti token lewat APIKeyToken
+	created, err := client.CreateAPIKey(ctx, session.Token, authclient.CreateAPIKeyRequest{Name: "script"})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	validation, err = client.Validate(ctx, authclient.APIKeyToken(created.Key))
+	if err != nil || validation.AuthMethod != "api_key" || validation.User.ID != session.User.ID {
+		t.Errorf("Validate with API key = %+v, %v", validation, err)
+	}
+	if err := client.DeleteAPIKey(ctx, session.Token, created.ID); err != nil {
+		t.Fatalf("DeleteAPIKey: %v", err)
+	}
+	if err := client.DeleteAPIKey(ctx, session.Token, created.ID); !errors.Is(err, authclient.ErrAPIKeyNotFound) {
+		t.Errorf("second DeleteAPIKey error = %v, want ErrAPIKeyNotFound", err)
+	}
+
+	if err := client.Logout(ctx, session.Token); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := client.Validate(ctx, session.Token); !errors.Is(err, authclient.ErrTokenRevoked) {
+		t.Errorf("Validate after logout error = %v, want ErrTokenRevoked", err)
+	}
+
+	if _, err := client.Readyz(ctx); err != nil {
+		t.Errorf("Readyz: %v", err)
+	}
+}
+
+func TestServiceAccountClient(t *testing.T) {
+	keys, err := signing.NewHMAC("test-secret-that-is-long-enough-for-hs256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, cfg := newServer(t, keys)
+	ctx := context.Background()
+
+	client, err := authclient.New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := client.Register(ctx, authclient.RegisterRequest{Name: "Root", Email: "root@example.com", Password: "secret123", Role: "admin"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	account, err := client.CreateServiceAccount(ctx, admin.Token, authclient.CreateServiceAccountRequest{Name: "billing", Role: "user"})
+	if err != nil {
+		t.Fatalf("CreateServiceAccount: %v", err)
+	}
+	if _, err := client.CreateServiceAccount(ctx, admin.Token, authclient.CreateServiceAccountRequest{Name: "billing", Role: "user"}); !errors.Is(err, authclient.ErrServiceAccountNameTaken) {
+		t.Errorf("duplicate CreateServiceAccount error = %v, want ErrServiceAccountNameTaken", err)
+	}
+
+	token, err := client.ClientCredentialsToken(ctx, account.ClientID, account.ClientSecret)
+	if err != nil || token.TokenType != "Bearer" || token.AccessToken == "" {
+		t.Fatalf("ClientCredentialsToken = %+v, %v", token, err)
+	}
+	if _, err := client.ClientCredentialsToken(ctx, account.ClientID, "wrong-secret"); !errors.Is(err, authclient.ErrInvalidClient) {
+		t.Errorf("ClientCredentialsToken with wrong secret error = %v, want ErrInvalidClient", err)
+	}
+
+	validation, err := client.Validate(ctx, token.AccessToken)
+	if err != nil || validation.PrincipalType != "service" || validation.ServiceAccountID != account.ID {
+		t.Errorf("Validate = %+v, %v", validation, err)
+	}
+	claims, err := authclient.NewHMACVerifier(cfg.JWT.Secret, cfg.JWT.Issuer).Verify(ctx, token.AccessToken)
+	if err != nil || !claims.IsService() {
+		t.Errorf("Verify = %+v, %v", claims, err)
+	}
+
+	disabled := true
+	if _, err := client.UpdateServiceAccount(ctx, admin.Token, account.ID, authclient.UpdateServiceAccountRequest{Disabled: &disabled}); err != nil {
+		t.Fatalf("UpdateServiceAccount: %v", err)
+	}
+	if _, err := client.ClientCredentialsToken(ctx, account.ClientID, account.ClientSecret); !errors.Is(err, authclient.ErrInvalidClient) {
+		t.Errorf("ClientCredentialsToken for disabled account error = %v, want ErrInvalidClient", err)
+	}
+
+	if err := client.DeleteServiceAccount(ctx, admin.Token, account.ID); err != nil {
+		t.Fatalf("DeleteServiceAccount: %v", err)
+	}
+	if _, err := client.GetServiceAccount(ctx, admin.Token, account.ID); !errors.Is(err, authclient.ErrServiceAccountNotFound) {
+		t.Errorf("GetServiceAccount after delete error = %v, want ErrServiceAccountNotFound", err)
+	}
+}
+
+func TestImpersonationClient(t *testing.T) {
+	keys, err := signing.NewHMAC("test-secret-that-is-long-enough-for-hs256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, _ := newServer(t, keys)
+	ctx := context.Background()
+
+	client, err := authclient.New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := client.Register(ctx, authclient.RegisterRequest{Name: "Root", Email: "root@example.com", Password: "secret123", Role: "admin"})
+	if err != nil {
+		t.Fatalf("Register admin: %v", err)
+	}
+	user, err := client.Register(ctx, authclient.RegisterRequest{Name: "Uma", Email: "uma@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Register user: %v", err)
+	}
+
+	session, err := client.Impersonate(ctx, admin.Token, user.User.ID)
+	if err != nil {
+		t.Fatalf("Impersonate: %v", err)
+	}
+	validation, err := client.Validate(ctx, session.Token)
+	if err != nil || !validation.Impersonated || validation.Actor.UserID != admin.User.ID || validation.User.ID != user.User.ID {
+		t.Errorf("Validate = %+v, %v", validation, err)
+	}
+	if _, err := client.CreateAPIKey(ctx, session.Token, authclient.CreateAPIKeyRequest{Name: "x"}); !errors.Is(err, authclient.ErrImpersonationRestricted) {
+		t.Errorf("CreateAPIKey while impersonating error = %v, want ErrImpersonationRestricted", err)
+	}
+
+	if err := client.StopImpersonation(ctx, session.Token); err != nil {
+		t.Fatalf("StopImpersonation: %v", err)
+	}
+	if _, err := client.Validate(ctx, session.Token); !errors.Is(err, authclient.ErrTokenRevoked) {
+		t.Errorf("Validate after stop error = %v, want ErrTokenRevoked", err)
+	}
+}
+
+func TestTokenExchangeClient(t *testing.T) {
+	keys, err := signing.NewHMAC("test-secret-that-is-long-enough-for-hs256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, cfg := newServer(t, keys, func(cfg *config.Config) {
+		cfg.TokenExchange.Policies = []config.TokenExchangePolicy{{Client: "gateway", Audiences: []string{"orders-api"}}}
+	})
+	ctx := context.Background()
+
+	client, err := authclient.New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := client.Register(ctx, authclient.RegisterRequest{Name: "Root", Email: "root@example.com", Password: "secret123", Role: "admin"})
+	if err != nil {
+		t.Fatalf("Register admin: %v", err)
+	}
+	user, err := client.Register(ctx, authclient.RegisterRequest{Name: "Vic", Email: "vic@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Register user: %v", err)
+	}
+	account, err := client.CreateServiceAccount(ctx, admin.Token, authclient.CreateServiceAccountRequest{Name: "gateway", Role: "user"})
+	if err != nil {
+		t.Fatalf("CreateServiceAccount: %v", err)
+	}
+
+	token, err := client.ExchangeToken(ctx, account.ClientID, account.ClientSecret, authclient.TokenExchangeRequest{
+		SubjectToken: user.Token,
+		Audience:     []string{"orders-api"},
+	})
+	if err != nil || token.IssuedTokenType != "urn:ietf:params:oauth:token-type:access_token" {
+		t.Fatalf("ExchangeToken = %+v, %v", token, err)
+	}
+	if _, err := client.ExchangeToken(ctx, account.ClientID, account.ClientSecret, authclient.TokenExchangeRequest{
+		SubjectToken: user.Token,
+		Audience:     []string{"billing-api"},
+	}); !errors.Is(err, authclient.ErrInvalidTarget) {
+		t.Errorf("ExchangeToken for unknown audience error = %v, want ErrInvalidTarget", err)
+	}
+
+	claims, err := authclient.NewHMACVerifier(cfg.JWT.Secret, cfg.JWT.Issuer, authclient.WithAudience("orders-api")).Verify(ctx, token.AccessToken)
+	if err != nil || claims.UserID != user.User.ID {
+		t.Errorf("Verify = %+v, %v", claims, err)
+	}
+	if _, err := authclient.NewHMACVerifier(cfg.JWT.Secret, cfg.JWT.Issuer, authclient.WithAudience("orders-api")).Verify(ctx, user.Token); !errors.Is(err, authclient.ErrTokenInvalid) {
+		t.Errorf("Verify without audience error = %v, want ErrTokenInvalid", err)
+	}
+	if _, err := authclient.NewHMACVerifier(cfg.JWT.Secret, cfg.JWT.Issuer, authclient.WithAudience("billing-api")).Verify(ctx, token.AccessToken); !errors.Is(err, authclient.ErrTokenInvalid) {
+		t.Errorf("Verify for another audience error = %v, want ErrTokenInvalid", err)
+	}
+	if _, err := authclient.NewHMACVerifier(cfg.JWT.Secret, cfg.JWT.Issuer).Verify(ctx, token.AccessToken); !errors.Is(err, authclient.ErrTokenInvalid) {
+		t.Errorf("Verify audience token without WithAudience error = %v, want ErrTokenInvalid", err)
+	}
+}
+
+func TestJWKSVerifierAndMiddleware(t *testing.T) {
+	server, cfg := newServer(t, ed25519Keys(t))
+	ctx := context.Background()
+
+	client, err := authclient.New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Register(ctx, authclient.RegisterRequest{Name: "Bob", Email: "bob@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	set, err := client.JWKS(ctx)
+	if err != nil || len(set.Keys) != 1 || set.Keys[0].Alg != "EdDSA" {
+		t.Fatalf("JWKS = %+v, %v", set, err)
+	}
+
+	verifier := authclient.NewJWKSVerifier(server.URL+"/.well-known/jwks.json", cfg.JWT.Issuer)
+	handler := authclient.Middleware(verifier)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := authclient.ClaimsFromContext(r.Context())
+		if !ok || claims.Email != "bob@example.com" {
+			t.Errorf("claims = %+v", claims)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"valid token", "Bearer " + session.Token, http.StatusNoContent},
+		{"missing token", "", http.StatusUnauthorized},
+		{"token signed by another key", "Bearer " + signedByOtherKey(t), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestJWKSVerifierSingleFlight(t *testing.T) {
+	server, cfg := newServer(t, ed25519Keys(t))
+	ctx := context.Background()
+
+	client, err := authclient.New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Register(ctx, authclient.RegisterRequest{Name: "Bob", Email: "bob@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	// Endpoint JWKS lambat agar request verifikasi saling tumpang tindih
+	var fetches atomic.Int32
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		resp, err := http.Get(server.URL + "/.well-known/jwks.json")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	t.Cleanup(jwks.Close)
+
+	verifier := authclient.NewJWKSVerifier(jwks.URL, cfg.JWT.Issuer)
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := verifier.Verify(ctx, session.Token); err != nil {
+				t.Errorf("Verify: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", n)
+	}
+}
+
+// signedByOtherKey membuat token dengan issuer yang benar tetapi key lain
+func signedByOtherKey(t *testing.T) string {
+	t.Helper()
+	server, _ := newServer(t, ed25519Keys(t))
+	client, err := authclient.New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Register(context.Background(), authclient.RegisterRequest{Name: "Eve", Email: "eve@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return session.Token
+}
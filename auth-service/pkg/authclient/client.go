@@ -0,0 +1,627 @@
+// Package authclient adalah SDK Go untuk layanan lain yang memakai
+// auth-service: client bertipe untuk semua endpoint, verifier token lokal
+// (HS256 dengan secret bersama atau RS256/EdDSA lewat JWKS) serta middleware
+// net/http dan Gin yang menyimpan claims pemanggil di context request.
+package authclient
+
+import (
+	"auth-service/internal/health"
+	"auth-service/internal/model"
+	"auth-service/internal/signing"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Tipe yang dipakai bersama dengan auth-service agar bentuk request dan
+// response selalu sama dengan server
+type (
+    Claims                      = model.JWTClaims
+    Actor                       = model.ActorClaim
+    User                        = model.UserResponse
+    RegisterRequest             = model.UserRegisterRequest
+    AuditEvent                  = model.AuditEvent
+    AuditEventFilter            = model.AuditEventFilter
+    CreateAPIKeyRequest         = model.CreateAPIKeyRequest
+    APIKey                      = model.APIKeyResponse
+    CreatedAPIKey               = model.CreatedAPIKeyResponse
+    ServiceAccount              = model.ServiceAccountResponse
+    ServiceAccountCredentials   = model.ServiceAccountCredentialsResponse
+    CreateServiceAccountRequest = model.CreateServiceAccountRequest
+    UpdateServiceAccountRequest = model.UpdateServiceAccountRequest
+    TokenResponse               = model.TokenResponse
+    TokenExchangeRequest        = model.TokenExchangeRequest
+    Problem                     = model.Problem
+    HealthReport                = health.Report
+    JWKSet                      = signing.JWKSet
+)
+
+// DefaultCookieName nama cookie JWT default auth-service (cookies.name)
+const DefaultCookieName = "jwt"
+
+// ErrNotReady dikembalikan Readyz jika /readyz merespons 503
+var ErrNotReady = errors.New("auth-service is not ready")
+
+// Session hasil register, login atau perubahan locale: token baru dan profil pengguna
+type Session struct {
+    Token   string
+    Message string
+    User    *User
+}
+
+// Validation response /api/validate. PrincipalType membedakan pengguna
+// ("user") dari principal layanan ("service"). Untuk pengguna field User,
+// Issuer, IssuedAt dan ExpiresAt terisi (ditambah AuthMethod dan Scopes untuk
+// API key); untuk principal layanan field Principal dan Role, ditambah
+// ServiceAccountID untuk service account dan AuthMethod untuk mTLS. Token
+// impersonation ditandai dengan Impersonated dan token delegasi dengan
+// Delegated; keduanya membawa Actor (rantai claim act). Token hasil token
+// exchange juga membawa Audience dan Scopes.
+type Validation struct {
+    Valid            bool             `json:"valid"`
+    PrincipalType    string           `json:"principalType,omitempty"`
+    User             *User            `json:"user,omitempty"`
+    Issuer           string           `json:"issuer,omitempty"`
+    IssuedAt         *jwt.NumericDate `json:"issuedAt,omitempty"`
+    ExpiresAt        *jwt.NumericDate `json:"expiresAt,omitempty"`
+    Principal        string           `json:"principal,omitempty"`
+    ServiceAccountID int64            `json:"serviceAccountId,omitempty"`
+    Role             string           `json:"role,omitempty"`
+    AuthMethod       string           `json:"authMethod,omitempty"`
+    Scopes           []string         `json:"scopes,omitempty"`
+    Impersonated     bool             `json:"impersonated,omitempty"`
+    Delegated        bool             `json:"delegated,omitempty"`
+    Actor            *Actor           `json:"actor,omitempty"`
+    Audience         []string         `json:"audience,omitempty"`
+}
+
+// AuditEventPage satu halaman hasil /api/admin/audit-events
+type AuditEventPage struct {
+    Events []AuditEvent `json:"events"`
+    Limit  int          `json:"limit"`
+    Offset int          `json:"offset"`
+}
+
+// APIKeyToken mengubah API key menjadi nilai yang dapat dipakai sebagai
+// parameter token pada method Client, sehingga dikirim sebagai
+// "Authorization: ApiKey <key>" alih-alih Bearer
+func APIKeyToken(key string) string {
+    return model.APIKeyScheme + " " + key
+}
+
+// Client client HTTP bertipe untuk auth-service. Token dikirim lewat header
+// Authorization, kecuali Logout yang membutuhkan cookie.
+type Client struct {
+    baseURL    *url.URL
+    httpClient *http.Client
+    cookieName string
+    language   string
+}
+
+// Option mengatur Client
+type Option func(*Client)
+
+// WithHTTPClient memakai http.Client sendiri (misalnya dengan sertifikat mTLS)
+func WithHTTPClient(httpClient *http.Client) Option {
+    return func(c *Client) {
+        c.httpClient = httpClient
+    }
+}
+
+// WithCookieName mengganti nama cookie JWT jika cookies.name server diubah
+func WithCookieName(name string) Option {
+    return func(c *Client) {
+        c.cookieName = name
+    }
+}
+
+// WithLanguage mengirim Accept-Language (misalnya "id") pada setiap request
+func WithLanguage(language string) Option {
+    return func(c *Client) {
+        c.language = language
+    }
+}
+
+// New membuat Client untuk auth-service di baseURL (misalnya "https://auth.internal")
+func New(baseURL string, opts ...Option) (*Client, error) {
+    u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+    if err != nil {
+        return nil, fmt.Errorf("invalid base URL: %v", err)
+    }
+    if u.Scheme != "http" && u.Scheme != "https" {
+        return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+    }
+
+    c := &Client{
+        baseURL:    u,
+        httpClient: &http.Client{Timeout: 10 * time.Second},
+        cookieName: DefaultCookieName,
+    }
+    for _, opt := range opts {
+        opt(c)
+    }
+    return c, nil
+}
+
+// Register mendaftarkan pengguna baru dan mengembalikan session-nya
+func (c *Client) Register(ctx context.Context, req RegisterRequest) (*Session, error) {
+    return c.session(ctx, http.MethodPost, "/api/register", "", req)
+}
+
+// Login mengautentikasi pengguna dengan email dan password
+func (c *Client) Login(ctx context.Context, email, password string) (*Session, error) {
+    return c.session(ctx, http.MethodPost, "/api/login", "", model.UserLoginRequest{Email: email, Password: password})
+}
+
+// Logout mencabut token
+func (c *Client) Logout(ctx context.Context, token string) error {
+    req, err := c.newRequest(ctx, http.MethodPost, "/api/logout", "", nil)
+    if err != nil {
+        return err
+    }
+    req.AddCookie(&http.Cookie{Name: c.cookieName, Value: token})
+
+    resp, err := c.send(req)
+    if err != nil {
+        return err
+    }
+    resp.Body.Close()
+    return nil
+}
+
+// Validate memvalidasi token di server, termasuk pemeriksaan token yang
+// sudah dicabut (yang tidak dapat dilakukan Verifier lokal). Token kosong
+// berarti autentikasi memakai sertifikat klien dari WithHTTPClient.
+func (c *Client) Validate(ctx context.Context, token string) (*Validation, error) {
+    var validation Validation
+    if err := c.doJSON(ctx, http.MethodGet, "/api/validate", token, nil, &validation); err != nil {
+        return nil, err
+    }
+    return &validation, nil
+}
+
+// UpdateLocale mengubah preferensi bahasa pengguna; session berisi token baru
+func (c *Client) UpdateLocale(ctx context.Context, token, locale string) (*Session, error) {
+    return c.session(ctx, http.MethodPut, "/api/locale", token, model.UpdateLocaleRequest{Locale: locale})
+}
+
+// UpdateUserRole mengubah role pengguna (admin)
+func (c *Client) UpdateUserRole(ctx context.Context, token string, userID int64, role string) (*User, error) {
+    var body struct {
+        User *User `json:"user"`
+    }
+    path := "/api/admin/users/" + strconv.FormatInt(userID, 10) + "/role"
+    if err := c.doJSON(ctx, http.MethodPatch, path, token, model.UpdateRoleRequest{Role: role}, &body); err != nil {
+        return nil, err
+    }
+    return body.User, nil
+}
+
+// ListAuditEvents mengambil satu halaman event audit (admin)
+func (c *Client) ListAuditEvents(ctx context.Context, token string, filter AuditEventFilter) (*AuditEventPage, error) {
+    var page AuditEventPage
+    path := "/api/admin/audit-events" + auditQuery(filter, true)
+    if err := c.doJSON(ctx, http.MethodGet, path, token, nil, &page); err != nil {
+        return nil, err
+    }
+    return &page, nil
+}
+
+// ExportAuditEvents mengalirkan semua event audit yang cocok dengan filter
+// ke fn (admin). Limit dan Offset pada filter diabaikan.
+func (c *Client) ExportAuditEvents(ctx context.Context, token string, filter AuditEventFilter, fn func(event *AuditEvent) error) error {
+    req, err := c.newRequest(ctx, http.MethodGet, "/api/admin/audit-events/export"+auditQuery(filter, false), token, nil)
+    if err != nil {
+        return err
+    }
+    resp, err := c.send(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    scanner := bufio.NewScanner(resp.Body)
+    scanner.Buffer(make([]byte, 64*1024), 1<<20)
+    for scanner.Scan() {
+        if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+            continue
+        }
+        var event AuditEvent
+        if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+            return fmt.Errorf("failed to decode audit event: %v", err)
+        }
+        if err := fn(&event); err != nil {
+            return err
+        }
+    }
+    return scanner.Err()
+}
+
+// Impersonate memulai impersonation pengguna userID oleh admin pemilik token.
+// Session.Token adalah token impersonation berumur pendek.
+func (c *Client) Impersonate(ctx context.Context, token string, userID int64) (*Session, error) {
+    return c.session(ctx, http.MethodPost, "/api/admin/users/"+strconv.FormatInt(userID, 10)+"/impersonate", token, nil)
+}
+
+// StopImpersonation mengakhiri impersonation dengan mencabut token impersonation
+func (c *Client) StopImpersonation(ctx context.Context, impersonationToken string) error {
+    return c.doJSON(ctx, http.MethodPost, "/api/impersonation/stop", impersonationToken, nil, nil)
+}
+
+// CreateAPIKey membuat API key untuk pengguna pemilik token. Key lengkap
+// (CreatedAPIKey.Key) hanya dikembalikan sekali.
+func (c *Client) CreateAPIKey(ctx context.Context, token string, req CreateAPIKeyRequest) (*CreatedAPIKey, error) {
+    var body struct {
+        APIKey *CreatedAPIKey `json:"api_key"`
+    }
+    if err := c.doJSON(ctx, http.MethodPost, "/api/api-keys", token, req, &body); err != nil {
+        return nil, err
+    }
+    return body.APIKey, nil
+}
+
+// ListAPIKeys mengambil API key milik pengguna pemilik token (tanpa secret)
+func (c *Client) ListAPIKeys(ctx context.Context, token string) ([]APIKey, error) {
+    var body struct {
+        APIKeys []APIKey `json:"api_keys"`
+    }
+    if err := c.doJSON(ctx, http.MethodGet, "/api/api-keys", token, nil, &body); err != nil {
+        return nil, err
+    }
+    return body.APIKeys, nil
+}
+
+// DeleteAPIKey mencabut API key milik pengguna pemilik token
+func (c *Client) DeleteAPIKey(ctx context.Context, token string, id int64) error {
+    return c.doJSON(ctx, http.MethodDelete, "/api/api-keys/"+strconv.FormatInt(id, 10), token, nil, nil)
+}
+
+// CreateServiceAccount membuat service account (admin). Client secret
+// (ServiceAccountCredentials.ClientSecret) hanya dikembalikan sekali.
+func (c *Client) CreateServiceAccount(ctx context.Context, token string, req CreateServiceAccountRequest) (*ServiceAccountCredentials, error) {
+    var body struct {
+        ServiceAccount *ServiceAccountCredentials `json:"service_account"`
+    }
+    if err := c.doJSON(ctx, http.MethodPost, "/api/admin/service-accounts", token, req, &body); err != nil {
+        return nil, err
+    }
+    return body.ServiceAccount, nil
+}
+
+// ListServiceAccounts mengambil semua service account (admin)
+func (c *Client) ListServiceAccounts(ctx context.Context, token string) ([]ServiceAccount, error) {
+    var body struct {
+        ServiceAccounts []ServiceAccount `json:"service_accounts"`
+    }
+    if err := c.doJSON(ctx, http.MethodGet, "/api/admin/service-accounts", token, nil, &body); err != nil {
+        return nil, err
+    }
+    return body.ServiceAccounts, nil
+}
+
+// GetServiceAccount mengambil satu service account (admin)
+func (c *Client) GetServiceAccount(ctx context.Context, token string, id int64) (*ServiceAccount, error) {
+    var body struct {
+        ServiceAccount *ServiceAccount `json:"service_account"`
+    }
+    if err := c.doJSON(ctx, http.MethodGet, serviceAccountPath(id), token, nil, &body); err != nil {
+        return nil, err
+    }
+    return body.ServiceAccount, nil
+}
+
+// UpdateServiceAccount mengubah description, role atau status disabled
+// service account (admin); field nil tidak diubah
+func (c *Client) UpdateServiceAccount(ctx context.Context, token string, id int64, req UpdateServiceAccountRequest) (*ServiceAccount, error) {
+    var body struct {
+        ServiceAccount *ServiceAccount `json:"service_account"`
+    }
+    if err := c.doJSON(ctx, http.MethodPatch, serviceAccountPath(id), token, req, &body); err != nil {
+        return nil, err
+    }
+    return body.ServiceAccount, nil
+}
+
+// DeleteServiceAccount menghapus service account beserta API key-nya (admin)
+func (c *Client) DeleteServiceAccount(ctx context.Context, token string, id int64) error {
+    return c.doJSON(ctx, http.MethodDelete, serviceAccountPath(id), token, nil, nil)
+}
+
+// RotateServiceAccountSecret mengganti client secret service account (admin)
+func (c *Client) RotateServiceAccountSecret(ctx context.Context, token string, id int64) (*ServiceAccountCredentials, error) {
+    var body struct {
+        ServiceAccount *ServiceAccountCredentials `json:"service_account"`
+    }
+    if err := c.doJSON(ctx, http.MethodPost, serviceAccountPath(id)+"/secret", token, nil, &body); err != nil {
+        return nil, err
+    }
+    return body.ServiceAccount, nil
+}
+
+// CreateServiceAccountAPIKey membuat API key milik service account (admin)
+func (c *Client) CreateServiceAccountAPIKey(ctx context.Context, token string, id int64, req CreateAPIKeyRequest) (*CreatedAPIKey, error) {
+    var body struct {
+        APIKey *CreatedAPIKey `json:"api_key"`
+    }
+    if err := c.doJSON(ctx, http.MethodPost, serviceAccountPath(id)+"/api-keys", token, req, &body); err != nil {
+        return nil, err
+    }
+    return body.APIKey, nil
+}
+
+// ListServiceAccountAPIKeys mengambil API key milik service account (admin)
+func (c *Client) ListServiceAccountAPIKeys(ctx context.Context, token string, id int64) ([]APIKey, error) {
+    var body struct {
+        APIKeys []APIKey `json:"api_keys"`
+    }
+    if err := c.doJSON(ctx, http.MethodGet, serviceAccountPath(id)+"/api-keys", token, nil, &body); err != nil {
+        return nil, err
+    }
+    return body.APIKeys, nil
+}
+
+// DeleteServiceAccountAPIKey mencabut API key milik service account (admin)
+func (c *Client) DeleteServiceAccountAPIKey(ctx context.Context, token string, id, keyID int64) error {
+    return c.doJSON(ctx, http.MethodDelete, serviceAccountPath(id)+"/api-keys/"+strconv.FormatInt(keyID, 10), token, nil, nil)
+}
+
+// ClientCredentialsToken meminta access token service account dengan grant
+// client_credentials. Scope kosong berarti semua scope role service account.
+func (c *Client) ClientCredentialsToken(ctx context.Context, clientID, clientSecret string, scopes ...string) (*TokenResponse, error) {
+    form := url.Values{"grant_type": {model.GrantTypeClientCredentials}}
+    if len(scopes) > 0 {
+        form.Set("scope", strings.Join(scopes, " "))
+    }
+    return c.requestToken(ctx, clientID, clientSecret, form)
+}
+
+// ExchangeToken menukar token pengguna menjadi token untuk audience lain
+// (token exchange, RFC 8693) atas nama service account clientID. Jenis token
+// yang kosong diisi urn:ietf:params:oauth:token-type:access_token.
+func (c *Client) ExchangeToken(ctx context.Context, clientID, clientSecret string, exchange TokenExchangeRequest) (*TokenResponse, error) {
+    form := url.Values{
+        "grant_type":         {model.GrantTypeTokenExchange},
+        "subject_token":      {exchange.SubjectToken},
+        "subject_token_type": {tokenTypeOrDefault(exchange.SubjectTokenType)},
+        "audience":           exchange.Audience,
+    }
+    if exchange.ActorToken != "" {
+        form.Set("actor_token", exchange.ActorToken)
+        form.Set("actor_token_type", tokenTypeOrDefault(exchange.ActorTokenType))
+    }
+    if exchange.Scope != "" {
+        form.Set("scope", exchange.Scope)
+    }
+    return c.requestToken(ctx, clientID, clientSecret, form)
+}
+
+// tokenTypeOrDefault jenis token default untuk token exchange
+func tokenTypeOrDefault(tokenType string) string {
+    if tokenType == "" {
+        return model.TokenTypeAccessToken
+    }
+    return tokenType
+}
+
+// requestToken mengirim form ke token endpoint dengan kredensial client
+// lewat HTTP Basic
+func (c *Client) requestToken(ctx context.Context, clientID, clientSecret string, form url.Values) (*TokenResponse, error) {
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL.String()+"/api/oauth/token", strings.NewReader(form.Encode()))
+    if err != nil {
+        return nil, err
+    }
+    req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+    req.Header.Set("Accept", "application/json")
+    if c.language != "" {
+        req.Header.Set("Accept-Language", c.language)
+    }
+    req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+
+    resp, err := c.send(req)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+
+    var token TokenResponse
+    if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+        return nil, fmt.Errorf("failed to decode response: %v", err)
+    }
+    return &token, nil
+}
+
+// Livez memeriksa liveness server
+func (c *Client) Livez(ctx context.Context) error {
+    return c.doJSON(ctx, http.MethodGet, "/livez", "", nil, nil)
+}
+
+// Readyz mengambil laporan readiness. Jika server belum siap, laporan tetap
+// dikembalikan bersama ErrNotReady.
+func (c *Client) Readyz(ctx context.Context) (*HealthReport, error) {
+    req, err := c.newRequest(ctx, http.MethodGet, "/readyz", "", nil)
+    if err != nil {
+        return nil, err
+    }
+    resp, err := c.httpClient.Do(req)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+
+    var report HealthReport
+    if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+        return nil, fmt.Errorf("failed to decode readiness report: %v", err)
+    }
+    if resp.StatusCode == http.StatusServiceUnavailable {
+        return &report, ErrNotReady
+    }
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("unexpected status %d from /readyz", resp.StatusCode)
+    }
+    return &report, nil
+}
+
+// JWKS mengambil public key penandatangan token
+func (c *Client) JWKS(ctx context.Context) (*JWKSet, error) {
+    var set JWKSet
+    if err := c.doJSON(ctx, http.MethodGet, "/.well-known/jwks.json", "", nil, &set); err != nil {
+        return nil, err
+    }
+    return &set, nil
+}
+
+// session menjalankan request yang menerbitkan token lewat cookie
+func (c *Client) session(ctx context.Context, method, path, token string, body interface{}) (*Session, error) {
+    req, err := c.newRequest(ctx, method, path, token, body)
+    if err != nil {
+        return nil, err
+    }
+    resp, err := c.send(req)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+
+    session := &Session{}
+    var payload struct {
+        Message string `json:"message"`
+        User    *User  `json:"user"`
+        Token   string `json:"token"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+        return nil, fmt.Errorf("failed to decode response: %v", err)
+    }
+    session.Message, session.User, session.Token = payload.Message, payload.User, payload.Token
+
+    for _, cookie := range resp.Cookies() {
+        if cookie.Name == c.cookieName && cookie.Value != "" {
+            session.Token = cookie.Value
+        }
+    }
+    if session.Token == "" {
+        return nil, fmt.Errorf("response has no %q cookie", c.cookieName)
+    }
+    return session, nil
+}
+
+// doJSON mengirim request dan men-decode body JSON ke out (jika tidak nil)
+func (c *Client) doJSON(ctx context.Context, method, path, token string, body, out interface{}) error {
+    req, err := c.newRequest(ctx, method, path, token, body)
+    if err != nil {
+        return err
+    }
+    resp, err := c.send(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    if out == nil {
+        return nil
+    }
+    if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+        return fmt.Errorf("failed to decode response: %v", err)
+    }
+    return nil
+}
+
+// newRequest membuat request dengan body JSON, token dan bahasa
+func (c *Client) newRequest(ctx context.Context, method, path, token string, body interface{}) (*http.Request, error) {
+    var reader io.Reader
+    if body != nil {
+        data, err := json.Marshal(body)
+        if err != nil {
+            return nil, fmt.Errorf("failed to encode request: %v", err)
+        }
+        reader = bytes.NewReader(data)
+    }
+
+    req, err := http.NewRequestWithContext(ctx, method, c.baseURL.String()+path, reader)
+    if err != nil {
+        return nil, err
+    }
+    if body != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+    req.Header.Set("Accept", "application/json")
+    if c.language != "" {
+        req.Header.Set("Accept-Language", c.language)
+    }
+    if strings.HasPrefix(token, model.APIKeyScheme+" ") {
+        req.Header.Set("Authorization", token)
+    } else if token != "" {
+        req.Header.Set("Authorization", "Bearer "+token)
+    }
+    return req, nil
+}
+
+// send menjalankan request; status >= 400 diubah menjadi *APIError
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+    resp, err := c.httpClient.Do(req)
+    if err != nil {
+        return nil, err
+    }
+    if resp.StatusCode < http.StatusBadRequest {
+        return resp, nil
+    }
+    defer resp.Body.Close()
+
+    apiErr := &APIError{StatusCode: resp.StatusCode}
+    data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+    if err := json.Unmarshal(data, &apiErr.Problem); err != nil || apiErr.Problem.Code == "" {
+        apiErr.Problem = Problem{Status: resp.StatusCode, Title: http.StatusText(resp.StatusCode)}
+        // Token endpoint memakai format error OAuth 2.0 (RFC 6749 5.2)
+        var oauthErr model.OAuthError
+        if json.Unmarshal(data, &oauthErr) == nil && oauthErr.Error != "" {
+            apiErr.Problem.Code = oauthErr.Error
+            apiErr.Problem.Detail = oauthErr.ErrorDescription
+        }
+    }
+    return nil, apiErr
+}
+
+// auditQuery membentuk query string filter audit
+func auditQuery(filter AuditEventFilter, paging bool) string {
+    query := url.Values{}
+    if filter.UserID != nil {
+        query.Set("user_id", strconv.FormatInt(*filter.UserID, 10))
+    }
+    if filter.ActorType != "" {
+        query.Set("actor_type", filter.ActorType)
+    }
+    if filter.EventType != "" {
+        query.Set("type", filter.EventType)
+    }
+    if filter.From != nil {
+        query.Set("from", filter.From.UTC().Format(time.RFC3339))
+    }
+    if filter.To != nil {
+        query.Set("to", filter.To.UTC().Format(time.RFC3339))
+    }
+    if paging && filter.Limit > 0 {
+        query.Set("limit", strconv.Itoa(filter.Limit))
+    }
+    if paging && filter.Offset > 0 {
+        query.Set("offset", strconv.Itoa(filter.Offset))
+    }
+    if len(query) == 0 {
+        return ""
+    }
+    return "?" + query.Encode()
+}
+
+// serviceAccountPath path admin satu service account
+func serviceAccountPath(id int64) string {
+    return "/api/admin/service-accounts/" + strconv.FormatInt(id, 10)
+}
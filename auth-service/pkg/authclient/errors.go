@@ -0,0 +1,85 @@
+package authclient
+
+import (
+	"auth-service/internal/model"
+	"fmt"
+)
+
+// Error yang dapat dicocokkan dengan errors.Is, baik dari *APIError
+// (berdasarkan kode Problem) maupun dari Verifier
+var (
+    ErrEmailTaken              = model.ErrEmailTaken
+    ErrInvalidCredentials      = model.ErrInvalidCredentials
+    ErrUserNotFound            = model.ErrUserNotFound
+    ErrInvalidRole             = model.ErrInvalidRole
+    ErrTokenMissing            = model.ErrTokenMissing
+    ErrTokenInvalid            = model.ErrTokenInvalid
+    ErrTokenExpired            = model.ErrTokenExpired
+    ErrTokenRevoked            = model.ErrTokenRevoked
+    ErrForbidden               = model.ErrForbidden
+    ErrAPIKeyNotFound          = model.ErrAPIKeyNotFound
+    ErrInvalidScope            = model.ErrInvalidScope
+    ErrAPIKeyLimitReached      = model.ErrAPIKeyLimitReached
+    ErrServiceAccountNotFound  = model.ErrServiceAccountNotFound
+    ErrServiceAccountNameTaken = model.ErrServiceAccountNameTaken
+    ErrInvalidClient           = model.ErrInvalidClient
+    ErrUnsupportedGrantType    = model.ErrUnsupportedGrantType
+    ErrInvalidGrant            = model.ErrInvalidGrant
+    ErrInvalidTarget           = model.ErrInvalidTarget
+    ErrUnauthorizedClient      = model.ErrUnauthorizedClient
+    ErrImpersonationNotAllowed = model.ErrImpersonationNotAllowed
+    ErrImpersonationRestricted = model.ErrImpersonationRestricted
+    ErrNotImpersonating        = model.ErrNotImpersonating
+)
+
+// problemErrors pemetaan kode Problem ke error sentinel
+var problemErrors = map[string]error{
+    "email_taken":                ErrEmailTaken,
+    "invalid_credentials":        ErrInvalidCredentials,
+    "user_not_found":             ErrUserNotFound,
+    "invalid_role":               ErrInvalidRole,
+    "authentication_required":    ErrTokenMissing,
+    "token_invalid":              ErrTokenInvalid,
+    "token_expired":              ErrTokenExpired,
+    "token_revoked":              ErrTokenRevoked,
+    "forbidden":                  ErrForbidden,
+    "api_key_not_found":          ErrAPIKeyNotFound,
+    "invalid_scope":              ErrInvalidScope,
+    "api_key_limit_reached":      ErrAPIKeyLimitReached,
+    "service_account_not_found":  ErrServiceAccountNotFound,
+    "service_account_name_taken": ErrServiceAccountNameTaken,
+    "invalid_client":             ErrInvalidClient,
+    "unsupported_grant_type":     ErrUnsupportedGrantType,
+    "invalid_grant":              ErrInvalidGrant,
+    "invalid_target":             ErrInvalidTarget,
+    "unauthorized_client":        ErrUnauthorizedClient,
+    "impersonation_not_allowed":  ErrImpersonationNotAllowed,
+    "impersonation_restricted":   ErrImpersonationRestricted,
+    "not_impersonating":          ErrNotImpersonating,
+}
+
+// APIError response error (application/problem+json) dari auth-service.
+// Error OAuth 2.0 dari token endpoint dipetakan ke Problem dengan Code berisi
+// kode error OAuth dan Detail berisi error_description.
+type APIError struct {
+    StatusCode int
+    Problem    Problem
+}
+
+func (e *APIError) Error() string {
+    message := e.Problem.Detail
+    if message == "" {
+        message = e.Problem.Title
+    }
+    if e.Problem.Code == "" {
+        return fmt.Sprintf("auth-service: %d %s", e.StatusCode, message)
+    }
+    return fmt.Sprintf("auth-service: %d %s: %s", e.StatusCode, e.Problem.Code, message)
+}
+
+// Is mencocokkan APIError dengan error sentinel sesuai kode Problem,
+// misalnya errors.Is(err, authclient.ErrEmailTaken)
+func (e *APIError) Is(target error) bool {
+    sentinel, ok := problemErrors[e.Problem.Code]
+    return ok && sentinel == target
+}
@@ -0,0 +1,130 @@
+package authclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GinClaimsKey key gin.Context tempat GinMiddleware menyimpan claims; sama
+// dengan yang dipakai auth-service sendiri
+const GinClaimsKey = "jwtClaims"
+
+type claimsKey struct{}
+
+// WithClaims menyimpan claims pemanggil ke context
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+    return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext mengambil claims yang disimpan middleware
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+    claims, ok := ctx.Value(claimsKey{}).(*Claims)
+    return claims, ok && claims != nil
+}
+
+// middlewareConfig pengaturan middleware
+type middlewareConfig struct {
+    cookieName string
+}
+
+// MiddlewareOption mengatur Middleware dan GinMiddleware
+type MiddlewareOption func(*middlewareConfig)
+
+// WithTokenCookie nama cookie tempat token dicari jika header Authorization
+// tidak ada (default "jwt"); string kosong menonaktifkan pencarian cookie
+func WithTokenCookie(name string) MiddlewareOption {
+    return func(cfg *middlewareConfig) {
+        cfg.cookieName = name
+    }
+}
+
+func newMiddlewareConfig(opts []MiddlewareOption) middlewareConfig {
+    cfg := middlewareConfig{cookieName: DefaultCookieName}
+    for _, opt := range opts {
+        opt(&cfg)
+    }
+    return cfg
+}
+
+// Middleware middleware net/http yang memverifikasi token dari header
+// Authorization (Bearer) atau cookie JWT. Request tanpa token valid ditolak
+// dengan 401 application/problem+json; claims pemanggil tersedia lewat
+// ClaimsFromContext.
+func Middleware(verifier TokenVerifier, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+    cfg := newMiddlewareConfig(opts)
+    return func(next http.Handler) http.Handler {
+        return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            claims, err := cfg.authenticate(r, verifier)
+            if err != nil {
+                problem := unauthorizedProblem(err, r.URL.Path)
+                w.Header().Set("Content-Type", "application/problem+json")
+                w.WriteHeader(problem.Status)
+                _ = json.NewEncoder(w).Encode(problem)
+                return
+            }
+            next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+        })
+    }
+}
+
+// GinMiddleware seperti Middleware untuk Gin. Claims juga disimpan di
+// gin.Context dengan key GinClaimsKey.
+func GinMiddleware(verifier TokenVerifier, opts ...MiddlewareOption) gin.HandlerFunc {
+    cfg := newMiddlewareConfig(opts)
+    return func(c *gin.Context) {
+        claims, err := cfg.authenticate(c.Request, verifier)
+        if err != nil {
+            problem := unauthorizedProblem(err, c.Request.URL.Path)
+            c.Header("Content-Type", "application/problem+json")
+            c.AbortWithStatusJSON(problem.Status, problem)
+            return
+        }
+        c.Set(GinClaimsKey, claims)
+        c.Request = c.Request.WithContext(WithClaims(c.Request.Context(), claims))
+        c.Next()
+    }
+}
+
+// authenticate mengambil token dari request dan memverifikasinya
+func (cfg middlewareConfig) authenticate(r *http.Request, verifier TokenVerifier) (*Claims, error) {
+    token := ""
+    if header := r.Header.Get("Authorization"); header != "" {
+        scheme, value, ok := strings.Cut(header, " ")
+        if !ok || !strings.EqualFold(scheme, "Bearer") {
+            return nil, ErrTokenInvalid
+        }
+        token = strings.TrimSpace(value)
+    } else if cfg.cookieName != "" {
+        if cookie, err := r.Cookie(cfg.cookieName); err == nil {
+            token = cookie.Value
+        }
+    }
+    if token == "" {
+        return nil, ErrTokenMissing
+    }
+    return verifier.Verify(r.Context(), token)
+}
+
+// unauthorizedProblem membentuk Problem 401 dengan kode yang sama seperti
+// response auth-service
+func unauthorizedProblem(err error, instance string) Problem {
+    code, title := "token_invalid", "Invalid token"
+    switch {
+    case errors.Is(err, ErrTokenMissing):
+        code, title = "authentication_required", "Authentication required"
+    case errors.Is(err, ErrTokenExpired):
+        code, title = "token_expired", "Token expired"
+    }
+    return Problem{
+        Type:     "urn:auth-service:problem:" + code,
+        Title:    title,
+        Status:   http.StatusUnauthorized,
+        Instance: instance,
+        Code:     code,
+    }
+}
@@ -0,0 +1,279 @@
+package authclient
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenVerifier memverifikasi token dan mengembalikan claims-nya. Dipenuhi
+// oleh *Verifier; implementasi lain dapat dipakai di middleware.
+type TokenVerifier interface {
+    Verify(ctx context.Context, token string) (*Claims, error)
+}
+
+// Verifier memverifikasi JWT auth-service secara lokal tanpa memanggil
+// server. Token yang dicabut lewat logout tidak terdeteksi; gunakan
+// Client.Validate jika hal itu penting.
+type Verifier struct {
+    issuer   string
+    audience string
+    methods  []string
+    leeway   time.Duration
+    keyfunc func(ctx context.Context, token *jwt.Token) (interface{}, error)
+
+    // Pengaturan JWKS
+    httpClient *http.Client
+    cacheTTL   time.Duration
+}
+
+// VerifierOption mengatur Verifier
+type VerifierOption func(*Verifier)
+
+// WithLeeway toleransi selisih jam saat memeriksa exp, iat dan nbf
+func WithLeeway(leeway time.Duration) VerifierOption {
+    return func(v *Verifier) {
+        v.leeway = leeway
+    }
+}
+
+// WithAudience mewajibkan token memiliki audience (claim aud), yaitu token
+// hasil token exchange yang ditujukan untuk layanan ini. Tanpa WithAudience,
+// token ber-aud selalu ditolak karena ditujukan untuk layanan lain.
+func WithAudience(audience string) VerifierOption {
+    return func(v *Verifier) {
+        v.audience = audience
+    }
+}
+
+// WithJWKSHTTPClient http.Client untuk mengambil JWKS
+func WithJWKSHTTPClient(httpClient *http.Client) VerifierOption {
+    return func(v *Verifier) {
+        v.httpClient = httpClient
+    }
+}
+
+// WithJWKSCacheTTL seberapa lama JWKS disimpan sebelum diambil ulang
+// (default 10 menit). Key ID yang belum dikenal selalu memicu pengambilan
+// ulang, dibatasi paling sering sekali per 30 detik.
+func WithJWKSCacheTTL(ttl time.Duration) VerifierOption {
+    return func(v *Verifier) {
+        v.cacheTTL = ttl
+    }
+}
+
+// NewHMACVerifier membuat Verifier untuk token HS256 dengan secret bersama
+// (jwt.secret server)
+func NewHMACVerifier(secret, issuer string, opts ...VerifierOption) *Verifier {
+    v := newVerifier(issuer, []string{"HS256"}, opts)
+    v.keyfunc = func(ctx context.Context, token *jwt.Token) (interface{}, error) {
+        if secret == "" {
+            return nil, errors.New("HMAC secret is empty")
+        }
+        return []byte(secret), nil
+    }
+    return v
+}
+
+// NewJWKSVerifier membuat Verifier untuk token RS256/EdDSA. Public key
+// diambil dari jwksURL (biasanya <base URL>/.well-known/jwks.json) dan
+// di-cache.
+func NewJWKSVerifier(jwksURL, issuer string, opts ...VerifierOption) *Verifier {
+    v := newVerifier(issuer, []string{"RS256", "EdDSA"}, opts)
+    cache := &jwksCache{url: jwksURL, httpClient: v.httpClient, ttl: v.cacheTTL, minRefresh: 30 * time.Second}
+    v.keyfunc = cache.keyfunc
+    return v
+}
+
+func newVerifier(issuer string, methods []string, opts []VerifierOption) *Verifier {
+    v := &Verifier{
+        issuer:     issuer,
+        methods:    methods,
+        leeway:     30 * time.Second,
+        httpClient: &http.Client{Timeout: 10 * time.Second},
+        cacheTTL:   10 * time.Minute,
+    }
+    for _, opt := range opts {
+        opt(v)
+    }
+    return v
+}
+
+// Verify memeriksa tanda tangan, issuer, audience (jika diatur dengan
+// WithAudience) dan masa berlaku token. Error dapat
+// dicocokkan dengan ErrTokenExpired atau ErrTokenInvalid.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+    if token == "" {
+        return nil, ErrTokenMissing
+    }
+
+    options := []jwt.ParserOption{
+        jwt.WithValidMethods(v.methods),
+        jwt.WithIssuer(v.issuer),
+        jwt.WithLeeway(v.leeway),
+        jwt.WithExpirationRequired(),
+    }
+    if v.audience != "" {
+        options = append(options, jwt.WithAudience(v.audience))
+    }
+
+    claims := &Claims{}
+    _, err := jwt.ParseWithClaims(token, claims,
+        func(t *jwt.Token) (interface{}, error) { return v.keyfunc(ctx, t) },
+        options...,
+    )
+    if err != nil {
+        if errors.Is(err, jwt.ErrTokenExpired) {
+            return nil, ErrTokenExpired
+        }
+        return nil, fmt.Errorf("%w: %w", ErrTokenInvalid, err)
+    }
+    if v.audience == "" && len(claims.Audience) > 0 {
+        return nil, fmt.Errorf("%w: token is intended for audience %v", ErrTokenInvalid, claims.Audience)
+    }
+    return claims, nil
+}
+
+// jwksCache cache public key dari endpoint JWKS per key ID. Lookup hanya
+// memakai read lock; refresh dijalankan satu per satu tanpa memegang lock
+// selama request HTTP, dan pemanggil lain menunggu hasil refresh tersebut.
+type jwksCache struct {
+    url        string
+    httpClient *http.Client
+    ttl        time.Duration
+    minRefresh time.Duration
+
+    mu         sync.RWMutex
+    keys       map[string]cachedKey
+    fetchedAt  time.Time
+    refreshing chan struct{}
+    refreshErr error
+}
+
+type cachedKey struct {
+    alg string
+    key crypto.PublicKey
+}
+
+// keyfunc memilih public key berdasarkan header kid token
+func (c *jwksCache) keyfunc(ctx context.Context, token *jwt.Token) (interface{}, error) {
+    kid, _ := token.Header["kid"].(string)
+    if kid == "" {
+        return nil, errors.New("token has no kid header")
+    }
+
+    c.mu.RLock()
+    key, found := c.keys[kid]
+    stale := c.stale(kid)
+    c.mu.RUnlock()
+
+    if stale {
+        if err := c.refresh(ctx, kid); err != nil {
+            // Key lama tetap dipakai jika server JWKS sementara tidak dapat dijangkau
+            if !found {
+                return nil, err
+            }
+        } else {
+            c.mu.RLock()
+            key, found = c.keys[kid]
+            c.mu.RUnlock()
+        }
+    }
+    if !found {
+        return nil, fmt.Errorf("unknown key ID %q", kid)
+    }
+    if key.alg != "" && key.alg != token.Method.Alg() {
+        return nil, fmt.Errorf("key %q is not valid for algorithm %s", kid, token.Method.Alg())
+    }
+    return key.key, nil
+}
+
+// stale melaporkan apakah JWKS perlu diambil ulang untuk kid; dipanggil
+// dengan mu terkunci
+func (c *jwksCache) stale(kid string) bool {
+    _, found := c.keys[kid]
+    age := time.Since(c.fetchedAt)
+    return age > c.ttl || (!found && age > c.minRefresh)
+}
+
+// refresh mengambil ulang JWKS. Hanya satu fetch yang berjalan pada satu
+// waktu; pemanggil yang datang selama fetch berlangsung menunggu dan
+// memakai hasilnya.
+func (c *jwksCache) refresh(ctx context.Context, kid string) error {
+    c.mu.Lock()
+    if done := c.refreshing; done != nil {
+        c.mu.Unlock()
+        select {
+        case <-done:
+        case <-ctx.Done():
+            return ctx.Err()
+        }
+        c.mu.RLock()
+        defer c.mu.RUnlock()
+        return c.refreshErr
+    }
+    // Refresh lain mungkin sudah selesai sejak pemanggil memeriksa cache
+    if !c.stale(kid) {
+        c.mu.Unlock()
+        return nil
+    }
+    done := make(chan struct{})
+    c.refreshing = done
+    c.mu.Unlock()
+
+    keys, err := c.fetch(ctx)
+
+    // fetchedAt diisi setelah fetch selesai, termasuk saat gagal, agar
+    // pemanggil yang datang selama fetch ikut menunggu hasilnya
+    c.mu.Lock()
+    if err == nil {
+        c.keys = keys
+    }
+    c.fetchedAt = time.Now()
+    c.refreshErr = err
+    c.refreshing = nil
+    c.mu.Unlock()
+    close(done)
+    return err
+}
+
+// fetch mengunduh JWKS dan mengubahnya menjadi map key ID ke public key
+func (c *jwksCache) fetch(ctx context.Context) (map[string]cachedKey, error) {
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+    if err != nil {
+        return nil, err
+    }
+    resp, err := c.httpClient.Do(req)
+    if err != nil {
+        return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+    }
+
+    var set JWKSet
+    if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+        return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+    }
+
+    keys := make(map[string]cachedKey, len(set.Keys))
+    for _, jwk := range set.Keys {
+        if jwk.Use != "" && jwk.Use != "sig" {
+            continue
+        }
+        pub, err := jwk.PublicKey()
+        if err != nil {
+            continue
+        }
+        keys[jwk.Kid] = cachedKey{alg: jwk.Alg, key: pub}
+    }
+    return keys, nil
+}
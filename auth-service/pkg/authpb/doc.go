@@ -0,0 +1,6 @@
+// Package authpb berisi kode hasil generate dari proto/auth/v1/auth.proto
+// untuk server dan klien gRPC auth-service. Jangan mengubah file *.pb.go
+// secara manual; jalankan go generate setelah mengubah file .proto.
+package authpb
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=auth-service --go-grpc_out=../.. --go-grpc_opt=module=auth-service auth/v1/auth.proto